@@ -12,9 +12,12 @@ type Config struct {
 
 // WebConfig is container for web ui configuration parameters
 type WebConfig struct {
-	SupportEmail  string       `json:"supportEmail,omitempty"`
-	RemoteAllowed bool         `json:"remoteAllowed"`
-	Contacts      []WebContact `json:"contacts"`
+	SupportEmail        string                  `json:"supportEmail,omitempty"`
+	RemoteAllowed       bool                    `json:"remoteAllowed"`
+	PrometheusAllowed   bool                    `json:"prometheusAllowed"`
+	RemoteClusters      []string                `json:"remoteClusters"`
+	Contacts            []WebContact            `json:"contacts"`
+	ExpressionFunctions []WebExpressionFunction `json:"expressionFunctions"`
 }
 
 // WebContact is container for web ui contact validation
@@ -25,3 +28,10 @@ type WebContact struct {
 	Placeholder     string `json:"placeholder,omitempty"`
 	Help            string `json:"help,omitempty"`
 }
+
+// WebExpressionFunction is container for web ui hints of functions available in trigger expressions
+type WebExpressionFunction struct {
+	Name        string `json:"name"`
+	Signature   string `json:"signature"`
+	Description string `json:"description"`
+}
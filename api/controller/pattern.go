@@ -1,11 +1,19 @@
 package controller
 
 import (
+	"fmt"
+	"time"
+
 	"github.com/moira-alert/moira"
 	"github.com/moira-alert/moira/api"
 	"github.com/moira-alert/moira/api/dto"
+	"gopkg.in/tomb.v2"
 )
 
+// MetricTapRenewPeriod is period to renew pattern tap while samples are streamed,
+// tap expires after double period if client is gone without unsubscribing
+const MetricTapRenewPeriod = 10 * time.Second
+
 // GetAllPatterns get all patterns and triggers and metrics info corresponding to this pattern
 func GetAllPatterns(database moira.Database, logger moira.Logger) (*dto.PatternList, *api.ErrorResponse) {
 	patterns, err := database.GetPatterns()
@@ -58,6 +66,40 @@ func GetAllPatterns(database moira.Database, logger moira.Logger) (*dto.PatternL
 	return &pattersList, nil
 }
 
+// GetIngestionLimitViolations gets patterns and metric prefixes which exceeded filter ingestion limits
+func GetIngestionLimitViolations(database moira.Database) (*dto.IngestionLimitViolationList, *api.ErrorResponse) {
+	violations, err := database.GetIngestionLimitViolations()
+	if err != nil {
+		return nil, api.ErrorInternalServer(err)
+	}
+	violationList := &dto.IngestionLimitViolationList{
+		List: make([]moira.IngestionLimitViolation, 0, len(violations)),
+	}
+	for _, violation := range violations {
+		if violation != nil {
+			violationList.List = append(violationList.List, *violation)
+		}
+	}
+	return violationList, nil
+}
+
+// GetRejectedTimestampSamples gets last points received by filter with timestamps out of acceptance window
+func GetRejectedTimestampSamples(database moira.Database) (*dto.RejectedTimestampSampleList, *api.ErrorResponse) {
+	samples, err := database.GetRejectedTimestampSamples()
+	if err != nil {
+		return nil, api.ErrorInternalServer(err)
+	}
+	sampleList := &dto.RejectedTimestampSampleList{
+		List: make([]moira.RejectedTimestampSample, 0, len(samples)),
+	}
+	for _, sample := range samples {
+		if sample != nil {
+			sampleList.List = append(sampleList.List, *sample)
+		}
+	}
+	return sampleList, nil
+}
+
 // DeletePattern deletes trigger pattern
 func DeletePattern(database moira.Database, pattern string) *api.ErrorResponse {
 	if err := database.RemovePattern(pattern); err != nil {
@@ -65,3 +107,32 @@ func DeletePattern(database moira.Database, pattern string) *api.ErrorResponse {
 	}
 	return nil
 }
+
+// TapPattern makes filter publish samples of metrics matched by pattern and subscribes to them until subscriptionTomb is killed
+// Only patterns of existing triggers can be tapped
+func TapPattern(database moira.Database, pattern string, subscriptionTomb *tomb.Tomb) (<-chan *moira.MetricTapSample, *api.ErrorResponse) {
+	triggerIDs, err := database.GetPatternTriggerIDs(pattern)
+	if err != nil {
+		return nil, api.ErrorInternalServer(err)
+	}
+	if len(triggerIDs) == 0 {
+		return nil, api.ErrorNotFound(fmt.Sprintf("pattern %s is not used by any trigger", pattern))
+	}
+	samples, err := database.SubscribeMetricTap(subscriptionTomb, pattern)
+	if err != nil {
+		return nil, api.ErrorInternalServer(err)
+	}
+	if errorResponse := RenewPatternTap(database, pattern); errorResponse != nil {
+		subscriptionTomb.Kill(nil)
+		return nil, errorResponse
+	}
+	return samples, nil
+}
+
+// RenewPatternTap prolongs pattern tap for double MetricTapRenewPeriod
+func RenewPatternTap(database moira.Database, pattern string) *api.ErrorResponse {
+	if err := database.AddMetricTap(pattern, time.Now().Add(2*MetricTapRenewPeriod).Unix()); err != nil {
+		return api.ErrorInternalServer(err)
+	}
+	return nil
+}
@@ -12,6 +12,7 @@ import (
 	logging "github.com/moira-alert/moira/logging/zerolog_adapter"
 	mock_moira_alert "github.com/moira-alert/moira/mock/moira-alert"
 	. "github.com/smartystreets/goconvey/convey"
+	"gopkg.in/tomb.v2"
 )
 
 func TestDeletePattern(t *testing.T) {
@@ -33,6 +34,116 @@ func TestDeletePattern(t *testing.T) {
 	})
 }
 
+func TestGetIngestionLimitViolations(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	dataBase := mock_moira_alert.NewMockDatabase(mockCtrl)
+
+	Convey("Success", t, func() {
+		violations := []*moira.IngestionLimitViolation{
+			{Pattern: "my.first.pattern", Limit: 100, Dropped: 10, Timestamp: 1234567890},
+			{Prefix: "my", Limit: 1000, Dropped: 5, Timestamp: 1234567890},
+		}
+		dataBase.EXPECT().GetIngestionLimitViolations().Return(violations, nil)
+		list, err := GetIngestionLimitViolations(dataBase)
+		So(err, ShouldBeNil)
+		So(list, ShouldResemble, &dto.IngestionLimitViolationList{
+			List: []moira.IngestionLimitViolation{*violations[0], *violations[1]},
+		})
+	})
+
+	Convey("Error", t, func() {
+		expected := fmt.Errorf("oooops! Can not get violations")
+		dataBase.EXPECT().GetIngestionLimitViolations().Return(nil, expected)
+		list, err := GetIngestionLimitViolations(dataBase)
+		So(list, ShouldBeNil)
+		So(err, ShouldResemble, api.ErrorInternalServer(expected))
+	})
+}
+
+func TestGetRejectedTimestampSamples(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	dataBase := mock_moira_alert.NewMockDatabase(mockCtrl)
+
+	Convey("Success", t, func() {
+		samples := []*moira.RejectedTimestampSample{
+			{Reason: "outdated", Action: "drop", Sample: "my.metric 1 100", Count: 10, Timestamp: 1234567890},
+		}
+		dataBase.EXPECT().GetRejectedTimestampSamples().Return(samples, nil)
+		list, err := GetRejectedTimestampSamples(dataBase)
+		So(err, ShouldBeNil)
+		So(list, ShouldResemble, &dto.RejectedTimestampSampleList{
+			List: []moira.RejectedTimestampSample{*samples[0]},
+		})
+	})
+
+	Convey("Error", t, func() {
+		expected := fmt.Errorf("oooops! Can not get samples")
+		dataBase.EXPECT().GetRejectedTimestampSamples().Return(nil, expected)
+		list, err := GetRejectedTimestampSamples(dataBase)
+		So(list, ShouldBeNil)
+		So(err, ShouldResemble, api.ErrorInternalServer(expected))
+	})
+}
+
+func TestTapPattern(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	dataBase := mock_moira_alert.NewMockDatabase(mockCtrl)
+	pattern := "super.puper.pattern"
+
+	Convey("Success", t, func() {
+		var subscriptionTomb tomb.Tomb
+		samples := make(<-chan *moira.MetricTapSample)
+		dataBase.EXPECT().GetPatternTriggerIDs(pattern).Return([]string{"trigger-id"}, nil)
+		dataBase.EXPECT().SubscribeMetricTap(&subscriptionTomb, pattern).Return(samples, nil)
+		dataBase.EXPECT().AddMetricTap(pattern, gomock.Any()).Return(nil)
+		result, err := TapPattern(dataBase, pattern, &subscriptionTomb)
+		So(err, ShouldBeNil)
+		So(result, ShouldEqual, samples)
+	})
+
+	Convey("Unknown pattern", t, func() {
+		var subscriptionTomb tomb.Tomb
+		dataBase.EXPECT().GetPatternTriggerIDs(pattern).Return([]string{}, nil)
+		result, err := TapPattern(dataBase, pattern, &subscriptionTomb)
+		So(result, ShouldBeNil)
+		So(err, ShouldResemble, api.ErrorNotFound("pattern super.puper.pattern is not used by any trigger"))
+	})
+
+	Convey("Get pattern triggers error", t, func() {
+		var subscriptionTomb tomb.Tomb
+		expected := fmt.Errorf("oooops! Can not get triggers")
+		dataBase.EXPECT().GetPatternTriggerIDs(pattern).Return(nil, expected)
+		result, err := TapPattern(dataBase, pattern, &subscriptionTomb)
+		So(result, ShouldBeNil)
+		So(err, ShouldResemble, api.ErrorInternalServer(expected))
+	})
+
+	Convey("Subscription error", t, func() {
+		var subscriptionTomb tomb.Tomb
+		expected := fmt.Errorf("oooops! Can not subscribe")
+		dataBase.EXPECT().GetPatternTriggerIDs(pattern).Return([]string{"trigger-id"}, nil)
+		dataBase.EXPECT().SubscribeMetricTap(&subscriptionTomb, pattern).Return(nil, expected)
+		result, err := TapPattern(dataBase, pattern, &subscriptionTomb)
+		So(result, ShouldBeNil)
+		So(err, ShouldResemble, api.ErrorInternalServer(expected))
+	})
+
+	Convey("Tap error should stop subscription", t, func() {
+		var subscriptionTomb tomb.Tomb
+		expected := fmt.Errorf("oooops! Can not add tap")
+		dataBase.EXPECT().GetPatternTriggerIDs(pattern).Return([]string{"trigger-id"}, nil)
+		dataBase.EXPECT().SubscribeMetricTap(&subscriptionTomb, pattern).Return(make(<-chan *moira.MetricTapSample), nil)
+		dataBase.EXPECT().AddMetricTap(pattern, gomock.Any()).Return(expected)
+		result, err := TapPattern(dataBase, pattern, &subscriptionTomb)
+		So(result, ShouldBeNil)
+		So(err, ShouldResemble, api.ErrorInternalServer(expected))
+		So(subscriptionTomb.Alive(), ShouldBeFalse)
+	})
+}
+
 func TestGetAllPatterns(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	dataBase := mock_moira_alert.NewMockDatabase(mockCtrl)
@@ -25,6 +25,9 @@ func UpdateTrigger(dataBase moira.Database, trigger *dto.TriggerModel, triggerID
 
 // saveTrigger create or update trigger data and update trigger metrics in last state
 func saveTrigger(dataBase moira.Database, trigger *moira.Trigger, triggerID string, timeSeriesNames map[string]bool) (*dto.SaveTriggerResponse, *api.ErrorResponse) {
+	if err := checkTriggerParents(dataBase, triggerID, trigger.Parents); err != nil {
+		return nil, err
+	}
 	if err := dataBase.AcquireTriggerCheckLock(triggerID, 10); err != nil {
 		return nil, api.ErrorInternalServer(err)
 	}
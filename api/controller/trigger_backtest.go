@@ -0,0 +1,57 @@
+package controller
+
+import (
+	"fmt"
+
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/api"
+	"github.com/moira-alert/moira/api/dto"
+	"github.com/moira-alert/moira/checker"
+	metricSource "github.com/moira-alert/moira/metric_source"
+	"github.com/moira-alert/moira/metric_source/remote"
+)
+
+// maxBacktestChecks limits number of checks replayed by one backtest request
+const maxBacktestChecks = 1440
+
+// BacktestTrigger replays checks of trigger every step seconds from from until to over historical data
+// and returns resulting timeline of trigger states and events without saving or sending them.
+// Beginning of time range is moved forward to oldest point kept by trigger metric source
+func BacktestTrigger(dataBase moira.Database, logger moira.Logger, metricSourceProvider *metricSource.SourceProvider, trigger *moira.Trigger, from, to, step int64) (*dto.TriggerBacktest, *api.ErrorResponse) {
+	if step <= 0 {
+		return nil, api.ErrorInvalidRequest(fmt.Errorf("step must be positive"))
+	}
+	if from >= to {
+		return nil, api.ErrorInvalidRequest(fmt.Errorf("from must be less than to"))
+	}
+
+	source, err := metricSourceProvider.GetTriggerMetricSource(trigger)
+	if err != nil {
+		return nil, api.ErrorInternalServer(err)
+	}
+	var message string
+	if metricsTTL := source.GetMetricsTTLSeconds(); from < to-metricsTTL {
+		from = to - metricsTTL
+		message = fmt.Sprintf("from is moved to %d, metric source keeps only %d seconds of history", from, metricsTTL)
+	}
+	if (to-from)/step > maxBacktestChecks {
+		return nil, api.ErrorInvalidRequest(fmt.Errorf("backtest can replay at most %d checks, increase step or reduce time range", maxBacktestChecks))
+	}
+
+	result, err := checker.Backtest(trigger, from, to, step, dataBase, logger, metricSourceProvider)
+	if err != nil {
+		switch err.(type) {
+		case remote.ErrRemoteTriggerResponse:
+			return nil, api.ErrorRemoteServerUnavailable(err)
+		default:
+			return nil, api.ErrorInternalServer(err)
+		}
+	}
+	return &dto.TriggerBacktest{
+		From:    from,
+		To:      to,
+		Message: message,
+		Checks:  result.Checks,
+		Events:  result.Events,
+	}, nil
+}
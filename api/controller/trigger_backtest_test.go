@@ -0,0 +1,84 @@
+package controller
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/api"
+	"github.com/moira-alert/moira/api/dto"
+	logging "github.com/moira-alert/moira/logging/zerolog_adapter"
+	metricSource "github.com/moira-alert/moira/metric_source"
+	mock_metric_source "github.com/moira-alert/moira/mock/metric_source"
+	mock_moira_alert "github.com/moira-alert/moira/mock/moira-alert"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestBacktestTrigger(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	dataBase := mock_moira_alert.NewMockDatabase(mockCtrl)
+	localSource := mock_metric_source.NewMockMetricSource(mockCtrl)
+	remoteSource := mock_metric_source.NewMockMetricSource(mockCtrl)
+	fetchResult := mock_metric_source.NewMockFetchResult(mockCtrl)
+	sourceProvider := metricSource.CreateMetricSourceProvider(localSource, remoteSource, nil)
+	logger, _ := logging.GetLogger("Test")
+	expression := "OK"
+	trigger := &moira.Trigger{ID: "triggerID", Targets: []string{"super.puper.pattern"}, TriggerType: moira.ExpressionTrigger, Expression: &expression}
+
+	Convey("Invalid parameters", t, func() {
+		response, err := BacktestTrigger(dataBase, logger, sourceProvider, trigger, 0, 600, 0)
+		So(err, ShouldResemble, api.ErrorInvalidRequest(fmt.Errorf("step must be positive")))
+		So(response, ShouldBeNil)
+
+		response, err = BacktestTrigger(dataBase, logger, sourceProvider, trigger, 600, 600, 60)
+		So(err, ShouldResemble, api.ErrorInvalidRequest(fmt.Errorf("from must be less than to")))
+		So(response, ShouldBeNil)
+
+		localSource.EXPECT().IsConfigured().Return(true, nil)
+		localSource.EXPECT().GetMetricsTTLSeconds().Return(int64(7 * 86400))
+		response, err = BacktestTrigger(dataBase, logger, sourceProvider, trigger, 0, 60*maxBacktestChecks+60, 60)
+		So(err, ShouldResemble, api.ErrorInvalidRequest(fmt.Errorf("backtest can replay at most %d checks, increase step or reduce time range", maxBacktestChecks)))
+		So(response, ShouldBeNil)
+	})
+
+	Convey("Backtest of trigger without metrics", t, func() {
+		localSource.EXPECT().IsConfigured().Return(true, nil).Times(2)
+		localSource.EXPECT().GetMetricsTTLSeconds().Return(int64(3600))
+		localSource.EXPECT().Fetch("super.puper.pattern", gomock.Any(), gomock.Any(), true).Return(fetchResult, nil).Times(2)
+		fetchResult.EXPECT().GetMetricsData().Return([]metricSource.MetricData{}).Times(2)
+		fetchResult.EXPECT().GetPatternMetrics().Return([]string{}, nil).Times(2)
+
+		response, err := BacktestTrigger(dataBase, logger, sourceProvider, trigger, 0, 120, 60)
+		So(err, ShouldBeNil)
+		So(response, ShouldResemble, &dto.TriggerBacktest{
+			From: 0,
+			To:   120,
+			Checks: []moira.CheckData{
+				{Metrics: map[string]moira.MetricState{}, MetricsToTargetRelation: map[string]string{}, State: moira.StateNODATA, Score: 1000, Timestamp: 60, Message: "target tt1 has no metrics"},
+				{Metrics: map[string]moira.MetricState{}, MetricsToTargetRelation: map[string]string{}, State: moira.StateNODATA, Score: 1000, Timestamp: 120, Message: "target tt1 has no metrics"},
+			},
+			Events: []moira.NotificationEvent{},
+		})
+	})
+	Convey("Backtest of time range longer than metrics history", t, func() {
+		localSource.EXPECT().IsConfigured().Return(true, nil).Times(2)
+		localSource.EXPECT().GetMetricsTTLSeconds().Return(int64(60))
+		localSource.EXPECT().Fetch("super.puper.pattern", int64(-540), int64(120), true).Return(fetchResult, nil)
+		fetchResult.EXPECT().GetMetricsData().Return([]metricSource.MetricData{})
+		fetchResult.EXPECT().GetPatternMetrics().Return([]string{}, nil)
+
+		response, err := BacktestTrigger(dataBase, logger, sourceProvider, trigger, 0, 120, 60)
+		So(err, ShouldBeNil)
+		So(response, ShouldResemble, &dto.TriggerBacktest{
+			From:    60,
+			To:      120,
+			Message: "from is moved to 60, metric source keeps only 60 seconds of history",
+			Checks: []moira.CheckData{
+				{Metrics: map[string]moira.MetricState{}, MetricsToTargetRelation: map[string]string{}, State: moira.StateNODATA, Score: 1000, Timestamp: 120, Message: "target tt1 has no metrics"},
+			},
+			Events: []moira.NotificationEvent{},
+		})
+	})
+}
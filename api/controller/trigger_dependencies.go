@@ -0,0 +1,142 @@
+package controller
+
+import (
+	"fmt"
+
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/api"
+	"github.com/moira-alert/moira/api/dto"
+	"github.com/moira-alert/moira/database"
+)
+
+// checkTriggerParents checks that all parent triggers exist and trigger is not an ancestor of any of them
+func checkTriggerParents(dataBase moira.Database, triggerID string, parents []string) *api.ErrorResponse {
+	if len(parents) == 0 {
+		return nil
+	}
+	parentTriggers, err := dataBase.GetTriggers(parents)
+	if err != nil {
+		return api.ErrorInternalServer(err)
+	}
+	for i, parentTrigger := range parentTriggers {
+		if parentTrigger == nil {
+			return api.ErrorInvalidRequest(fmt.Errorf("parent trigger with ID = '%s' does not exists", parents[i]))
+		}
+	}
+
+	visited := make(map[string]bool)
+	levelIDs, levelTriggers := parents, parentTriggers
+	for len(levelIDs) > 0 {
+		nextLevelIDs := make([]string, 0)
+		for i, ancestor := range levelTriggers {
+			if levelIDs[i] == triggerID {
+				return api.ErrorInvalidRequest(fmt.Errorf("parent triggers make dependency cycle: trigger '%s' depends on itself", triggerID))
+			}
+			if ancestor == nil {
+				continue
+			}
+			for _, parentID := range ancestor.Parents {
+				if !visited[parentID] {
+					visited[parentID] = true
+					nextLevelIDs = append(nextLevelIDs, parentID)
+				}
+			}
+		}
+		if len(nextLevelIDs) == 0 {
+			break
+		}
+		if levelTriggers, err = dataBase.GetTriggers(nextLevelIDs); err != nil {
+			return api.ErrorInternalServer(err)
+		}
+		levelIDs = nextLevelIDs
+	}
+	return nil
+}
+
+// GetTriggerDependencies gets dependency graph of trigger containing all its ancestors and descendants.
+// Parent triggers which do not exist are omitted
+func GetTriggerDependencies(dataBase moira.Database, triggerID string) (*dto.TriggerDependencies, *api.ErrorResponse) {
+	if _, err := dataBase.GetTrigger(triggerID); err != nil {
+		if err == database.ErrNil {
+			return nil, api.ErrorNotFound("trigger not found")
+		}
+		return nil, api.ErrorInternalServer(err)
+	}
+
+	edges := make([]dto.TriggerDependencyEdge, 0)
+	graphIDs := []string{triggerID}
+
+	// Ancestors are found by parents declared in triggers
+	visited := map[string]bool{triggerID: true}
+	levelIDs := []string{triggerID}
+	for len(levelIDs) > 0 {
+		levelTriggers, err := dataBase.GetTriggers(levelIDs)
+		if err != nil {
+			return nil, api.ErrorInternalServer(err)
+		}
+		nextLevelIDs := make([]string, 0)
+		for i, trigger := range levelTriggers {
+			if trigger == nil {
+				continue
+			}
+			for _, parentID := range trigger.Parents {
+				edges = append(edges, dto.TriggerDependencyEdge{Parent: parentID, Child: levelIDs[i]})
+				if !visited[parentID] {
+					visited[parentID] = true
+					nextLevelIDs = append(nextLevelIDs, parentID)
+				}
+			}
+		}
+		graphIDs = append(graphIDs, nextLevelIDs...)
+		levelIDs = nextLevelIDs
+	}
+
+	// Descendants are found by children index
+	visited = map[string]bool{triggerID: true}
+	levelIDs = []string{triggerID}
+	for len(levelIDs) > 0 {
+		nextLevelIDs := make([]string, 0)
+		for _, id := range levelIDs {
+			childIDs, err := dataBase.GetChildTriggerIDs(id)
+			if err != nil {
+				return nil, api.ErrorInternalServer(err)
+			}
+			for _, childID := range childIDs {
+				edges = append(edges, dto.TriggerDependencyEdge{Parent: id, Child: childID})
+				if !visited[childID] {
+					visited[childID] = true
+					nextLevelIDs = append(nextLevelIDs, childID)
+				}
+			}
+		}
+		graphIDs = append(graphIDs, nextLevelIDs...)
+		levelIDs = nextLevelIDs
+	}
+
+	triggerChecks, err := dataBase.GetTriggerChecks(graphIDs)
+	if err != nil {
+		return nil, api.ErrorInternalServer(err)
+	}
+	dependencies := &dto.TriggerDependencies{
+		Nodes: make([]dto.TriggerDependencyNode, 0, len(triggerChecks)),
+		Edges: make([]dto.TriggerDependencyEdge, 0, len(edges)),
+	}
+	existing := make(map[string]bool, len(triggerChecks))
+	for i, triggerCheck := range triggerChecks {
+		if triggerCheck == nil {
+			continue
+		}
+		existing[graphIDs[i]] = true
+		dependencies.Nodes = append(dependencies.Nodes, dto.TriggerDependencyNode{
+			ID:    graphIDs[i],
+			Name:  triggerCheck.Name,
+			State: triggerCheck.LastCheck.State,
+		})
+	}
+	for _, edge := range edges {
+		if existing[edge.Parent] && existing[edge.Child] {
+			dependencies.Edges = append(dependencies.Edges, edge)
+		}
+	}
+	return dependencies, nil
+}
@@ -0,0 +1,106 @@
+package controller
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/api"
+	"github.com/moira-alert/moira/api/dto"
+	"github.com/moira-alert/moira/database"
+	mock_moira_alert "github.com/moira-alert/moira/mock/moira-alert"
+)
+
+func TestCheckTriggerParents(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	dataBase := mock_moira_alert.NewMockDatabase(mockCtrl)
+
+	Convey("No parents", t, func() {
+		So(checkTriggerParents(dataBase, "child", nil), ShouldBeNil)
+	})
+
+	Convey("Parent does not exist", t, func() {
+		dataBase.EXPECT().GetTriggers([]string{"parent"}).Return([]*moira.Trigger{nil}, nil)
+		err := checkTriggerParents(dataBase, "child", []string{"parent"})
+		So(err, ShouldResemble, api.ErrorInvalidRequest(fmt.Errorf("parent trigger with ID = 'parent' does not exists")))
+	})
+
+	Convey("Trigger depends on itself", t, func() {
+		dataBase.EXPECT().GetTriggers([]string{"child"}).Return([]*moira.Trigger{{ID: "child"}}, nil)
+		err := checkTriggerParents(dataBase, "child", []string{"child"})
+		So(err, ShouldResemble, api.ErrorInvalidRequest(fmt.Errorf("parent triggers make dependency cycle: trigger 'child' depends on itself")))
+	})
+
+	Convey("Trigger is ancestor of parent", t, func() {
+		dataBase.EXPECT().GetTriggers([]string{"parent"}).Return([]*moira.Trigger{{ID: "parent", Parents: []string{"grandparent"}}}, nil)
+		dataBase.EXPECT().GetTriggers([]string{"grandparent"}).Return([]*moira.Trigger{{ID: "grandparent", Parents: []string{"child"}}}, nil)
+		dataBase.EXPECT().GetTriggers([]string{"child"}).Return([]*moira.Trigger{{ID: "child"}}, nil)
+		err := checkTriggerParents(dataBase, "child", []string{"parent"})
+		So(err, ShouldResemble, api.ErrorInvalidRequest(fmt.Errorf("parent triggers make dependency cycle: trigger 'child' depends on itself")))
+	})
+
+	Convey("Parents without cycle", t, func() {
+		dataBase.EXPECT().GetTriggers([]string{"parent1", "parent2"}).Return([]*moira.Trigger{
+			{ID: "parent1", Parents: []string{"grandparent"}},
+			{ID: "parent2", Parents: []string{"grandparent"}},
+		}, nil)
+		dataBase.EXPECT().GetTriggers([]string{"grandparent"}).Return([]*moira.Trigger{{ID: "grandparent"}}, nil)
+		So(checkTriggerParents(dataBase, "child", []string{"parent1", "parent2"}), ShouldBeNil)
+	})
+
+	Convey("Database error", t, func() {
+		expected := fmt.Errorf("oooops! Can not get triggers")
+		dataBase.EXPECT().GetTriggers([]string{"parent"}).Return(nil, expected)
+		err := checkTriggerParents(dataBase, "child", []string{"parent"})
+		So(err, ShouldResemble, api.ErrorInternalServer(expected))
+	})
+}
+
+func TestGetTriggerDependencies(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	dataBase := mock_moira_alert.NewMockDatabase(mockCtrl)
+
+	Convey("Trigger not found", t, func() {
+		dataBase.EXPECT().GetTrigger("trigger").Return(moira.Trigger{}, database.ErrNil)
+		dependencies, err := GetTriggerDependencies(dataBase, "trigger")
+		So(err, ShouldResemble, api.ErrorNotFound("trigger not found"))
+		So(dependencies, ShouldBeNil)
+	})
+
+	Convey("Ancestors and descendants", t, func() {
+		trigger := moira.Trigger{ID: "trigger", Name: "Trigger", Parents: []string{"parent", "removed"}}
+		parent := moira.Trigger{ID: "parent", Name: "Parent"}
+		child := moira.Trigger{ID: "child", Name: "Child", Parents: []string{"trigger"}}
+
+		dataBase.EXPECT().GetTrigger("trigger").Return(trigger, nil)
+		dataBase.EXPECT().GetTriggers([]string{"trigger"}).Return([]*moira.Trigger{&trigger}, nil)
+		dataBase.EXPECT().GetTriggers([]string{"parent", "removed"}).Return([]*moira.Trigger{&parent, nil}, nil)
+		dataBase.EXPECT().GetChildTriggerIDs("trigger").Return([]string{"child"}, nil)
+		dataBase.EXPECT().GetChildTriggerIDs("child").Return([]string{}, nil)
+		dataBase.EXPECT().GetTriggerChecks([]string{"trigger", "parent", "removed", "child"}).Return([]*moira.TriggerCheck{
+			{Trigger: trigger, LastCheck: moira.CheckData{State: moira.StateOK}},
+			{Trigger: parent, LastCheck: moira.CheckData{State: moira.StateERROR}},
+			nil,
+			{Trigger: child, LastCheck: moira.CheckData{State: moira.StateNODATA}},
+		}, nil)
+
+		dependencies, err := GetTriggerDependencies(dataBase, "trigger")
+		So(err, ShouldBeNil)
+		So(dependencies, ShouldResemble, &dto.TriggerDependencies{
+			Nodes: []dto.TriggerDependencyNode{
+				{ID: "trigger", Name: "Trigger", State: moira.StateOK},
+				{ID: "parent", Name: "Parent", State: moira.StateERROR},
+				{ID: "child", Name: "Child", State: moira.StateNODATA},
+			},
+			Edges: []dto.TriggerDependencyEdge{
+				{Parent: "parent", Child: "trigger"},
+				{Parent: "trigger", Child: "child"},
+			},
+		})
+	})
+}
@@ -3,6 +3,8 @@ package dto
 
 import (
 	"net/http"
+
+	"github.com/moira-alert/moira"
 )
 
 type PatternList struct {
@@ -18,3 +20,19 @@ type PatternData struct {
 	Pattern  string         `json:"pattern"`
 	Triggers []TriggerModel `json:"triggers"`
 }
+
+type IngestionLimitViolationList struct {
+	List []moira.IngestionLimitViolation `json:"list"`
+}
+
+func (*IngestionLimitViolationList) Render(w http.ResponseWriter, r *http.Request) error {
+	return nil
+}
+
+type RejectedTimestampSampleList struct {
+	List []moira.RejectedTimestampSample `json:"list"`
+}
+
+func (*RejectedTimestampSampleList) Render(w http.ResponseWriter, r *http.Request) error {
+	return nil
+}
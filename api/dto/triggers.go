@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"regexp"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/moira-alert/moira/templating"
@@ -53,7 +54,7 @@ type TriggerModel struct {
 	WarnValue *float64 `json:"warn_value"`
 	// ERROR threshold
 	ErrorValue *float64 `json:"error_value"`
-	// Could be: rising, falling, expression
+	// Could be: rising, falling, expression, anomaly, forecast
 	TriggerType string `json:"trigger_type"`
 	// Set of tags to manipulate subscriptions
 	Tags []string `json:"tags"`
@@ -69,10 +70,26 @@ type TriggerModel struct {
 	Patterns []string `json:"patterns"`
 	// Shows if trigger is remote (graphite-backend) based or stored inside Moira-Redis DB
 	IsRemote bool `json:"is_remote"`
+	// Metric source trigger targets are fetched from: graphite_local, graphite_remote or prometheus_remote
+	TriggerSource moira.TriggerSource `json:"trigger_source,omitempty"`
+	// Name of remote graphite cluster trigger targets are fetched from, default remote is used if empty
+	ClusterName string `json:"cluster_name,omitempty"`
 	// If true, first event NODATA → OK will be omitted
 	MuteNewMetrics bool `json:"mute_new_metrics"`
 	// A list of targets that have only alone metrics
 	AloneMetrics map[string]bool `json:"alone_metrics"`
+	// IDs of triggers this trigger depends on. Trigger events are suppressed while any of parents is in ERROR or NODATA state
+	Parents []string `json:"parents"`
+	// Sensitivity settings of anomaly trigger, used instead of WARN/ERROR values
+	Anomaly *moira.AnomalyParams `json:"anomaly,omitempty"`
+	// Trend settings of forecast trigger, used instead of WARN/ERROR values
+	Forecast *moira.ForecastParams `json:"forecast,omitempty"`
+	// Conditions metric must satisfy in new state before state change fires an event
+	Pending *moira.PendingParams `json:"pending,omitempty"`
+	// Flapping detection settings, events of flapping metric are muted until it stabilises
+	Flapping *moira.FlappingParams `json:"flapping,omitempty"`
+	// Ordered list of thresholds for metrics matching patterns, first matching override is used instead of trigger thresholds
+	Overrides []moira.ThresholdOverride `json:"overrides,omitempty"`
 }
 
 // ToMoiraTrigger transforms TriggerModel to moira.Trigger
@@ -92,8 +109,16 @@ func (model *TriggerModel) ToMoiraTrigger() *moira.Trigger {
 		Expression:     &model.Expression,
 		Patterns:       model.Patterns,
 		IsRemote:       model.IsRemote,
+		TriggerSource:  model.TriggerSource,
+		ClusterName:    model.ClusterName,
 		MuteNewMetrics: model.MuteNewMetrics,
 		AloneMetrics:   model.AloneMetrics,
+		Parents:        model.Parents,
+		Anomaly:        model.Anomaly,
+		Forecast:       model.Forecast,
+		Pending:        model.Pending,
+		Flapping:       model.Flapping,
+		Overrides:      model.Overrides,
 	}
 }
 
@@ -114,13 +139,22 @@ func CreateTriggerModel(trigger *moira.Trigger) TriggerModel {
 		Expression:     moira.UseString(trigger.Expression),
 		Patterns:       trigger.Patterns,
 		IsRemote:       trigger.IsRemote,
+		TriggerSource:  trigger.TriggerSource,
+		ClusterName:    trigger.ClusterName,
 		MuteNewMetrics: trigger.MuteNewMetrics,
 		AloneMetrics:   trigger.AloneMetrics,
+		Parents:        trigger.Parents,
+		Anomaly:        trigger.Anomaly,
+		Forecast:       trigger.Forecast,
+		Pending:        trigger.Pending,
+		Flapping:       trigger.Flapping,
+		Overrides:      trigger.Overrides,
 	}
 }
 
 func (trigger *Trigger) Bind(request *http.Request) error {
 	trigger.Tags = normalizeTags(trigger.Tags)
+	trigger.Parents = normalizeParents(trigger.Parents)
 	if len(trigger.Targets) == 0 {
 		return api.ErrInvalidRequestContent{ValidationError: fmt.Errorf("targets is required")}
 	}
@@ -130,9 +164,21 @@ func (trigger *Trigger) Bind(request *http.Request) error {
 	if trigger.Name == "" {
 		return api.ErrInvalidRequestContent{ValidationError: fmt.Errorf("trigger name is required")}
 	}
+	if err := checkTriggerSource(trigger); err != nil {
+		return api.ErrInvalidRequestContent{ValidationError: err}
+	}
 	if err := checkWarnErrorExpression(trigger); err != nil {
 		return api.ErrInvalidRequestContent{ValidationError: err}
 	}
+	if err := checkThresholdOverrides(trigger); err != nil {
+		return api.ErrInvalidRequestContent{ValidationError: err}
+	}
+	if err := checkPendingParams(trigger.Pending); err != nil {
+		return api.ErrInvalidRequestContent{ValidationError: err}
+	}
+	if err := checkFlappingParams(trigger.Flapping); err != nil {
+		return api.ErrInvalidRequestContent{ValidationError: err}
+	}
 	if len(trigger.Targets) <= 1 { // we should have empty alone metrics dictionary when there is only one target
 		trigger.AloneMetrics = map[string]bool{}
 	}
@@ -157,10 +203,12 @@ func (trigger *Trigger) Bind(request *http.Request) error {
 		TriggerType:             trigger.TriggerType,
 		PreviousState:           moira.StateNODATA,
 		Expression:              &trigger.Expression,
+		Anomaly:                 trigger.Anomaly,
+		Forecast:                trigger.Forecast,
 	}
 
 	metricsSourceProvider := middleware.GetTriggerTargetsSourceProvider(request)
-	metricsSource, err := metricsSourceProvider.GetMetricSource(trigger.IsRemote)
+	metricsSource, err := metricsSourceProvider.GetTriggerSourceMetricSource(trigger.TriggerSource, trigger.ClusterName)
 	if err != nil {
 		return err
 	}
@@ -168,6 +216,12 @@ func (trigger *Trigger) Bind(request *http.Request) error {
 	if err := checkTTLSanity(trigger, metricsSource); err != nil {
 		return api.ErrInvalidRequestContent{ValidationError: err}
 	}
+	if err := checkHistorySanity(trigger, metricsSource); err != nil {
+		if _, ok := err.(expression.ErrInvalidExpression); ok {
+			return err
+		}
+		return api.ErrInvalidRequestContent{ValidationError: err}
+	}
 
 	metricsDataNames, err := resolvePatterns(trigger, &triggerExpression, metricsSource)
 	if err != nil {
@@ -185,7 +239,96 @@ func (trigger *Trigger) Bind(request *http.Request) error {
 	if _, err := triggerExpression.Evaluate(); err != nil {
 		return err
 	}
+	for i := range trigger.Overrides {
+		overrideExpression := triggerExpression
+		overrideExpression.WarnValue = trigger.Overrides[i].WarnValue
+		overrideExpression.ErrorValue = trigger.Overrides[i].ErrorValue
+		overrideExpression.Expression = &trigger.Overrides[i].Expression
+		if _, err := overrideExpression.Evaluate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// normalizeParents removes empty and duplicated parent trigger IDs
+func normalizeParents(parents []string) []string {
+	normalized := make([]string, 0, len(parents))
+	seen := make(map[string]bool, len(parents))
+	for _, parentID := range parents {
+		if parentID != "" && !seen[parentID] {
+			seen[parentID] = true
+			normalized = append(normalized, parentID)
+		}
+	}
+	return normalized
+}
 
+// checkTriggerSource validates declared trigger source and keeps is_remote flag consistent with it,
+// source of trigger without declared one is resolved by is_remote flag
+func checkTriggerSource(trigger *Trigger) error {
+	switch trigger.TriggerSource {
+	case moira.TriggerSourceNotSet:
+		trigger.TriggerSource = trigger.ToMoiraTrigger().GetTriggerSource()
+	case moira.GraphiteLocal:
+		trigger.IsRemote = false
+	case moira.GraphiteRemote, moira.PrometheusRemote:
+		trigger.IsRemote = true
+	default:
+		return fmt.Errorf("trigger_source can be only %s, %s or %s, got: '%s'",
+			moira.GraphiteLocal, moira.GraphiteRemote, moira.PrometheusRemote, trigger.TriggerSource)
+	}
+	if trigger.ClusterName != "" && trigger.TriggerSource != moira.GraphiteRemote {
+		return fmt.Errorf("cluster_name can be used only with trigger_source %s", moira.GraphiteRemote)
+	}
+	return nil
+}
+
+func checkPendingParams(pending *moira.PendingParams) error {
+	if pending == nil {
+		return nil
+	}
+	if err := checkPendingCondition(moira.PendingCondition{Period: pending.Period, Checks: pending.Checks}); err != nil {
+		return err
+	}
+	for state, condition := range pending.States {
+		switch state {
+		case moira.StateOK, moira.StateWARN, moira.StateERROR, moira.StateNODATA:
+		default:
+			return fmt.Errorf("pending state can be only %s, %s, %s or %s, got: '%s'",
+				moira.StateOK, moira.StateWARN, moira.StateERROR, moira.StateNODATA, state)
+		}
+		if err := checkPendingCondition(condition); err != nil {
+			return fmt.Errorf("pending condition of state %s: %w", state, err)
+		}
+	}
+	return nil
+}
+
+func checkPendingCondition(condition moira.PendingCondition) error {
+	if condition.Period < 0 {
+		return fmt.Errorf("pending period can't be negative")
+	}
+	if condition.Checks < 0 {
+		return fmt.Errorf("pending checks can't be negative")
+	}
+	return nil
+}
+
+func checkFlappingParams(flapping *moira.FlappingParams) error {
+	if flapping == nil {
+		return nil
+	}
+	if flapping.Window <= 0 {
+		return fmt.Errorf("flapping window must be positive")
+	}
+	if flapping.Changes < 2 { //nolint
+		return fmt.Errorf("flapping changes must be at least 2")
+	}
+	if flapping.StopChanges < 0 || flapping.StopChanges >= flapping.Changes {
+		return fmt.Errorf("flapping stop_changes must be in range from 0 to changes - 1")
+	}
 	return nil
 }
 
@@ -193,15 +336,51 @@ func checkTTLSanity(trigger *Trigger, metricsSource metricSource.MetricSource) e
 	maximumAllowedTTL := metricsSource.GetMetricsTTLSeconds()
 
 	if trigger.TTL > maximumAllowedTTL {
-		triggerType := "local"
-		if trigger.IsRemote {
-			triggerType = "remote"
+		return fmt.Errorf("TTL for %s trigger can't be more than %d seconds", getTriggerSourceName(trigger), maximumAllowedTTL)
+	}
+	return nil
+}
+
+// checkHistorySanity checks that metric source keeps history required by trigger,
+// e.g. past seasons of anomaly trigger, window of expression functions or lookback period of forecast trigger
+func checkHistorySanity(trigger *Trigger, metricsSource metricSource.MetricSource) error {
+	switch {
+	case trigger.TriggerType == moira.AnomalyTrigger && trigger.Anomaly != nil:
+		anomaly := trigger.Anomaly
+		history := anomaly.Season*int64(anomaly.Seasons) + anomaly.Window
+		if maximumAllowedHistory := metricsSource.GetMetricsTTLSeconds(); history > maximumAllowedHistory {
+			return fmt.Errorf("anomaly seasons and window for %s trigger can't cover more than %d seconds, got %d",
+				getTriggerSourceName(trigger), maximumAllowedHistory, history)
+		}
+	case trigger.TriggerType == moira.ExpressionTrigger:
+		window, err := expression.GetTriggerWindow(trigger.ToMoiraTrigger())
+		if err != nil || window == 0 {
+			return err
+		}
+		if maximumAllowedHistory := metricsSource.GetMetricsTTLSeconds(); window > maximumAllowedHistory {
+			return fmt.Errorf("expression window for %s trigger can't be more than %d seconds, got %d",
+				getTriggerSourceName(trigger), maximumAllowedHistory, window)
+		}
+	case trigger.TriggerType == moira.ForecastTrigger && trigger.Forecast != nil:
+		if maximumAllowedHistory := metricsSource.GetMetricsTTLSeconds(); trigger.Forecast.Lookback > maximumAllowedHistory {
+			return fmt.Errorf("forecast lookback for %s trigger can't be more than %d seconds",
+				getTriggerSourceName(trigger), maximumAllowedHistory)
 		}
-		return fmt.Errorf("TTL for %s trigger can't be more than %d seconds", triggerType, maximumAllowedTTL)
 	}
 	return nil
 }
 
+func getTriggerSourceName(trigger *Trigger) string {
+	switch trigger.TriggerSource {
+	case moira.PrometheusRemote:
+		return "prometheus"
+	case moira.GraphiteRemote:
+		return "remote"
+	default:
+		return "local"
+	}
+}
+
 func resolvePatterns(trigger *Trigger, expressionValues *expression.TriggerExpression, metricsSource metricSource.MetricSource) (map[string]bool, error) {
 	now := time.Now().Unix()
 	targetNum := 1
@@ -233,6 +412,16 @@ func resolvePatterns(trigger *Trigger, expressionValues *expression.TriggerExpre
 }
 
 func checkWarnErrorExpression(trigger *Trigger) error {
+	switch trigger.TriggerType {
+	case moira.AnomalyTrigger:
+		trigger.Forecast = nil
+		return checkAnomalyFields(trigger)
+	case moira.ForecastTrigger:
+		trigger.Anomaly = nil
+		return checkForecastFields(trigger)
+	}
+	trigger.Anomaly, trigger.Forecast = nil, nil
+
 	if trigger.WarnValue == nil && trigger.ErrorValue == nil && trigger.Expression == "" {
 		return fmt.Errorf("at least one of error_value, warn_value or expression is required")
 	}
@@ -299,13 +488,62 @@ func checkWarnErrorExpression(trigger *Trigger) error {
 		}
 
 	default:
-		return fmt.Errorf("wrong trigger_type: %v, allowable values: '%v', '%v', '%v'",
-			trigger.TriggerType, moira.RisingTrigger, moira.FallingTrigger, moira.ExpressionTrigger)
+		return fmt.Errorf("wrong trigger_type: %v, allowable values: '%v', '%v', '%v', '%v', '%v'",
+			trigger.TriggerType, moira.RisingTrigger, moira.FallingTrigger, moira.ExpressionTrigger, moira.AnomalyTrigger, moira.ForecastTrigger)
 	}
 
 	return nil
 }
 
+func checkThresholdOverrides(trigger *Trigger) error {
+	for _, override := range trigger.Overrides {
+		if err := checkThresholdOverride(trigger.TriggerType, override); err != nil {
+			return fmt.Errorf("override for pattern '%s': %v", override.Pattern, err)
+		}
+	}
+	return nil
+}
+
+func checkThresholdOverride(triggerType string, override moira.ThresholdOverride) error {
+	if override.Pattern == "" {
+		return fmt.Errorf("pattern is required")
+	}
+	if strings.Contains("."+override.Pattern+".", "..") {
+		return fmt.Errorf("pattern can't contain empty parts")
+	}
+	switch triggerType {
+	case moira.RisingTrigger, moira.FallingTrigger:
+		if override.Expression != "" {
+			return fmt.Errorf("can't use 'expression' to trigger_type: '%v'", triggerType)
+		}
+		if override.WarnValue == nil && override.ErrorValue == nil {
+			return fmt.Errorf("at least one of error_value or warn_value is required")
+		}
+		if override.WarnValue == nil || override.ErrorValue == nil {
+			return nil
+		}
+		if *override.WarnValue == *override.ErrorValue {
+			return fmt.Errorf("error_value is equal to warn_value, please set exactly one value")
+		}
+		if triggerType == moira.RisingTrigger && *override.WarnValue > *override.ErrorValue {
+			return fmt.Errorf("error_value should be greater than warn_value")
+		}
+		if triggerType == moira.FallingTrigger && *override.WarnValue < *override.ErrorValue {
+			return fmt.Errorf("warn_value should be greater than error_value")
+		}
+	case moira.ExpressionTrigger:
+		if override.Expression == "" {
+			return fmt.Errorf("expression is required")
+		}
+		if override.WarnValue != nil || override.ErrorValue != nil {
+			return fmt.Errorf("can't use 'warn_value' and 'error_value' on trigger_type: '%v'", moira.ExpressionTrigger)
+		}
+	default:
+		return fmt.Errorf("can't use overrides on trigger_type: '%v'", triggerType)
+	}
+	return nil
+}
+
 func checkSimpleModeFields(trigger *Trigger) error {
 	if len(trigger.Targets) > 1 {
 		return fmt.Errorf("can't use trigger_type not '%v' for with multiple targets", trigger.TriggerType)
@@ -316,6 +554,26 @@ func checkSimpleModeFields(trigger *Trigger) error {
 	return nil
 }
 
+func checkAnomalyFields(trigger *Trigger) error {
+	if trigger.WarnValue != nil || trigger.ErrorValue != nil {
+		return fmt.Errorf("can't use 'warn_value' and 'error_value' on trigger_type: '%v', use anomaly deviations instead", moira.AnomalyTrigger)
+	}
+	if err := checkSimpleModeFields(trigger); err != nil {
+		return err
+	}
+	return expression.ValidateAnomalyParams(trigger.Anomaly)
+}
+
+func checkForecastFields(trigger *Trigger) error {
+	if trigger.WarnValue != nil || trigger.ErrorValue != nil {
+		return fmt.Errorf("can't use 'warn_value' and 'error_value' on trigger_type: '%v', use forecast horizons instead", moira.ForecastTrigger)
+	}
+	if err := checkSimpleModeFields(trigger); err != nil {
+		return err
+	}
+	return expression.ValidateForecastParams(trigger.Forecast)
+}
+
 func (*Trigger) Render(w http.ResponseWriter, r *http.Request) error {
 	return nil
 }
@@ -408,3 +666,37 @@ type TriggersSearchResultDeleteResponse struct {
 func (TriggersSearchResultDeleteResponse) Render(w http.ResponseWriter, r *http.Request) error {
 	return nil
 }
+
+// TriggerDependencies is dependency graph of trigger containing all its ancestors and descendants
+type TriggerDependencies struct {
+	Nodes []TriggerDependencyNode `json:"nodes"`
+	Edges []TriggerDependencyEdge `json:"edges"`
+}
+
+type TriggerDependencyNode struct {
+	ID    string      `json:"id"`
+	Name  string      `json:"name"`
+	State moira.State `json:"state"`
+}
+
+type TriggerDependencyEdge struct {
+	Parent string `json:"parent"`
+	Child  string `json:"child"`
+}
+
+func (*TriggerDependencies) Render(w http.ResponseWriter, r *http.Request) error {
+	return nil
+}
+
+// TriggerBacktest is timeline of trigger checks replayed over historical data and events they produced
+type TriggerBacktest struct {
+	From    int64                     `json:"from"`
+	To      int64                     `json:"to"`
+	Message string                    `json:"message,omitempty"`
+	Checks  []moira.CheckData         `json:"checks"`
+	Events  []moira.NotificationEvent `json:"events"`
+}
+
+func (*TriggerBacktest) Render(w http.ResponseWriter, r *http.Request) error {
+	return nil
+}
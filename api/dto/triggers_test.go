@@ -9,6 +9,7 @@ import (
 	"github.com/moira-alert/moira"
 	"github.com/moira-alert/moira/api"
 	"github.com/moira-alert/moira/api/middleware"
+	"github.com/moira-alert/moira/expression"
 	metricSource "github.com/moira-alert/moira/metric_source"
 	mock_metric_source "github.com/moira-alert/moira/mock/metric_source"
 
@@ -23,8 +24,9 @@ func TestTriggerValidation(t *testing.T) {
 
 		localSource := mock_metric_source.NewMockMetricSource(mockCtrl)
 		remoteSource := mock_metric_source.NewMockMetricSource(mockCtrl)
+		prometheusSource := mock_metric_source.NewMockMetricSource(mockCtrl)
 		fetchResult := mock_metric_source.NewMockFetchResult(mockCtrl)
-		sourceProvider := metricSource.CreateMetricSourceProvider(localSource, remoteSource)
+		sourceProvider := metricSource.CreateMetricSourceProvider(localSource, remoteSource, prometheusSource)
 
 		request, _ := http.NewRequest("PUT", "/api/trigger", nil)
 		request.Header.Set("Content-Type", "application/json")
@@ -169,6 +171,234 @@ func TestTriggerValidation(t *testing.T) {
 				err := tr.Bind(request)
 				So(err, ShouldBeNil)
 			})
+			Convey("and expression with functions", func() {
+				trigger.Expression = "max(abs(t1 - t2), round(t3, 1)) > 10 ? ERROR : OK"
+				tr := Trigger{trigger, throttling}
+				err := tr.Bind(request)
+				So(err, ShouldBeNil)
+			})
+			Convey("and expression with unknown function", func() {
+				trigger.Expression = "median(t1, t2) > 10 ? ERROR : OK"
+				tr := Trigger{trigger, throttling}
+				err := tr.Bind(request)
+				So(err, ShouldHaveSameTypeAs, expression.ErrInvalidExpression{})
+				So(err.Error(), ShouldEqual, "unknown function median")
+			})
+			Convey("and expression with wrong function arguments", func() {
+				trigger.Expression = "t1 > 10 ? ERROR : (pow(t2) > 10 ? WARN : OK)"
+				tr := Trigger{trigger, throttling}
+				err := tr.Bind(request)
+				So(err, ShouldHaveSameTypeAs, expression.ErrInvalidExpression{})
+				So(err.Error(), ShouldEqual, "function pow expects 2 arguments, got 1")
+			})
+			Convey("and expression with window functions", func() {
+				trigger.Expression = "avg_over_time(t1, 1800) > 10 ? ERROR : OK"
+				tr := Trigger{trigger, throttling}
+				err := tr.Bind(request)
+				So(err, ShouldBeNil)
+			})
+			Convey("and expression window longer than metrics history", func() {
+				trigger.Expression = "value_ago(t1, 7200) > t1 ? ERROR : OK"
+				tr := Trigger{trigger, throttling}
+				err := tr.Bind(request)
+				So(err, ShouldResemble, api.ErrInvalidRequestContent{ValidationError: fmt.Errorf("expression window for local trigger can't be more than 3600 seconds, got 7200")})
+			})
+			Convey("and override expression window longer than metrics history", func() {
+				trigger.Overrides = []moira.ThresholdOverride{{Pattern: "DevOps.*", Expression: "max_over_time(t1, 7200) > 10 ? ERROR : OK"}}
+				tr := Trigger{trigger, throttling}
+				err := tr.Bind(request)
+				So(err, ShouldResemble, api.ErrInvalidRequestContent{ValidationError: fmt.Errorf("expression window for local trigger can't be more than 3600 seconds, got 7200")})
+			})
+		})
+
+		Convey("Test AnomalyTrigger", func() {
+			localSource.EXPECT().IsConfigured().Return(true, nil).AnyTimes()
+			localSource.EXPECT().GetMetricsTTLSeconds().Return(int64(7 * 86400)).AnyTimes()
+			localSource.EXPECT().Fetch(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(fetchResult, nil).AnyTimes()
+			fetchResult.EXPECT().GetPatterns().Return(make([]string, 0), nil).AnyTimes()
+			fetchResult.EXPECT().GetMetricsData().Return([]metricSource.MetricData{*metricSource.MakeMetricData("", []float64{}, 0, 0)}).AnyTimes()
+
+			warnDeviation := float64(2)
+			errorDeviation := float64(3)
+			trigger.TriggerType = moira.AnomalyTrigger
+			trigger.Targets = []string{"DevOps.system.graphite01.disk._mnt_data.gigabyte_percentfree"}
+			trigger.Anomaly = &moira.AnomalyParams{Season: 86400, Seasons: 4, Window: 600, WarnDeviation: &warnDeviation, ErrorDeviation: &errorDeviation}
+
+			Convey("and anomaly params", func() {
+				tr := Trigger{trigger, throttling}
+				err := tr.Bind(request)
+				So(err, ShouldBeNil)
+			})
+			Convey("and warn_value", func() {
+				trigger.WarnValue = &warnValue
+				tr := Trigger{trigger, throttling}
+				err := tr.Bind(request)
+				So(err, ShouldResemble, api.ErrInvalidRequestContent{ValidationError: fmt.Errorf("can't use 'warn_value' and 'error_value' on trigger_type: 'anomaly', use anomaly deviations instead")})
+			})
+			Convey("and multiple targets", func() {
+				trigger.Targets = append(trigger.Targets, "DevOps.system.graphite02.disk._mnt_data.gigabyte_percentfree")
+				tr := Trigger{trigger, throttling}
+				err := tr.Bind(request)
+				So(err, ShouldResemble, api.ErrInvalidRequestContent{ValidationError: fmt.Errorf("can't use trigger_type not 'anomaly' for with multiple targets")})
+			})
+			Convey("and seasons longer than metrics history", func() {
+				trigger.Anomaly.Seasons = 7
+				tr := Trigger{trigger, throttling}
+				err := tr.Bind(request)
+				So(err, ShouldResemble, api.ErrInvalidRequestContent{ValidationError: fmt.Errorf("anomaly seasons and window for local trigger can't cover more than 604800 seconds, got 605400")})
+			})
+			Convey("without anomaly params", func() {
+				trigger.Anomaly = nil
+				tr := Trigger{trigger, throttling}
+				err := tr.Bind(request)
+				So(err, ShouldResemble, api.ErrInvalidRequestContent{ValidationError: fmt.Errorf("trigger_type set to anomaly, but no anomaly params provided")})
+			})
+		})
+
+		Convey("Test ForecastTrigger", func() {
+			localSource.EXPECT().IsConfigured().Return(true, nil).AnyTimes()
+			localSource.EXPECT().GetMetricsTTLSeconds().Return(int64(3600)).AnyTimes()
+			localSource.EXPECT().Fetch(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(fetchResult, nil).AnyTimes()
+			fetchResult.EXPECT().GetPatterns().Return(make([]string, 0), nil).AnyTimes()
+			fetchResult.EXPECT().GetMetricsData().Return([]metricSource.MetricData{*metricSource.MakeMetricData("", []float64{}, 0, 0)}).AnyTimes()
+
+			warnHorizon := int64(86400)
+			errorHorizon := int64(3600)
+			trigger.TriggerType = moira.ForecastTrigger
+			trigger.Targets = []string{"DevOps.system.graphite01.disk._mnt_data.gigabyte_used"}
+			trigger.Forecast = &moira.ForecastParams{Lookback: 1800, Limit: 1000, WarnHorizon: &warnHorizon, ErrorHorizon: &errorHorizon, Method: moira.RobustForecastMethod}
+
+			Convey("and forecast params", func() {
+				tr := Trigger{trigger, throttling}
+				err := tr.Bind(request)
+				So(err, ShouldBeNil)
+			})
+			Convey("and error_value", func() {
+				trigger.ErrorValue = &errorValue
+				tr := Trigger{trigger, throttling}
+				err := tr.Bind(request)
+				So(err, ShouldResemble, api.ErrInvalidRequestContent{ValidationError: fmt.Errorf("can't use 'warn_value' and 'error_value' on trigger_type: 'forecast', use forecast horizons instead")})
+			})
+			Convey("and lookback longer than metrics history", func() {
+				trigger.Forecast.Lookback = 21600
+				tr := Trigger{trigger, throttling}
+				err := tr.Bind(request)
+				So(err, ShouldResemble, api.ErrInvalidRequestContent{ValidationError: fmt.Errorf("forecast lookback for local trigger can't be more than 3600 seconds")})
+			})
+			Convey("without lookback", func() {
+				trigger.Forecast.Lookback = 0
+				tr := Trigger{trigger, throttling}
+				err := tr.Bind(request)
+				So(err, ShouldResemble, api.ErrInvalidRequestContent{ValidationError: fmt.Errorf("forecast lookback must be positive")})
+			})
+		})
+
+		Convey("Test threshold overrides", func() {
+			localSource.EXPECT().IsConfigured().Return(true, nil).AnyTimes()
+			localSource.EXPECT().GetMetricsTTLSeconds().Return(int64(3600)).AnyTimes()
+			localSource.EXPECT().Fetch(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(fetchResult, nil).AnyTimes()
+			fetchResult.EXPECT().GetPatterns().Return(make([]string, 0), nil).AnyTimes()
+			fetchResult.EXPECT().GetMetricsData().Return([]metricSource.MetricData{*metricSource.MakeMetricData("", []float64{}, 0, 0)}).AnyTimes()
+
+			overrideWarn := float64(50)
+			overrideError := float64(20)
+			trigger.Targets = []string{"DevOps.system.*.disk._mnt_data.gigabyte_percentfree"}
+
+			Convey("on falling trigger", func() {
+				trigger.TriggerType = moira.FallingTrigger
+				trigger.WarnValue = &warnValue
+				trigger.ErrorValue = &errorValue
+
+				Convey("with thresholds", func() {
+					trigger.Overrides = []moira.ThresholdOverride{{Pattern: "DevOps.system.big-*.disk._mnt_data.gigabyte_percentfree", WarnValue: &overrideWarn, ErrorValue: &overrideError}}
+					tr := Trigger{trigger, throttling}
+					err := tr.Bind(request)
+					So(err, ShouldBeNil)
+				})
+				Convey("with wrong thresholds order", func() {
+					trigger.Overrides = []moira.ThresholdOverride{{Pattern: "DevOps.system.big-*", WarnValue: &overrideError, ErrorValue: &overrideWarn}}
+					tr := Trigger{trigger, throttling}
+					err := tr.Bind(request)
+					So(err, ShouldResemble, api.ErrInvalidRequestContent{ValidationError: fmt.Errorf("override for pattern 'DevOps.system.big-*': warn_value should be greater than error_value")})
+				})
+				Convey("with expression", func() {
+					trigger.Overrides = []moira.ThresholdOverride{{Pattern: "DevOps.system.big-*", Expression: "t1 > 10 ? ERROR : OK"}}
+					tr := Trigger{trigger, throttling}
+					err := tr.Bind(request)
+					So(err, ShouldResemble, api.ErrInvalidRequestContent{ValidationError: fmt.Errorf("override for pattern 'DevOps.system.big-*': can't use 'expression' to trigger_type: 'falling'")})
+				})
+				Convey("with empty pattern part", func() {
+					trigger.Overrides = []moira.ThresholdOverride{{Pattern: "DevOps..big-*", WarnValue: &overrideWarn}}
+					tr := Trigger{trigger, throttling}
+					err := tr.Bind(request)
+					So(err, ShouldResemble, api.ErrInvalidRequestContent{ValidationError: fmt.Errorf("override for pattern 'DevOps..big-*': pattern can't contain empty parts")})
+				})
+			})
+
+			Convey("on expression trigger", func() {
+				trigger.TriggerType = moira.ExpressionTrigger
+				trigger.Expression = "t1 < 10 ? ERROR : OK"
+
+				Convey("with expression", func() {
+					trigger.Overrides = []moira.ThresholdOverride{{Pattern: "DevOps.system.big-*.disk._mnt_data.gigabyte_percentfree", Expression: "t1 < 30 ? ERROR : OK"}}
+					tr := Trigger{trigger, throttling}
+					err := tr.Bind(request)
+					So(err, ShouldBeNil)
+				})
+				Convey("without expression", func() {
+					trigger.Overrides = []moira.ThresholdOverride{{Pattern: "DevOps.system.big-*", WarnValue: &overrideWarn}}
+					tr := Trigger{trigger, throttling}
+					err := tr.Bind(request)
+					So(err, ShouldResemble, api.ErrInvalidRequestContent{ValidationError: fmt.Errorf("override for pattern 'DevOps.system.big-*': expression is required")})
+				})
+			})
+		})
+
+		Convey("Test pending and flapping", func() {
+			localSource.EXPECT().IsConfigured().Return(true, nil).AnyTimes()
+			localSource.EXPECT().GetMetricsTTLSeconds().Return(int64(3600)).AnyTimes()
+			localSource.EXPECT().Fetch(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(fetchResult, nil).AnyTimes()
+			fetchResult.EXPECT().GetPatterns().Return(make([]string, 0), nil).AnyTimes()
+			fetchResult.EXPECT().GetMetricsData().Return([]metricSource.MetricData{*metricSource.MakeMetricData("", []float64{}, 0, 0)}).AnyTimes()
+
+			trigger.TriggerType = moira.FallingTrigger
+			trigger.Targets = []string{"DevOps.system.graphite01.disk._mnt_data.gigabyte_percentfree"}
+			trigger.WarnValue = &warnValue
+			trigger.ErrorValue = &errorValue
+
+			Convey("with period and state conditions", func() {
+				trigger.Pending = &moira.PendingParams{
+					Period: 300,
+					States: map[moira.State]moira.PendingCondition{moira.StateOK: {Checks: 3}},
+				}
+				tr := Trigger{trigger, throttling}
+				err := tr.Bind(request)
+				So(err, ShouldBeNil)
+			})
+			Convey("with negative period", func() {
+				trigger.Pending = &moira.PendingParams{Period: -1}
+				tr := Trigger{trigger, throttling}
+				err := tr.Bind(request)
+				So(err, ShouldResemble, api.ErrInvalidRequestContent{ValidationError: fmt.Errorf("pending period can't be negative")})
+			})
+			Convey("with unknown state", func() {
+				trigger.Pending = &moira.PendingParams{States: map[moira.State]moira.PendingCondition{moira.StateEXCEPTION: {Checks: 2}}}
+				tr := Trigger{trigger, throttling}
+				err := tr.Bind(request)
+				So(err, ShouldResemble, api.ErrInvalidRequestContent{ValidationError: fmt.Errorf("pending state can be only OK, WARN, ERROR or NODATA, got: 'EXCEPTION'")})
+			})
+			Convey("with flapping params", func() {
+				trigger.Flapping = &moira.FlappingParams{Window: 600, Changes: 4, StopChanges: 1}
+				tr := Trigger{trigger, throttling}
+				err := tr.Bind(request)
+				So(err, ShouldBeNil)
+			})
+			Convey("with flapping stop changes not less than changes", func() {
+				trigger.Flapping = &moira.FlappingParams{Window: 600, Changes: 4, StopChanges: 4}
+				tr := Trigger{trigger, throttling}
+				err := tr.Bind(request)
+				So(err, ShouldResemble, api.ErrInvalidRequestContent{ValidationError: fmt.Errorf("flapping stop_changes must be in range from 0 to changes - 1")})
+			})
 		})
 
 		Convey("Test alone metrics", func() {
@@ -236,5 +466,74 @@ func TestTriggerValidation(t *testing.T) {
 				So(err, ShouldResemble, api.ErrInvalidRequestContent{ValidationError: fmt.Errorf("pattern \"*\" is not allowed to use")})
 			})
 		})
+
+		Convey("Test trigger source", func() {
+			fetchResult.EXPECT().GetPatterns().Return(make([]string, 0), fmt.Errorf("no patterns")).AnyTimes()
+			fetchResult.EXPECT().GetMetricsData().Return([]metricSource.MetricData{*metricSource.MakeMetricData("", []float64{}, 0, 0)}).AnyTimes()
+
+			trigger.Targets = []string{"rate(http_requests_total[5m])"}
+			trigger.Expression = "OK"
+			Convey("prometheus trigger is remote and fetched from prometheus", func() {
+				prometheusSource.EXPECT().IsConfigured().Return(true, nil)
+				prometheusSource.EXPECT().GetMetricsTTLSeconds().Return(int64(3600))
+				prometheusSource.EXPECT().Fetch("rate(http_requests_total[5m])", gomock.Any(), gomock.Any(), false).Return(fetchResult, nil)
+				trigger.TriggerSource = moira.PrometheusRemote
+				tr := Trigger{trigger, throttling}
+				err := tr.Bind(request)
+				So(err, ShouldBeNil)
+				So(tr.IsRemote, ShouldBeTrue)
+			})
+			Convey("prometheus trigger TTL is limited by prometheus metrics TTL", func() {
+				prometheusSource.EXPECT().IsConfigured().Return(true, nil)
+				prometheusSource.EXPECT().GetMetricsTTLSeconds().Return(int64(300))
+				trigger.TriggerSource = moira.PrometheusRemote
+				tr := Trigger{trigger, throttling}
+				err := tr.Bind(request)
+				So(err, ShouldResemble, api.ErrInvalidRequestContent{ValidationError: fmt.Errorf("TTL for prometheus trigger can't be more than 300 seconds")})
+			})
+			Convey("not set source is resolved by is_remote", func() {
+				remoteSource.EXPECT().IsConfigured().Return(true, nil)
+				remoteSource.EXPECT().GetMetricsTTLSeconds().Return(int64(3600))
+				remoteSource.EXPECT().Fetch(gomock.Any(), gomock.Any(), gomock.Any(), false).Return(fetchResult, nil)
+				trigger.IsRemote = true
+				tr := Trigger{trigger, throttling}
+				err := tr.Bind(request)
+				So(err, ShouldBeNil)
+				So(tr.TriggerSource, ShouldEqual, moira.GraphiteRemote)
+			})
+			Convey("remote cluster trigger is fetched from cluster", func() {
+				clusterSource := mock_metric_source.NewMockMetricSource(mockCtrl)
+				sourceProvider.RegisterRemoteCluster("eu", clusterSource)
+				clusterSource.EXPECT().IsConfigured().Return(true, nil)
+				clusterSource.EXPECT().GetMetricsTTLSeconds().Return(int64(3600))
+				clusterSource.EXPECT().Fetch(gomock.Any(), gomock.Any(), gomock.Any(), false).Return(fetchResult, nil)
+				trigger.TriggerSource = moira.GraphiteRemote
+				trigger.ClusterName = "eu"
+				tr := Trigger{trigger, throttling}
+				err := tr.Bind(request)
+				So(err, ShouldBeNil)
+				So(tr.IsRemote, ShouldBeTrue)
+			})
+			Convey("unknown remote cluster", func() {
+				trigger.TriggerSource = moira.GraphiteRemote
+				trigger.ClusterName = "us"
+				tr := Trigger{trigger, throttling}
+				err := tr.Bind(request)
+				So(err, ShouldResemble, fmt.Errorf("unknown remote cluster 'us'"))
+			})
+			Convey("cluster name of not graphite remote trigger", func() {
+				trigger.TriggerSource = moira.PrometheusRemote
+				trigger.ClusterName = "eu"
+				tr := Trigger{trigger, throttling}
+				err := tr.Bind(request)
+				So(err, ShouldResemble, api.ErrInvalidRequestContent{ValidationError: fmt.Errorf("cluster_name can be used only with trigger_source graphite_remote")})
+			})
+			Convey("unknown source", func() {
+				trigger.TriggerSource = "influxdb"
+				tr := Trigger{trigger, throttling}
+				err := tr.Bind(request)
+				So(err, ShouldResemble, api.ErrInvalidRequestContent{ValidationError: fmt.Errorf("trigger_source can be only graphite_local, graphite_remote or prometheus_remote, got: 'influxdb'")})
+			})
+		})
 	})
 }
@@ -1,18 +1,30 @@
 package handler
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/go-chi/chi"
 	"github.com/go-chi/render"
 	"github.com/moira-alert/moira/api"
 	"github.com/moira-alert/moira/api/controller"
 	"github.com/moira-alert/moira/api/middleware"
+	"gopkg.in/tomb.v2"
+)
+
+const (
+	defaultPatternTapDuration = time.Minute
+	maxPatternTapDuration     = 10 * time.Minute
 )
 
 func pattern(router chi.Router) {
 	router.Get("/", getAllPatterns)
+	router.Get("/limits", getIngestionLimitViolations)
+	router.Get("/timestamps", getRejectedTimestampSamples)
+	router.Get("/tap", tapPattern)
 	router.Delete("/{pattern}", deletePattern)
 }
 
@@ -28,6 +40,28 @@ func getAllPatterns(writer http.ResponseWriter, request *http.Request) {
 	}
 }
 
+func getIngestionLimitViolations(writer http.ResponseWriter, request *http.Request) {
+	violations, err := controller.GetIngestionLimitViolations(database)
+	if err != nil {
+		render.Render(writer, request, err) //nolint
+		return
+	}
+	if err := render.Render(writer, request, violations); err != nil {
+		render.Render(writer, request, api.ErrorRender(err)) //nolint
+	}
+}
+
+func getRejectedTimestampSamples(writer http.ResponseWriter, request *http.Request) {
+	samples, err := controller.GetRejectedTimestampSamples(database)
+	if err != nil {
+		render.Render(writer, request, err) //nolint
+		return
+	}
+	if err := render.Render(writer, request, samples); err != nil {
+		render.Render(writer, request, api.ErrorRender(err)) //nolint
+	}
+}
+
 func deletePattern(writer http.ResponseWriter, request *http.Request) {
 	pattern := chi.URLParam(request, "pattern")
 	if pattern == "" {
@@ -39,3 +73,82 @@ func deletePattern(writer http.ResponseWriter, request *http.Request) {
 		render.Render(writer, request, err) //nolint
 	}
 }
+
+// tapPattern streams samples of metrics matched by pattern as server-sent events
+// Query parameters: pattern, duration in seconds (60 by default, 600 at most)
+func tapPattern(writer http.ResponseWriter, request *http.Request) {
+	pattern := request.URL.Query().Get("pattern")
+	if pattern == "" {
+		render.Render(writer, request, api.ErrorInvalidRequest(fmt.Errorf("pattern must be set"))) //nolint
+		return
+	}
+	duration, err := getPatternTapDuration(request)
+	if err != nil {
+		render.Render(writer, request, api.ErrorInvalidRequest(err)) //nolint
+		return
+	}
+	flusher, ok := writer.(http.Flusher)
+	if !ok {
+		render.Render(writer, request, api.ErrorInternalServer(fmt.Errorf("streaming is not supported"))) //nolint
+		return
+	}
+
+	var subscriptionTomb tomb.Tomb
+	samples, errorResponse := controller.TapPattern(database, pattern, &subscriptionTomb)
+	if errorResponse != nil {
+		render.Render(writer, request, errorResponse) //nolint
+		return
+	}
+	defer subscriptionTomb.Kill(nil)
+
+	writer.Header().Set("Content-Type", "text/event-stream")
+	writer.Header().Set("Connection", "keep-alive")
+	writer.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	logger := middleware.GetLoggerEntry(request)
+	timeout := time.NewTimer(duration)
+	defer timeout.Stop()
+	renewTicker := time.NewTicker(controller.MetricTapRenewPeriod)
+	defer renewTicker.Stop()
+	for {
+		select {
+		case <-request.Context().Done():
+			return
+		case <-timeout.C:
+			fmt.Fprint(writer, "event: end\ndata: {}\n\n") //nolint
+			flusher.Flush()
+			return
+		case <-renewTicker.C:
+			if errorResponse := controller.RenewPatternTap(database, pattern); errorResponse != nil {
+				logger.Errorf("Failed to renew tap of pattern %s: %s", pattern, errorResponse.ErrorText)
+			}
+		case sample, ok := <-samples:
+			if !ok {
+				return
+			}
+			sampleBytes, err := json.Marshal(sample)
+			if err != nil {
+				continue
+			}
+			fmt.Fprintf(writer, "event: sample\ndata: %s\n\n", sampleBytes) //nolint
+			flusher.Flush()
+		}
+	}
+}
+
+func getPatternTapDuration(request *http.Request) (time.Duration, error) {
+	durationStr := request.URL.Query().Get("duration")
+	if durationStr == "" {
+		return defaultPatternTapDuration, nil
+	}
+	seconds, err := strconv.ParseInt(durationStr, 10, 64)
+	if err != nil || seconds <= 0 {
+		return 0, fmt.Errorf("duration must be positive number of seconds")
+	}
+	duration := time.Duration(seconds) * time.Second
+	if duration > maxPatternTapDuration {
+		return 0, fmt.Errorf("duration must not exceed %d seconds", int64(maxPatternTapDuration.Seconds()))
+	}
+	return duration, nil
+}
@@ -20,6 +20,7 @@ func trigger(router chi.Router) {
 	router.With(middleware.TriggerContext, middleware.Populate(false)).Get("/", getTrigger)
 	router.Delete("/", removeTrigger)
 	router.Get("/state", getTriggerState)
+	router.Get("/dependencies", getTriggerDependencies)
 	router.Route("/throttling", func(router chi.Router) {
 		router.Get("/", getTriggerThrottling)
 		router.Delete("/", deleteThrottling)
@@ -110,6 +111,18 @@ func getTriggerState(writer http.ResponseWriter, request *http.Request) {
 	}
 }
 
+func getTriggerDependencies(writer http.ResponseWriter, request *http.Request) {
+	triggerID := middleware.GetTriggerID(request)
+	dependencies, err := controller.GetTriggerDependencies(database, triggerID)
+	if err != nil {
+		render.Render(writer, request, err) //nolint
+		return
+	}
+	if err := render.Render(writer, request, dependencies); err != nil {
+		render.Render(writer, request, api.ErrorRender(err)) //nolint
+	}
+}
+
 func getTriggerThrottling(writer http.ResponseWriter, request *http.Request) {
 	triggerID := middleware.GetTriggerID(request)
 	triggerState, err := controller.GetTriggerThrottling(database, triggerID)
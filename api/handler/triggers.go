@@ -6,9 +6,11 @@ import (
 	"net/url"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/go-chi/chi"
 	"github.com/go-chi/render"
+	"github.com/go-graphite/carbonapi/date"
 	"github.com/moira-alert/moira"
 	metricSource "github.com/moira-alert/moira/metric_source"
 	"github.com/moira-alert/moira/metric_source/local"
@@ -28,6 +30,7 @@ func triggers(metricSourceProvider *metricSource.SourceProvider, searcher moira.
 		router.Get("/", getAllTriggers)
 		router.Put("/", createTrigger)
 		router.Put("/check", triggerCheck)
+		router.With(middleware.DateRange("-1day", "now")).Put("/backtest", triggerBacktest)
 		router.Route("/{triggerId}", trigger)
 		router.With(middleware.Paginate(0, 10)).With(middleware.Pager(false, "")).Get("/search", searchTriggers)
 		router.With(middleware.Pager(false, "")).Delete("/search/pager", deletePager)
@@ -81,7 +84,7 @@ func createTrigger(writer http.ResponseWriter, request *http.Request) {
 func getTriggerFromRequest(request *http.Request) (*dto.Trigger, *api.ErrorResponse) {
 	trigger := &dto.Trigger{}
 	if err := render.Bind(request, trigger); err != nil {
-		switch err.(type) {
+		switch typedErr := err.(type) {
 		case local.ErrParseExpr, local.ErrEvalExpr, local.ErrUnknownFunction:
 			return nil, api.ErrorInvalidRequest(fmt.Errorf("invalid graphite targets: %s", err.Error()))
 		case expression.ErrInvalidExpression:
@@ -89,6 +92,9 @@ func getTriggerFromRequest(request *http.Request) (*dto.Trigger, *api.ErrorRespo
 		case api.ErrInvalidRequestContent:
 			return nil, api.ErrorInvalidRequest(err)
 		case remote.ErrRemoteTriggerResponse:
+			if _, ok := typedErr.InternalError.(remote.ErrRemoteBadTarget); ok {
+				return nil, api.ErrorInvalidRequest(fmt.Errorf("invalid graphite targets: %s", typedErr.Reason()))
+			}
 			response := api.ErrorRemoteServerUnavailable(err)
 			middleware.GetLoggerEntry(request).Error("%s : %s : %s", response.StatusText, response.ErrorText, err)
 			return nil, response
@@ -114,13 +120,61 @@ func triggerCheck(writer http.ResponseWriter, request *http.Request) {
 		}
 	}
 
-	if len(trigger.Targets) > 0 {
+	// PromQL targets are not graphite expressions, so they can't be verified by graphite parser
+	if len(trigger.Targets) > 0 && trigger.TriggerSource != moira.PrometheusRemote {
 		response.Targets = dto.TargetVerification(trigger.Targets, ttl, trigger.IsRemote)
 	}
 
 	render.JSON(writer, request, response)
 }
 
+func triggerBacktest(writer http.ResponseWriter, request *http.Request) {
+	from, to, step, err := getBacktestParameters(request)
+	if err != nil {
+		render.Render(writer, request, api.ErrorInvalidRequest(err)) //nolint
+		return
+	}
+
+	trigger, errorResponse := getTriggerFromRequest(request)
+	if errorResponse != nil {
+		render.Render(writer, request, errorResponse) //nolint
+		return
+	}
+
+	sourceProvider := middleware.GetTriggerTargetsSourceProvider(request)
+	logger := middleware.GetLoggerEntry(request)
+	response, errorResponse := controller.BacktestTrigger(database, logger, sourceProvider, trigger.ToMoiraTrigger(), from, to, step)
+	if errorResponse != nil {
+		render.Render(writer, request, errorResponse) //nolint
+		return
+	}
+
+	if err := render.Render(writer, request, response); err != nil {
+		render.Render(writer, request, api.ErrorRender(err)) //nolint
+	}
+}
+
+func getBacktestParameters(request *http.Request) (from, to, step int64, err error) {
+	fromStr := middleware.GetFromStr(request)
+	from = date.DateParamToEpoch(fromStr, "UTC", 0, time.UTC)
+	if from == 0 {
+		return 0, 0, 0, fmt.Errorf("can not parse from: %s", fromStr)
+	}
+	toStr := middleware.GetToStr(request)
+	to = date.DateParamToEpoch(toStr, "UTC", 0, time.UTC)
+	if to == 0 {
+		return 0, 0, 0, fmt.Errorf("can not parse to: %s", toStr)
+	}
+	step = 60 //nolint
+	if stepStr := request.URL.Query().Get("step"); stepStr != "" {
+		step, err = strconv.ParseInt(stepStr, 10, 64)
+		if err != nil {
+			return 0, 0, 0, fmt.Errorf("invalid step param: %s", err.Error())
+		}
+	}
+	return from, to, step, nil
+}
+
 func searchTriggers(writer http.ResponseWriter, request *http.Request) {
 	request.ParseForm() //nolint
 	onlyErrors := getOnlyProblemsFlag(request)
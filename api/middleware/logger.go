@@ -139,13 +139,31 @@ func (entry *apiLoggerEntry) writePanic(status, bytes int, elapsed time.Duration
 	log.Error(entry.buf.String())
 }
 
+// responseWriterWithBody keeps body of error response to log error text
+// Other responses are not kept, so streamed responses are not buffered
 type responseWriterWithBody struct {
 	http.ResponseWriter
-	body bytes.Buffer
+	body        bytes.Buffer
+	captureBody bool
+}
+
+func (w *responseWriterWithBody) WriteHeader(statusCode int) {
+	w.captureBody = statusCode >= 500 //nolint
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+// Flush sends buffered data to client, it allows handlers to stream responses
+func (w *responseWriterWithBody) Flush() {
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
 }
 
 func (w *responseWriterWithBody) Write(buf []byte) (int, error) {
 	n, err := w.ResponseWriter.Write(buf)
+	if !w.captureBody {
+		return n, err
+	}
 	_, err2 := w.body.Write(buf[:n])
 	if err == nil {
 		err = err2
@@ -0,0 +1,25 @@
+package checker
+
+import (
+	"github.com/moira-alert/moira"
+)
+
+// getAnomalyBaseline returns valid values of metric within window around the same time in past seasons
+func (triggerChecker *TriggerChecker) getAnomalyBaseline(metricName string, valueTimestamp int64) []float64 {
+	params := triggerChecker.trigger.Anomaly
+	history, ok := triggerChecker.history[metricName]
+	if params == nil || !ok || history.StepTime <= 0 {
+		return nil
+	}
+
+	baseline := make([]float64, 0, params.Seasons)
+	for season := 1; season <= params.Seasons; season++ {
+		seasonTimestamp := valueTimestamp - int64(season)*params.Season
+		for timestamp := seasonTimestamp - params.Window; timestamp <= seasonTimestamp+params.Window; timestamp += history.StepTime {
+			if value := history.GetTimestampValue(timestamp); moira.IsValidFloat64(value) {
+				baseline = append(baseline, value)
+			}
+		}
+	}
+	return baseline
+}
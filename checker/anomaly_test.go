@@ -0,0 +1,69 @@
+package checker
+
+import (
+	"math"
+	"testing"
+
+	"github.com/moira-alert/moira"
+	logging "github.com/moira-alert/moira/logging/zerolog_adapter"
+	metricSource "github.com/moira-alert/moira/metric_source"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestGetAnomalyBaseline(t *testing.T) {
+	metric := "super.puper.metric"
+	history := *metricSource.MakeMetricData(metric, []float64{1, 2, 3, math.NaN(), 5, 6, 7, 8, 9, 10}, 10, 0)
+	triggerChecker := &TriggerChecker{
+		trigger: &moira.Trigger{
+			TriggerType: moira.AnomalyTrigger,
+			Anomaly:     &moira.AnomalyParams{Season: 40, Seasons: 2, Window: 10},
+		},
+		history: map[string]metricSource.MetricData{metric: history},
+	}
+
+	Convey("Values of window around the same time in past seasons", t, func() {
+		So(triggerChecker.getAnomalyBaseline(metric, 90), ShouldResemble, []float64{5, 6, 7, 1, 2, 3})
+	})
+
+	Convey("Absent values and values before history start are skipped", t, func() {
+		So(triggerChecker.getAnomalyBaseline(metric, 80), ShouldResemble, []float64{5, 6, 1, 2})
+	})
+
+	Convey("Unknown metric has empty baseline", t, func() {
+		So(triggerChecker.getAnomalyBaseline("other.metric", 90), ShouldBeEmpty)
+	})
+}
+
+func TestGetMetricDataStateAnomaly(t *testing.T) {
+	logger, _ := logging.GetLogger("Test")
+	metric := "super.puper.metric"
+	warnDeviation := 2.0
+	errorDeviation := 3.0
+	triggerChecker := &TriggerChecker{
+		logger: logger,
+		trigger: &moira.Trigger{
+			TriggerType: moira.AnomalyTrigger,
+			Anomaly:     &moira.AnomalyParams{Season: 100, Seasons: 3, WarnDeviation: &warnDeviation, ErrorDeviation: &errorDeviation},
+		},
+		history: map[string]metricSource.MetricData{
+			metric: *metricSource.MakeMetricData(metric, []float64{8, 10, 12}, 100, 0),
+		},
+	}
+	lastState := moira.MetricState{State: moira.StateOK}
+
+	Convey("Value close to baseline is OK", t, func() {
+		metrics := map[string]metricSource.MetricData{"t1": *metricSource.MakeMetricData(metric, []float64{11}, 100, 300)}
+		var valueTimestamp, checkPoint int64 = 300, 0
+		metricState, err := triggerChecker.getMetricDataState(&metrics, &lastState, &valueTimestamp, &checkPoint, logger)
+		So(err, ShouldBeNil)
+		So(metricState.State, ShouldEqual, moira.StateOK)
+	})
+
+	Convey("Value far from baseline is ERROR", t, func() {
+		metrics := map[string]metricSource.MetricData{"t1": *metricSource.MakeMetricData(metric, []float64{1}, 100, 300)}
+		var valueTimestamp, checkPoint int64 = 300, 0
+		metricState, err := triggerChecker.getMetricDataState(&metrics, &lastState, &valueTimestamp, &checkPoint, logger)
+		So(err, ShouldBeNil)
+		So(metricState.State, ShouldEqual, moira.StateERROR)
+	})
+}
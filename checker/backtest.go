@@ -0,0 +1,112 @@
+package checker
+
+import (
+	"fmt"
+
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/database"
+	"github.com/moira-alert/moira/expression"
+	metricSource "github.com/moira-alert/moira/metric_source"
+	"github.com/moira-alert/moira/metrics"
+)
+
+// BacktestResult represents timeline of trigger checks replayed over historical data and events they produced
+type BacktestResult struct {
+	Checks []moira.CheckData
+	Events []moira.NotificationEvent
+}
+
+// backtestDatabase records check data and notification events of replayed checks instead of saving them,
+// other requests are passed to database
+type backtestDatabase struct {
+	moira.Database
+	lastCheck *moira.CheckData
+	events    []moira.NotificationEvent
+}
+
+// SetTriggerLastCheck records check data of replayed check
+func (dataBase *backtestDatabase) SetTriggerLastCheck(triggerID string, checkData *moira.CheckData, isRemote bool) error {
+	dataBase.lastCheck = checkData
+	return nil
+}
+
+// PushNotificationEvent records event of replayed check
+func (dataBase *backtestDatabase) PushNotificationEvent(event *moira.NotificationEvent, ui bool) error {
+	dataBase.events = append(dataBase.events, *event)
+	return nil
+}
+
+// GetTriggerLastCheck returns nil, because states of parent triggers in the past are unknown
+func (dataBase *backtestDatabase) GetTriggerLastCheck(triggerID string) (moira.CheckData, error) {
+	return moira.CheckData{}, database.ErrNil
+}
+
+// RemovePatternsMetrics does nothing, replayed check must not change stored metrics
+func (dataBase *backtestDatabase) RemovePatternsMetrics(pattern []string) error {
+	return nil
+}
+
+// RemoveMetricsValues does nothing, replayed check must not change stored metrics
+func (dataBase *backtestDatabase) RemoveMetricsValues(metrics []string, toTime int64) error {
+	return nil
+}
+
+// Backtest replays checks of trigger every step seconds from from until until over historical data of metric source.
+// Neither check data is saved nor notification events are pushed
+func Backtest(trigger *moira.Trigger, from, until, step int64, dataBase moira.Database, logger moira.Logger, sourceProvider *metricSource.SourceProvider) (*BacktestResult, error) {
+	if step <= 0 {
+		return nil, fmt.Errorf("backtest step must be positive")
+	}
+	source, err := sourceProvider.GetTriggerMetricSource(trigger)
+	if err != nil {
+		return nil, err
+	}
+
+	window, err := expression.GetTriggerWindow(trigger)
+	if err != nil {
+		return nil, err
+	}
+
+	backtestDataBase := &backtestDatabase{Database: dataBase}
+	checkMetrics := metrics.ConfigureCheckerMetrics(metrics.NewDummyRegistry(), true).GetCheckMetrics(trigger)
+	lastCheck := &moira.CheckData{
+		Metrics:   make(map[string]moira.MetricState),
+		State:     moira.StateOK,
+		Timestamp: from,
+	}
+	result := &BacktestResult{
+		Checks: make([]moira.CheckData, 0),
+		Events: make([]moira.NotificationEvent, 0),
+	}
+	for checkTimestamp := from + step; checkTimestamp <= until; checkTimestamp += step {
+		triggerChecker := &TriggerChecker{
+			database: backtestDataBase,
+			logger:   logger,
+			config:   &Config{},
+			metrics:  checkMetrics,
+			source:   source,
+
+			from:   calculateFrom(lastCheck.Timestamp, trigger.TTL),
+			until:  checkTimestamp,
+			window: window,
+
+			triggerID: trigger.ID,
+			trigger:   trigger,
+			lastCheck: lastCheck,
+
+			ttl:      trigger.TTL,
+			ttlState: getTTLState(trigger.TTLState),
+		}
+		backtestDataBase.lastCheck = nil
+		if err := triggerChecker.Check(); err != nil {
+			return nil, err
+		}
+		if backtestDataBase.lastCheck == nil {
+			continue
+		}
+		lastCheck = backtestDataBase.lastCheck
+		result.Checks = append(result.Checks, *lastCheck)
+	}
+	result.Events = append(result.Events, backtestDataBase.events...)
+	return result, nil
+}
@@ -0,0 +1,93 @@
+package checker
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/moira-alert/moira"
+	logging "github.com/moira-alert/moira/logging/zerolog_adapter"
+	metricSource "github.com/moira-alert/moira/metric_source"
+	mock_metric_source "github.com/moira-alert/moira/mock/metric_source"
+	mock_moira_alert "github.com/moira-alert/moira/mock/moira-alert"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestBacktest(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	dataBase := mock_moira_alert.NewMockDatabase(mockCtrl)
+	localSource := mock_metric_source.NewMockMetricSource(mockCtrl)
+	remoteSource := mock_metric_source.NewMockMetricSource(mockCtrl)
+	sourceProvider := metricSource.CreateMetricSourceProvider(localSource, remoteSource, nil)
+	logger, _ := logging.GetLogger("Test")
+
+	const metric = "super.puper.metric"
+	var step int64 = 60
+	// metric is above error value from 300 until 540
+	getValue := func(timestamp int64) float64 {
+		if timestamp >= 300 && timestamp < 540 {
+			return 30
+		}
+		return 1
+	}
+
+	localSource.EXPECT().IsConfigured().Return(true, nil).AnyTimes()
+	localSource.EXPECT().Fetch("super.puper.pattern", gomock.Any(), gomock.Any(), true).DoAndReturn(
+		func(target string, from, until int64, allowRealTimeAlerting bool) (metricSource.FetchResult, error) {
+			start := from - from%step
+			values := make([]float64, 0)
+			for timestamp := start; timestamp <= until; timestamp += step {
+				values = append(values, getValue(timestamp))
+			}
+			fetchResult := mock_metric_source.NewMockFetchResult(mockCtrl)
+			fetchResult.EXPECT().GetMetricsData().Return([]metricSource.MetricData{*metricSource.MakeMetricData(metric, values, step, start)})
+			fetchResult.EXPECT().GetPatternMetrics().Return([]string{metric}, nil)
+			return fetchResult, nil
+		}).AnyTimes()
+	dataBase.EXPECT().GetMetricsTTLSeconds().Return(int64(3600)).AnyTimes()
+
+	warnValue := 10.0
+	errorValue := 20.0
+	trigger := &moira.Trigger{
+		ID:          "SuperId",
+		Name:        "Super trigger",
+		TriggerType: moira.RisingTrigger,
+		WarnValue:   &warnValue,
+		ErrorValue:  &errorValue,
+		Targets:     []string{"super.puper.pattern"},
+		Patterns:    []string{"super.puper.pattern"},
+		TTL:         600,
+		Parents:     []string{"ParentId"},
+	}
+
+	Convey("Test backtest", t, func() {
+		Convey("Replays checks and collects events without saving them", func() {
+			result, err := Backtest(trigger, 0, 900, step, dataBase, logger, sourceProvider)
+			So(err, ShouldBeNil)
+			So(result.Checks, ShouldHaveLength, 15)
+			for i, checkData := range result.Checks {
+				So(checkData.Timestamp, ShouldEqual, int64(i+1)*step)
+			}
+			So(result.Checks[3].Metrics[metric].State, ShouldEqual, moira.StateOK)
+			So(result.Checks[5].Metrics[metric].State, ShouldEqual, moira.StateERROR)
+			So(result.Checks[14].Metrics[metric].State, ShouldEqual, moira.StateOK)
+
+			So(result.Events, ShouldHaveLength, 3)
+			So(result.Events[0].Metric, ShouldEqual, metric)
+			So(result.Events[0].State, ShouldEqual, moira.StateOK)
+			So(result.Events[0].OldState, ShouldEqual, moira.StateNODATA)
+			So(result.Events[0].Timestamp, ShouldEqual, 60)
+			So(result.Events[1].State, ShouldEqual, moira.StateERROR)
+			So(result.Events[1].OldState, ShouldEqual, moira.StateOK)
+			So(result.Events[1].Timestamp, ShouldEqual, 300)
+			So(result.Events[2].State, ShouldEqual, moira.StateOK)
+			So(result.Events[2].OldState, ShouldEqual, moira.StateERROR)
+			So(result.Events[2].Timestamp, ShouldEqual, 540)
+		})
+
+		Convey("Fails with not positive step", func() {
+			_, err := Backtest(trigger, 0, 900, 0, dataBase, logger, sourceProvider)
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
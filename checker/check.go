@@ -54,6 +54,12 @@ func (triggerChecker *TriggerChecker) Check() error {
 	return triggerChecker.database.SetTriggerLastCheck(triggerChecker.triggerID, &checkData, triggerChecker.trigger.IsRemote)
 }
 
+// IsSourceSuspended returns true if requests to trigger metric source are suspended, trigger can't be checked then
+func (triggerChecker *TriggerChecker) IsSourceSuspended() bool {
+	source, ok := triggerChecker.source.(metricSource.SuspendableSource)
+	return ok && source.IsSuspended()
+}
+
 // handlePrepareError is a function that checks error returned from prepareMetrics function. If error
 // is not serious and check process can be continued first return value became true and Filled CheckData returned.
 // in the other case first return value became true and error passed to this function is handled.
@@ -85,7 +91,7 @@ func (triggerChecker *TriggerChecker) handlePrepareError(checkData moira.CheckDa
 
 // handleFetchError is a function that checks error returned from fetchTriggerMetrics function.
 func (triggerChecker *TriggerChecker) handleFetchError(checkData moira.CheckData, err error) error {
-	switch err.(type) {
+	switch typedErr := err.(type) {
 	case ErrTriggerHasEmptyTargets, ErrTriggerHasOnlyWildcards:
 		triggerChecker.logger.Debugf("Trigger %s: %s", triggerChecker.triggerID, err.Error())
 		triggerState := triggerChecker.ttlState.ToTriggerState()
@@ -98,10 +104,17 @@ func (triggerChecker *TriggerChecker) handleFetchError(checkData moira.CheckData
 			return triggerChecker.database.SetTriggerLastCheck(triggerChecker.triggerID, &checkData, triggerChecker.trigger.IsRemote)
 		}
 	case remote.ErrRemoteTriggerResponse:
+		// Rejected target won't be accepted by remote server on next check, so there is no reason to wait
+		if _, ok := typedErr.InternalError.(remote.ErrRemoteBadTarget); ok {
+			checkData.State = moira.StateEXCEPTION
+			checkData.Message = typedErr.Reason()
+			triggerChecker.logger.Warning(formatTriggerCheckException(triggerChecker.triggerID, err))
+			break
+		}
 		timeSinceLastSuccessfulCheck := checkData.Timestamp - checkData.LastSuccessfulCheckTimestamp
 		if timeSinceLastSuccessfulCheck >= triggerChecker.ttl {
 			checkData.State = moira.StateEXCEPTION
-			checkData.Message = fmt.Sprintf("Remote server unavailable. Trigger is not checked for %d seconds", timeSinceLastSuccessfulCheck)
+			checkData.Message = fmt.Sprintf("%s. Trigger is not checked for %d seconds", typedErr.Reason(), timeSinceLastSuccessfulCheck)
 			checkData, err = triggerChecker.compareTriggerStates(checkData)
 		}
 		triggerChecker.logger.Warning(formatTriggerCheckException(triggerChecker.triggerID, err))
@@ -314,7 +327,7 @@ func (triggerChecker *TriggerChecker) getMetricStepsStates(metricName string, me
 	var stepTime int64
 
 	for _, metric := range metrics { // Taking values from any metric
-		last = triggerChecker.lastCheck.GetOrCreateMetricState(metricName, metric.StartTime-secondsInHour, triggerChecker.trigger.MuteNewMetrics)
+		last = triggerChecker.lastCheck.GetOrCreateMetricState(metricName, moira.MaxInt64(metric.StartTime, triggerChecker.from)-secondsInHour, triggerChecker.trigger.MuteNewMetrics)
 		startTime = metric.StartTime
 		stepTime = metric.StepTime
 		break
@@ -330,7 +343,12 @@ func (triggerChecker *TriggerChecker) getMetricStepsStates(metricName string, me
 	// DO NOT CHANGE
 	// Specific optimization magic
 	previousState := last
-	difference := moira.MaxInt64(checkPoint-startTime, 0)
+	// Values of expression window preceding checked period are only read by window functions, they are not checked
+	checkFrom := checkPoint
+	if triggerChecker.window > 0 {
+		checkFrom = moira.MaxInt64(checkPoint, triggerChecker.from)
+	}
+	difference := moira.MaxInt64(checkFrom-startTime, 0)
 	stepsDifference := difference / stepTime
 	if (difference % stepTime) > 0 {
 		stepsDifference++
@@ -368,6 +386,26 @@ func (triggerChecker *TriggerChecker) getMetricDataState(metrics *map[string]met
 	triggerExpression.TriggerType = triggerChecker.trigger.TriggerType
 	triggerExpression.PreviousState = lastState.State
 	triggerExpression.Expression = triggerChecker.trigger.Expression
+	if override := triggerChecker.getThresholdOverride((*metrics)["t1"].Name); override != nil {
+		triggerExpression.WarnValue = override.WarnValue
+		triggerExpression.ErrorValue = override.ErrorValue
+		triggerExpression.Expression = &override.Expression
+	}
+	switch triggerChecker.trigger.TriggerType {
+	case moira.ExpressionTrigger:
+		triggerExpression.Series = getExpressionSeries(metrics)
+		triggerExpression.Timestamp = *valueTimestamp
+	case moira.AnomalyTrigger:
+		triggerExpression.Anomaly = triggerChecker.trigger.Anomaly
+		triggerExpression.BaselineValues = triggerChecker.getAnomalyBaseline((*metrics)["t1"].Name, *valueTimestamp)
+	case moira.ForecastTrigger:
+		triggerExpression.Forecast = triggerChecker.trigger.Forecast
+		if forecastTimestamp, ok := triggerChecker.getForecastTimestamp((*metrics)["t1"].Name, *valueTimestamp); ok {
+			timeLeft := forecastTimestamp - *valueTimestamp
+			triggerExpression.ForecastTimeLeft = &timeLeft
+			values[moira.ForecastValueName] = float64(timeLeft)
+		}
+	}
 
 	expressionState, err := triggerExpression.Evaluate()
 	if err != nil {
@@ -382,6 +420,15 @@ func (triggerChecker *TriggerChecker) getMetricDataState(metrics *map[string]met
 	), nil
 }
 
+// getExpressionSeries returns fetched values of targets read by expression window functions
+func getExpressionSeries(metrics *map[string]metricSource.MetricData) map[string]expression.Series {
+	series := make(map[string]expression.Series, len(*metrics))
+	for targetName, metric := range *metrics {
+		series[targetName] = expression.Series{StartTime: metric.StartTime, StepTime: metric.StepTime, Values: metric.Values}
+	}
+	return series
+}
+
 func getExpressionValues(metrics *map[string]metricSource.MetricData, valueTimestamp *int64) (*expression.TriggerExpression, map[string]float64, bool) {
 	expression := &expression.TriggerExpression{
 		AdditionalTargetsValues: make(map[string]float64, len(*metrics)-1),
@@ -13,6 +13,7 @@ import (
 	logging "github.com/moira-alert/moira/logging/zerolog_adapter"
 	metricSource "github.com/moira-alert/moira/metric_source"
 	"github.com/moira-alert/moira/metric_source/local"
+	"github.com/moira-alert/moira/metric_source/remote"
 
 	"github.com/moira-alert/moira/metrics"
 	mock_metric_source "github.com/moira-alert/moira/mock/metric_source"
@@ -1497,3 +1498,119 @@ func TestTriggerChecker_handlePrepareError(t *testing.T) {
 		})
 	})
 }
+
+func TestGetMetricDataStateWithWindowFunctions(t *testing.T) {
+	logger, _ := logging.GetLogger("Test")
+	expression := "count_over_time(t1, 300) >= 3 && avg_over_time(t1, 300) > 90 ? ERROR : OK"
+	triggerChecker := TriggerChecker{
+		logger:  logger,
+		trigger: &moira.Trigger{TriggerType: moira.ExpressionTrigger, Expression: &expression},
+	}
+	metrics := map[string]metricSource.MetricData{
+		"t1": *metricSource.MakeMetricData("main.metric", []float64{95, 99, math.NaN(), 97, 20}, 60, 0),
+	}
+	var checkPoint int64
+
+	Convey("Window functions read values preceding checked timestamp", t, func() {
+		var valueTimestamp int64 = 180
+		metricState, err := triggerChecker.getMetricDataState(&metrics, &moira.MetricState{}, &valueTimestamp, &checkPoint, logger)
+		So(err, ShouldBeNil)
+		So(metricState.State, ShouldEqual, moira.StateERROR)
+
+		valueTimestamp = 240
+		metricState, err = triggerChecker.getMetricDataState(&metrics, &moira.MetricState{}, &valueTimestamp, &checkPoint, logger)
+		So(err, ShouldBeNil)
+		So(metricState.State, ShouldEqual, moira.StateOK)
+	})
+}
+
+func TestGetMetricStepsStatesWithExpressionWindow(t *testing.T) {
+	logger, _ := logging.GetLogger("Test")
+	expression := "avg_over_time(t1, 300) > 90 ? ERROR : OK"
+	triggerChecker := TriggerChecker{
+		logger:    logger,
+		trigger:   &moira.Trigger{TriggerType: moira.ExpressionTrigger, Expression: &expression},
+		lastCheck: &moira.CheckData{Metrics: make(map[string]moira.MetricState)},
+		from:      300,
+		until:     540,
+		window:    300,
+	}
+	metrics := map[string]metricSource.MetricData{
+		"t1": *metricSource.MakeMetricData("main.metric", []float64{95, 95, 95, 95, 95, 95, 95, 95, 95, 95}, 60, 0),
+	}
+
+	Convey("Values of expression window are not checked for new metric", t, func() {
+		last, current, err := triggerChecker.getMetricStepsStates("main.metric", metrics, logger)
+		So(err, ShouldBeNil)
+		So(last.Timestamp, ShouldEqual, triggerChecker.from-secondsInHour)
+		So(current, ShouldHaveLength, 5)
+		So(current[0].Timestamp, ShouldEqual, triggerChecker.from)
+		So(current[0].State, ShouldEqual, moira.StateERROR)
+	})
+}
+
+func TestTriggerChecker_handleFetchError(t *testing.T) {
+	Convey("Test handleFetchError with remote errors", t, func() {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+		dataBase := mock_moira_alert.NewMockDatabase(mockCtrl)
+		logger, _ := logging.GetLogger("Test")
+
+		triggerChecker := TriggerChecker{
+			triggerID: "test trigger",
+			trigger:   &moira.Trigger{IsRemote: true},
+			database:  dataBase,
+			logger:    logger,
+			ttl:       600,
+			lastCheck: &moira.CheckData{
+				State:          moira.StateOK,
+				EventTimestamp: 10,
+			},
+		}
+		checkData := moira.CheckData{
+			State:                        moira.StateOK,
+			Timestamp:                    15,
+			LastSuccessfulCheckTimestamp: 10,
+		}
+
+		Convey("rejected target sets exception state immediately", func() {
+			err := remote.ErrRemoteTriggerResponse{
+				InternalError: remote.ErrRemoteBadTarget{StatusCode: 400, Body: "unknown function"},
+				Target:        "foo(bar)",
+			}
+			expectedCheckData := moira.CheckData{
+				Score:                        100000,
+				State:                        moira.StateEXCEPTION,
+				Message:                      "Remote server rejected target foo(bar): unknown function",
+				Timestamp:                    15,
+				EventTimestamp:               15,
+				LastSuccessfulCheckTimestamp: 10,
+			}
+			dataBase.EXPECT().PushNotificationEvent(&moira.NotificationEvent{
+				IsTriggerEvent:   true,
+				TriggerID:        triggerChecker.triggerID,
+				State:            moira.StateEXCEPTION,
+				OldState:         getEventOldState(moira.StateOK, "", false),
+				Timestamp:        15,
+				MessageEventInfo: nil,
+			}, true)
+			dataBase.EXPECT().SetTriggerLastCheck("test trigger", &expectedCheckData, true)
+			So(triggerChecker.handleFetchError(checkData, err), ShouldBeNil)
+		})
+
+		Convey("remote timeout doesn't change state until trigger TTL is over", func() {
+			err := remote.ErrRemoteTriggerResponse{
+				InternalError: remote.ErrRemoteTimeout{Timeout: time.Minute},
+				Target:        "foo.bar",
+			}
+			expectedCheckData := moira.CheckData{
+				State:                        moira.StateOK,
+				Timestamp:                    15,
+				EventTimestamp:               10,
+				LastSuccessfulCheckTimestamp: 10,
+			}
+			dataBase.EXPECT().SetTriggerLastCheck("test trigger", &expectedCheckData, true)
+			So(triggerChecker.handleFetchError(checkData, err), ShouldBeNil)
+		})
+	})
+}
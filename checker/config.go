@@ -16,4 +16,12 @@ type Config struct {
 	LogFile                     string
 	LogLevel                    string
 	LogTriggersToLevel          map[string]string
+	Sharding                    ShardingConfig
+}
+
+// ShardingConfig represent config of triggers sharding between checker instances
+type ShardingConfig struct {
+	Enabled           bool
+	HeartbeatInterval time.Duration
+	InstanceTimeout   time.Duration
 }
@@ -0,0 +1,52 @@
+package checker
+
+import (
+	"fmt"
+
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/database"
+)
+
+// getParentsSuppressReason returns reason to suppress trigger events if any of parent triggers is in ERROR or NODATA state
+// and empty string otherwise. Parent triggers states are requested once per check
+func (triggerChecker *TriggerChecker) getParentsSuppressReason() string {
+	if triggerChecker.parentsSuppressReason == nil {
+		reason := triggerChecker.findBadParent()
+		triggerChecker.parentsSuppressReason = &reason
+	}
+	return *triggerChecker.parentsSuppressReason
+}
+
+func (triggerChecker *TriggerChecker) findBadParent() string {
+	for _, parentID := range triggerChecker.trigger.Parents {
+		parentCheck, err := triggerChecker.database.GetTriggerLastCheck(parentID)
+		if err != nil {
+			if err != database.ErrNil {
+				triggerChecker.logger.Warningf("Failed to get last check of parent trigger %s: %s", parentID, err.Error())
+			}
+			continue
+		}
+		if state := getWorstParentState(parentCheck); state != "" {
+			return fmt.Sprintf("parent trigger %s is in %s state", parentID, state)
+		}
+	}
+	return ""
+}
+
+// getWorstParentState returns ERROR or NODATA if trigger or any of its metrics is in such state and empty state otherwise
+func getWorstParentState(checkData moira.CheckData) moira.State {
+	worstState := moira.State("")
+	states := []moira.State{checkData.State}
+	for _, metricState := range checkData.Metrics {
+		states = append(states, metricState.State)
+	}
+	for _, state := range states {
+		switch state {
+		case moira.StateERROR:
+			return moira.StateERROR
+		case moira.StateNODATA:
+			worstState = moira.StateNODATA
+		}
+	}
+	return worstState
+}
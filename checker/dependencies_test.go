@@ -0,0 +1,116 @@
+package checker
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/database"
+	logging "github.com/moira-alert/moira/logging/zerolog_adapter"
+	mock_moira_alert "github.com/moira-alert/moira/mock/moira-alert"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestGetWorstParentState(t *testing.T) {
+	Convey("Test worst parent state", t, func() {
+		So(getWorstParentState(moira.CheckData{State: moira.StateOK}), ShouldBeEmpty)
+		So(getWorstParentState(moira.CheckData{State: moira.StateNODATA}), ShouldEqual, moira.StateNODATA)
+		So(getWorstParentState(moira.CheckData{
+			State: moira.StateOK,
+			Metrics: map[string]moira.MetricState{
+				"metric1": {State: moira.StateNODATA},
+				"metric2": {State: moira.StateERROR},
+				"metric3": {State: moira.StateWARN},
+			},
+		}), ShouldEqual, moira.StateERROR)
+		So(getWorstParentState(moira.CheckData{
+			State:   moira.StateOK,
+			Metrics: map[string]moira.MetricState{"metric1": {State: moira.StateWARN}},
+		}), ShouldBeEmpty)
+	})
+}
+
+func TestGetParentsSuppressReason(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	logger, _ := logging.GetLogger("Test")
+	dataBase := mock_moira_alert.NewMockDatabase(mockCtrl)
+
+	Convey("Trigger without parents", t, func() {
+		triggerChecker := TriggerChecker{database: dataBase, logger: logger, trigger: &moira.Trigger{}}
+		So(triggerChecker.getParentsSuppressReason(), ShouldBeEmpty)
+	})
+
+	Convey("Parents are requested once", t, func() {
+		triggerChecker := TriggerChecker{database: dataBase, logger: logger, trigger: &moira.Trigger{Parents: []string{"removed", "broken", "parent"}}}
+		dataBase.EXPECT().GetTriggerLastCheck("removed").Return(moira.CheckData{}, database.ErrNil)
+		dataBase.EXPECT().GetTriggerLastCheck("broken").Return(moira.CheckData{}, fmt.Errorf("oops"))
+		dataBase.EXPECT().GetTriggerLastCheck("parent").Return(moira.CheckData{State: moira.StateNODATA}, nil)
+		So(triggerChecker.getParentsSuppressReason(), ShouldEqual, "parent trigger parent is in NODATA state")
+		So(triggerChecker.getParentsSuppressReason(), ShouldEqual, "parent trigger parent is in NODATA state")
+	})
+}
+
+func TestCompareStatesWithBadParent(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	logger, _ := logging.GetLogger("Test")
+	dataBase := mock_moira_alert.NewMockDatabase(mockCtrl)
+	reason := "parent trigger parent is in ERROR state"
+
+	Convey("Child events are suppressed while parent is in bad state", t, func() {
+		triggerChecker := TriggerChecker{
+			triggerID: "child",
+			database:  dataBase,
+			logger:    logger,
+			trigger:   &moira.Trigger{Parents: []string{"parent"}},
+			lastCheck: &moira.CheckData{Timestamp: 1502712000, State: moira.StateOK},
+		}
+		dataBase.EXPECT().GetTriggerLastCheck("parent").Return(moira.CheckData{
+			State:   moira.StateOK,
+			Metrics: map[string]moira.MetricState{"link": {State: moira.StateERROR}},
+		}, nil)
+
+		actualCheck, err := triggerChecker.compareTriggerStates(moira.CheckData{Timestamp: 1502719200, State: moira.StateNODATA})
+		So(err, ShouldBeNil)
+		So(actualCheck.Suppressed, ShouldBeTrue)
+		So(actualCheck.SuppressedState, ShouldEqual, moira.StateOK)
+		So(actualCheck.SuppressedReason, ShouldEqual, reason)
+
+		actualState, err := triggerChecker.compareMetricStates("metric",
+			moira.MetricState{Timestamp: 1502719200, State: moira.StateERROR},
+			moira.MetricState{Timestamp: 1502712000, State: moira.StateOK})
+		So(err, ShouldBeNil)
+		So(actualState.Suppressed, ShouldBeTrue)
+		So(actualState.SuppressedState, ShouldEqual, moira.StateOK)
+		So(actualState.SuppressedReason, ShouldEqual, reason)
+	})
+
+	Convey("Child events are released when parent recovers", t, func() {
+		triggerChecker := TriggerChecker{
+			triggerID: "child",
+			database:  dataBase,
+			logger:    logger,
+			trigger:   &moira.Trigger{Parents: []string{"parent"}},
+			lastCheck: &moira.CheckData{Timestamp: 1502712000, State: moira.StateOK},
+		}
+		dataBase.EXPECT().GetTriggerLastCheck("parent").Return(moira.CheckData{State: moira.StateOK}, nil)
+		dataBase.EXPECT().PushNotificationEvent(&moira.NotificationEvent{
+			TriggerID:        "child",
+			State:            moira.StateERROR,
+			OldState:         moira.StateOK,
+			Timestamp:        1502719200,
+			Metric:           "metric",
+			MessageEventInfo: &moira.EventInfo{SuppressedReason: reason},
+		}, true).Return(nil)
+
+		actualState, err := triggerChecker.compareMetricStates("metric",
+			moira.MetricState{Timestamp: 1502719200, State: moira.StateERROR},
+			moira.MetricState{Timestamp: 1502712000, State: moira.StateERROR, Suppressed: true, SuppressedState: moira.StateOK, SuppressedReason: reason})
+		So(err, ShouldBeNil)
+		So(actualState.Suppressed, ShouldBeFalse)
+		So(actualState.SuppressedState, ShouldBeEmpty)
+		So(actualState.SuppressedReason, ShouldBeEmpty)
+	})
+}
@@ -32,19 +32,21 @@ func (triggerChecker *TriggerChecker) compareTriggerStates(currentCheck moira.Ch
 	currentCheck.SuppressedState = lastStateSuppressedValue
 
 	maintenanceInfo, maintenanceTimestamp := getMaintenanceInfo(lastCheck, nil)
-	eventInfo, needSend := isStateChanged(currentStateValue, lastStateValue, currentCheckTimestamp, lastCheck.GetEventTimestamp(), lastStateSuppressed, lastStateSuppressedValue, maintenanceInfo)
+	eventInfo, needSend := isStateChanged(currentStateValue, lastStateValue, currentCheckTimestamp, lastCheck.GetEventTimestamp(), lastStateSuppressed, lastStateSuppressedValue, lastCheck.SuppressedReason, maintenanceInfo)
 	if !needSend {
 		if maintenanceTimestamp < currentCheckTimestamp {
 			currentCheck.Suppressed = false
 			currentCheck.SuppressedState = ""
+			currentCheck.SuppressedReason = ""
 		}
 		return currentCheck, nil
 	}
 
 	currentCheck.EventTimestamp = currentCheckTimestamp
 
-	if triggerChecker.isTriggerSuppressed(currentCheckTimestamp, maintenanceTimestamp) {
+	if suppressed, reason := triggerChecker.getSuppression(currentCheckTimestamp, maintenanceTimestamp); suppressed {
 		currentCheck.Suppressed = true
+		currentCheck.SuppressedReason = reason
 		if !lastStateSuppressed {
 			currentCheck.SuppressedState = lastStateValue
 		}
@@ -53,6 +55,7 @@ func (triggerChecker *TriggerChecker) compareTriggerStates(currentCheck moira.Ch
 
 	currentCheck.Suppressed = false
 	currentCheck.SuppressedState = ""
+	currentCheck.SuppressedReason = ""
 
 	err := triggerChecker.database.PushNotificationEvent(&moira.NotificationEvent{
 		IsTriggerEvent:   true,
@@ -67,6 +70,9 @@ func (triggerChecker *TriggerChecker) compareTriggerStates(currentCheck moira.Ch
 }
 
 func (triggerChecker *TriggerChecker) compareMetricStates(metric string, currentState moira.MetricState, lastState moira.MetricState) (moira.MetricState, error) {
+	currentState = triggerChecker.holdPendingState(currentState, lastState)
+	currentState = triggerChecker.updateFlapping(currentState, lastState)
+
 	// Just set check info
 	// TODO: make sure that this logic can be moved to current state initialization
 	if lastState.EventTimestamp != 0 {
@@ -82,11 +88,15 @@ func (triggerChecker *TriggerChecker) compareMetricStates(metric string, current
 	currentState.SuppressedState = lastState.SuppressedState
 
 	maintenanceInfo, maintenanceTimestamp := getMaintenanceInfo(triggerChecker.lastCheck, &currentState)
-	eventInfo, needSend := isStateChanged(currentState.State, lastState.State, currentState.Timestamp, lastState.GetEventTimestamp(), lastState.Suppressed, lastState.SuppressedState, maintenanceInfo)
+	if currentState.Flapping || lastState.Flapping {
+		return triggerChecker.compareFlappingMetricStates(metric, currentState, lastState, maintenanceTimestamp)
+	}
+	eventInfo, needSend := isStateChanged(currentState.State, lastState.State, currentState.Timestamp, lastState.GetEventTimestamp(), lastState.Suppressed, lastState.SuppressedState, lastState.SuppressedReason, maintenanceInfo)
 	if !needSend {
 		if maintenanceTimestamp < currentState.Timestamp {
 			currentState.Suppressed = false
 			currentState.SuppressedState = ""
+			currentState.SuppressedReason = ""
 		}
 		return currentState, nil
 	}
@@ -94,8 +104,9 @@ func (triggerChecker *TriggerChecker) compareMetricStates(metric string, current
 	// State was changed. Set event timestamp. Event will be not sent if it is suppressed
 	currentState.EventTimestamp = currentState.Timestamp
 
-	if triggerChecker.isTriggerSuppressed(currentState.Timestamp, maintenanceTimestamp) {
+	if suppressed, reason := triggerChecker.getSuppression(currentState.Timestamp, maintenanceTimestamp); suppressed {
 		currentState.Suppressed = true
+		currentState.SuppressedReason = reason
 		if !lastState.Suppressed {
 			currentState.SuppressedState = lastState.State
 		}
@@ -104,6 +115,7 @@ func (triggerChecker *TriggerChecker) compareMetricStates(metric string, current
 
 	currentState.Suppressed = false
 	currentState.SuppressedState = ""
+	currentState.SuppressedReason = ""
 
 	err := triggerChecker.database.PushNotificationEvent(&moira.NotificationEvent{
 		TriggerID:        triggerChecker.triggerID,
@@ -128,12 +140,27 @@ func (triggerChecker *TriggerChecker) isTriggerSuppressed(timestamp int64, maint
 	return !triggerChecker.trigger.Schedule.IsScheduleAllows(timestamp) || maintenanceTimestamp >= timestamp
 }
 
-func isStateChanged(currentStateValue moira.State, lastStateValue moira.State, currentStateTimestamp int64, lastStateEventTimestamp int64, isLastCheckSuppressed bool, lastStateSuppressedValue moira.State, maintenanceInfo moira.MaintenanceInfo) (*moira.EventInfo, bool) {
+// getSuppression returns true if event must be suppressed by schedule, maintenance or parent trigger
+// and reason of suppression by parent trigger
+func (triggerChecker *TriggerChecker) getSuppression(timestamp int64, maintenanceTimestamp int64) (bool, string) {
+	if triggerChecker.isTriggerSuppressed(timestamp, maintenanceTimestamp) {
+		return true, ""
+	}
+	reason := triggerChecker.getParentsSuppressReason()
+	return reason != "", reason
+}
+
+// isStateChanged returns true if event must be sent and info to make event message with.
+// Reason is empty for state suppressed by maintenance or schedule and describes other suppression, e.g. by parent trigger
+func isStateChanged(currentStateValue moira.State, lastStateValue moira.State, currentStateTimestamp int64, lastStateEventTimestamp int64, isLastCheckSuppressed bool, lastStateSuppressedValue moira.State, lastStateSuppressedReason string, maintenanceInfo moira.MaintenanceInfo) (*moira.EventInfo, bool) {
 	if !isLastCheckSuppressed && currentStateValue != lastStateValue {
 		return nil, true
 	}
 
 	if isLastCheckSuppressed && currentStateValue != lastStateSuppressedValue {
+		if lastStateSuppressedReason != "" {
+			return &moira.EventInfo{SuppressedReason: lastStateSuppressedReason}, true
+		}
 		return &moira.EventInfo{Maintenance: &maintenanceInfo}, true
 	}
 
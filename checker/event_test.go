@@ -664,29 +664,35 @@ func TestIsStateChanged(t *testing.T) {
 		Convey("Test is state changed", func() {
 			Convey("If is last check suppressed and current state not equal last state", func() {
 				lastCheckTest.Suppressed = false
-				eventInfo, needSend := isStateChanged(currentCheckTest.State, lastCheckTest.State, currentCheckTest.Timestamp, lastCheckTest.GetEventTimestamp()-1, lastCheckTest.Suppressed, lastCheckTest.SuppressedState, moira.MaintenanceInfo{})
+				eventInfo, needSend := isStateChanged(currentCheckTest.State, lastCheckTest.State, currentCheckTest.Timestamp, lastCheckTest.GetEventTimestamp()-1, lastCheckTest.Suppressed, lastCheckTest.SuppressedState, "", moira.MaintenanceInfo{})
 				So(eventInfo, ShouldBeNil)
 				So(needSend, ShouldBeTrue)
 			})
 
 			Convey("Create EventInfo with MaintenanceInfo", func() {
 				maintenanceInfo := moira.MaintenanceInfo{}
-				eventInfo, needSend := isStateChanged(currentCheckTest.State, lastCheckTest.State, currentCheckTest.Timestamp, lastCheckTest.GetEventTimestamp(), lastCheckTest.Suppressed, lastCheckTest.SuppressedState, maintenanceInfo)
+				eventInfo, needSend := isStateChanged(currentCheckTest.State, lastCheckTest.State, currentCheckTest.Timestamp, lastCheckTest.GetEventTimestamp(), lastCheckTest.Suppressed, lastCheckTest.SuppressedState, "", maintenanceInfo)
 				So(eventInfo, ShouldNotBeNil)
 				So(eventInfo, ShouldResemble, &moira.EventInfo{Maintenance: &maintenanceInfo})
 				So(needSend, ShouldBeTrue)
 			})
 
+			Convey("Create EventInfo with suppression reason", func() {
+				eventInfo, needSend := isStateChanged(currentCheckTest.State, lastCheckTest.State, currentCheckTest.Timestamp, lastCheckTest.GetEventTimestamp(), lastCheckTest.Suppressed, lastCheckTest.SuppressedState, "parent trigger parent-id is in ERROR state", moira.MaintenanceInfo{})
+				So(eventInfo, ShouldResemble, &moira.EventInfo{SuppressedReason: "parent trigger parent-id is in ERROR state"})
+				So(needSend, ShouldBeTrue)
+			})
+
 			Convey("Create EventInfo with interval", func() {
 				var interval int64 = 24
-				eventInfo, needSend := isStateChanged(moira.StateNODATA, lastCheckTest.State, currentCheckTest.Timestamp, lastCheckTest.GetEventTimestamp()-100000, lastCheckTest.Suppressed, moira.StateNODATA, moira.MaintenanceInfo{})
+				eventInfo, needSend := isStateChanged(moira.StateNODATA, lastCheckTest.State, currentCheckTest.Timestamp, lastCheckTest.GetEventTimestamp()-100000, lastCheckTest.Suppressed, moira.StateNODATA, "", moira.MaintenanceInfo{})
 				So(eventInfo, ShouldNotBeNil)
 				So(eventInfo, ShouldResemble, &moira.EventInfo{Interval: &interval})
 				So(needSend, ShouldBeTrue)
 			})
 
 			Convey("No send message", func() {
-				eventInfo, needSend := isStateChanged(moira.StateNODATA, lastCheckTest.State, currentCheckTest.Timestamp, lastCheckTest.GetEventTimestamp(), lastCheckTest.Suppressed, moira.StateNODATA, moira.MaintenanceInfo{})
+				eventInfo, needSend := isStateChanged(moira.StateNODATA, lastCheckTest.State, currentCheckTest.Timestamp, lastCheckTest.GetEventTimestamp(), lastCheckTest.Suppressed, moira.StateNODATA, "", moira.MaintenanceInfo{})
 				So(eventInfo, ShouldBeNil)
 				So(needSend, ShouldBeFalse)
 			})
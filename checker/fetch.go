@@ -3,6 +3,7 @@ package checker
 import (
 	"fmt"
 
+	"github.com/moira-alert/moira"
 	"github.com/moira-alert/moira/checker/metrics/conversion"
 	metricSource "github.com/moira-alert/moira/metric_source"
 )
@@ -14,6 +15,10 @@ func (triggerChecker *TriggerChecker) fetchTriggerMetrics() (map[string][]metric
 	}
 	triggerChecker.cleanupMetricsValues(metrics, triggerChecker.until)
 
+	if err := triggerChecker.fetchHistory(); err != nil {
+		return triggerMetricsData, err
+	}
+
 	if len(triggerChecker.lastCheck.Metrics) == 0 {
 		if hasEmptyTargets, emptyTargets := conversion.HasEmptyTargets(triggerMetricsData); hasEmptyTargets {
 			return nil, ErrTriggerHasEmptyTargets{targets: emptyTargets}
@@ -31,12 +36,21 @@ func (triggerChecker *TriggerChecker) fetch() (map[string][]metricSource.MetricD
 	metricsArr := make([]string, 0)
 
 	isSimpleTrigger := triggerChecker.trigger.IsSimple()
+	batchFetchResults, err := triggerChecker.fetchBatch(triggerChecker.trigger.Targets, isSimpleTrigger)
+	if err != nil {
+		return nil, nil, err
+	}
 	for targetIndex, target := range triggerChecker.trigger.Targets {
-		targetIndex++ // increasing target index to have target names started from 1 instead of 0
-		fetchResult, err := triggerChecker.source.Fetch(target, triggerChecker.from, triggerChecker.until, isSimpleTrigger)
-		if err != nil {
-			return nil, nil, err
+		var fetchResult metricSource.FetchResult
+		if batchFetchResults != nil {
+			fetchResult = batchFetchResults[targetIndex]
+		} else {
+			fetchResult, err = triggerChecker.source.Fetch(target, triggerChecker.from-triggerChecker.window, triggerChecker.until, isSimpleTrigger)
+			if err != nil {
+				return nil, nil, err
+			}
 		}
+		targetIndex++ // increasing target index to have target names started from 1 instead of 0
 		metricsData := fetchResult.GetMetricsData()
 
 		metricsFetchResult, metricsErr := fetchResult.GetPatternMetrics()
@@ -51,6 +65,54 @@ func (triggerChecker *TriggerChecker) fetch() (map[string][]metricSource.MetricD
 	return triggerMetricsData, metricsArr, nil
 }
 
+// fetchBatch fetches all targets over checked period by one batch if metric source supports it, returns nil results otherwise
+func (triggerChecker *TriggerChecker) fetchBatch(targets []string, allowRealTimeAlerting bool) ([]metricSource.FetchResult, error) {
+	batchFetcher, ok := triggerChecker.source.(metricSource.BatchFetcher)
+	if !ok || len(targets) < 2 { //nolint
+		return nil, nil
+	}
+	return batchFetcher.FetchBatch(targets, triggerChecker.from-triggerChecker.window, triggerChecker.until, allowRealTimeAlerting)
+}
+
+// fetchHistory fetches main target values preceding checked period for triggers which judge values by history
+func (triggerChecker *TriggerChecker) fetchHistory() error {
+	from, until, ok := triggerChecker.getHistoryRange()
+	if !ok {
+		return nil
+	}
+	fetchResult, err := triggerChecker.source.Fetch(triggerChecker.trigger.Targets[0], from, until, false)
+	if err != nil {
+		return err
+	}
+
+	triggerChecker.history = make(map[string]metricSource.MetricData)
+	for _, metricData := range fetchResult.GetMetricsData() {
+		if metricData.Wildcard {
+			continue
+		}
+		triggerChecker.history[metricData.Name] = metricData
+	}
+	return nil
+}
+
+// getHistoryRange returns period of history required to check all timestamps from checked period
+func (triggerChecker *TriggerChecker) getHistoryRange() (int64, int64, bool) {
+	switch trigger := triggerChecker.trigger; trigger.TriggerType {
+	case moira.AnomalyTrigger:
+		if trigger.Anomaly == nil {
+			return 0, 0, false
+		}
+		return triggerChecker.from - trigger.Anomaly.Season*int64(trigger.Anomaly.Seasons) - trigger.Anomaly.Window,
+			triggerChecker.until - trigger.Anomaly.Season + trigger.Anomaly.Window, true
+	case moira.ForecastTrigger:
+		if trigger.Forecast == nil {
+			return 0, 0, false
+		}
+		return triggerChecker.from - trigger.Forecast.Lookback, triggerChecker.until, true
+	}
+	return 0, 0, false
+}
+
 func (triggerChecker *TriggerChecker) cleanupMetricsValues(metrics []string, until int64) {
 	if len(metrics) > 0 {
 		if err := triggerChecker.database.RemoveMetricsValues(metrics, until-triggerChecker.database.GetMetricsTTLSeconds()); err != nil {
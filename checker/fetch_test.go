@@ -3,10 +3,12 @@ package checker
 import (
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/moira-alert/moira"
 	metricSource "github.com/moira-alert/moira/metric_source"
+	"github.com/moira-alert/moira/metrics"
 	mock_metric_source "github.com/moira-alert/moira/mock/metric_source"
 	mock_moira_alert "github.com/moira-alert/moira/mock/moira-alert"
 	. "github.com/smartystreets/goconvey/convey"
@@ -215,5 +217,98 @@ func TestFetch(t *testing.T) {
 			So(actual, ShouldResemble, map[string][]metricSource.MetricData{"t1": metricData, "t2": addMetricData})
 			So(metrics, ShouldResemble, []string{metric, addMetric, addMetric2})
 		})
+
+		Convey("Targets are fetched together by batch fetcher", func() {
+			addFetchResult := mock_metric_source.NewMockFetchResult(mockCtrl)
+			batchChecker := *triggerChecker
+			batchChecker.trigger = &moira.Trigger{
+				Targets:  []string{pattern, addPattern, pattern},
+				Patterns: []string{pattern, addPattern},
+			}
+			fetchCacheMetrics := &metrics.FetchCacheMetrics{
+				Hits:   metrics.NewDummyRegistry().NewMeter("hits"),
+				Misses: metrics.NewDummyRegistry().NewMeter("misses"),
+			}
+			batchChecker.source = metricSource.NewFetchCache(source, time.Minute, fetchCacheMetrics)
+
+			metricData := []metricSource.MetricData{*metricSource.MakeMetricData(metric, []float64{0, 1, 2, 3, 4}, retention, from)}
+			addMetricData := []metricSource.MetricData{*metricSource.MakeMetricData(addMetric, []float64{0, 1, 2, 3, 4}, retention, from)}
+
+			// Fetch cache fetches period aligned to its cycle with real time value, which is dropped from result
+			source.EXPECT().Fetch(pattern, int64(-60), int64(120), true).Return(fetchResult, nil)
+			fetchResult.EXPECT().GetMetricsData().Return([]metricSource.MetricData{*metricSource.MakeMetricData(metric, []float64{0, 1, 2, 3, 4, 5}, retention, from)}).Times(2)
+			fetchResult.EXPECT().GetPatternMetrics().Return([]string{metric}, nil).Times(2)
+
+			source.EXPECT().Fetch(addPattern, int64(-60), int64(120), true).Return(addFetchResult, nil)
+			addFetchResult.EXPECT().GetMetricsData().Return([]metricSource.MetricData{*metricSource.MakeMetricData(addMetric, []float64{0, 1, 2, 3, 4, 5}, retention, from)})
+			addFetchResult.EXPECT().GetPatternMetrics().Return([]string{addMetric}, nil)
+
+			actual, metrics, err := batchChecker.fetch()
+			So(err, ShouldBeNil)
+			So(actual, ShouldResemble, map[string][]metricSource.MetricData{"t1": metricData, "t2": addMetricData, "t3": metricData})
+			So(metrics, ShouldResemble, []string{metric, addMetric, metric})
+		})
+	})
+}
+
+func TestFetchHistory(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	source := mock_metric_source.NewMockMetricSource(mockCtrl)
+	fetchResult := mock_metric_source.NewMockFetchResult(mockCtrl)
+	defer mockCtrl.Finish()
+
+	pattern := "super.puper.pattern"
+	metric := "super.puper.metric"
+	triggerChecker := &TriggerChecker{
+		source: source,
+		from:   1000,
+		until:  1100,
+		trigger: &moira.Trigger{
+			Targets:     []string{pattern},
+			TriggerType: moira.AnomalyTrigger,
+			Anomaly:     &moira.AnomalyParams{Season: 300, Seasons: 2, Window: 20},
+		},
+	}
+
+	Convey("Trigger without history", t, func() {
+		checker := &TriggerChecker{source: source, trigger: &moira.Trigger{Targets: []string{pattern}, TriggerType: moira.RisingTrigger}}
+		err := checker.fetchHistory()
+		So(err, ShouldBeNil)
+		So(checker.history, ShouldBeNil)
+	})
+
+	Convey("Error test", t, func() {
+		fetchErr := fmt.Errorf("ooops, fetch error")
+		source.EXPECT().Fetch(pattern, int64(380), int64(820), false).Return(nil, fetchErr)
+		err := triggerChecker.fetchHistory()
+		So(err, ShouldResemble, fetchErr)
+	})
+
+	Convey("Wildcards are skipped", t, func() {
+		metricData := *metricSource.MakeMetricData(metric, []float64{1, 2, 3}, 10, 380)
+		wildcard := metricSource.MetricData{Name: pattern, Wildcard: true}
+		source.EXPECT().Fetch(pattern, int64(380), int64(820), false).Return(fetchResult, nil)
+		fetchResult.EXPECT().GetMetricsData().Return([]metricSource.MetricData{metricData, wildcard})
+		err := triggerChecker.fetchHistory()
+		So(err, ShouldBeNil)
+		So(triggerChecker.history, ShouldResemble, map[string]metricSource.MetricData{metric: metricData})
+	})
+
+	Convey("Forecast trigger fetches lookback period", t, func() {
+		checker := &TriggerChecker{
+			source: source,
+			from:   1000,
+			until:  1100,
+			trigger: &moira.Trigger{
+				Targets:     []string{pattern},
+				TriggerType: moira.ForecastTrigger,
+				Forecast:    &moira.ForecastParams{Lookback: 3600},
+			},
+		}
+		source.EXPECT().Fetch(pattern, int64(-2600), int64(1100), false).Return(fetchResult, nil)
+		fetchResult.EXPECT().GetMetricsData().Return([]metricSource.MetricData{})
+		err := checker.fetchHistory()
+		So(err, ShouldBeNil)
+		So(checker.history, ShouldBeEmpty)
 	})
 }
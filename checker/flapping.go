@@ -0,0 +1,92 @@
+package checker
+
+import (
+	"github.com/moira-alert/moira"
+)
+
+const flappingSuppressReason = "metric is flapping"
+
+// updateFlapping tracks metric state changes within trigger flapping window and sets metric flapping status
+func (triggerChecker *TriggerChecker) updateFlapping(currentState moira.MetricState, lastState moira.MetricState) moira.MetricState {
+	params := triggerChecker.trigger.Flapping
+	if params == nil {
+		currentState.Flapping = false
+		currentState.StateChanges = nil
+		return currentState
+	}
+
+	changes := make([]int64, 0, len(lastState.StateChanges)+1)
+	for _, timestamp := range lastState.StateChanges {
+		if timestamp > currentState.Timestamp-params.Window {
+			changes = append(changes, timestamp)
+		}
+	}
+	if currentState.State != lastState.State {
+		changes = append(changes, currentState.Timestamp)
+	}
+	if len(changes) == 0 {
+		changes = nil
+	}
+	currentState.StateChanges = changes
+
+	if lastState.Flapping {
+		currentState.Flapping = len(changes) > params.StopChanges
+	} else {
+		currentState.Flapping = len(changes) >= params.Changes
+	}
+	return currentState
+}
+
+// compareFlappingMetricStates handles metric which is flapping or has just changed flapping status.
+// Single event is sent when metric starts and stops flapping, other events are muted while metric is flapping
+func (triggerChecker *TriggerChecker) compareFlappingMetricStates(metric string, currentState moira.MetricState, lastState moira.MetricState, maintenanceTimestamp int64) (moira.MetricState, error) {
+	if currentState.State != lastState.State {
+		currentState.EventTimestamp = currentState.Timestamp
+	}
+
+	if currentState.Flapping && lastState.Flapping {
+		// Metric keeps flapping, stay quiet
+		currentState.Suppressed = true
+		currentState.SuppressedReason = flappingSuppressReason
+		if !lastState.Suppressed {
+			currentState.SuppressedState = lastState.State
+		}
+		return currentState, nil
+	}
+
+	if suppressed, reason := triggerChecker.getSuppression(currentState.Timestamp, maintenanceTimestamp); suppressed {
+		currentState.Suppressed = true
+		currentState.SuppressedReason = reason
+		if !lastState.Suppressed {
+			currentState.SuppressedState = lastState.State
+		}
+		return currentState, nil
+	}
+
+	flappingInfo := &moira.FlappingInfo{Stopped: true}
+	if currentState.Flapping {
+		flappingInfo = &moira.FlappingInfo{Changes: len(currentState.StateChanges), Window: triggerChecker.trigger.Flapping.Window}
+	}
+	currentState.EventTimestamp = currentState.Timestamp
+	err := triggerChecker.database.PushNotificationEvent(&moira.NotificationEvent{
+		TriggerID:        triggerChecker.triggerID,
+		State:            currentState.State,
+		OldState:         getEventOldState(lastState.State, lastState.SuppressedState, lastState.Suppressed),
+		Timestamp:        currentState.Timestamp,
+		Metric:           metric,
+		MessageEventInfo: &moira.EventInfo{Flapping: flappingInfo},
+		Values:           currentState.Values,
+	}, true)
+
+	if currentState.Flapping {
+		// Mute following events until metric stops flapping, users are notified about current state
+		currentState.Suppressed = true
+		currentState.SuppressedState = currentState.State
+		currentState.SuppressedReason = flappingSuppressReason
+	} else {
+		currentState.Suppressed = false
+		currentState.SuppressedState = ""
+		currentState.SuppressedReason = ""
+	}
+	return currentState, err
+}
@@ -0,0 +1,130 @@
+package checker
+
+import (
+	"testing"
+
+	"github.com/moira-alert/moira"
+	logging "github.com/moira-alert/moira/logging/zerolog_adapter"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestUpdateFlapping(t *testing.T) {
+	Convey("Test update flapping", t, func() {
+		triggerChecker := TriggerChecker{
+			trigger: &moira.Trigger{Flapping: &moira.FlappingParams{Window: 300, Changes: 3, StopChanges: 1}},
+		}
+
+		Convey("Trigger without flapping params clears flapping status", func() {
+			triggerChecker.trigger.Flapping = nil
+			actual := triggerChecker.updateFlapping(
+				moira.MetricState{State: moira.StateOK, Timestamp: 1000},
+				moira.MetricState{State: moira.StateWARN, Flapping: true, StateChanges: []int64{900, 960}})
+			So(actual, ShouldResemble, moira.MetricState{State: moira.StateOK, Timestamp: 1000})
+		})
+
+		Convey("State changes outside of window are forgotten", func() {
+			actual := triggerChecker.updateFlapping(
+				moira.MetricState{State: moira.StateOK, Timestamp: 1000},
+				moira.MetricState{State: moira.StateWARN, StateChanges: []int64{600, 700, 960}})
+			So(actual.StateChanges, ShouldResemble, []int64{960, 1000})
+			So(actual.Flapping, ShouldBeFalse)
+		})
+
+		Convey("Metric starts flapping after enough changes", func() {
+			actual := triggerChecker.updateFlapping(
+				moira.MetricState{State: moira.StateOK, Timestamp: 1000},
+				moira.MetricState{State: moira.StateWARN, StateChanges: []int64{900, 960}})
+			So(actual.StateChanges, ShouldResemble, []int64{900, 960, 1000})
+			So(actual.Flapping, ShouldBeTrue)
+		})
+
+		Convey("Flapping metric stops flapping after changes go down to stop changes", func() {
+			lastState := moira.MetricState{State: moira.StateOK, Flapping: true, StateChanges: []int64{900, 960, 1000}}
+			actual := triggerChecker.updateFlapping(moira.MetricState{State: moira.StateOK, Timestamp: 1230}, lastState)
+			So(actual.StateChanges, ShouldResemble, []int64{960, 1000})
+			So(actual.Flapping, ShouldBeTrue)
+
+			actual = triggerChecker.updateFlapping(moira.MetricState{State: moira.StateOK, Timestamp: 1290}, actual)
+			So(actual.StateChanges, ShouldResemble, []int64{1000})
+			So(actual.Flapping, ShouldBeFalse)
+		})
+	})
+}
+
+func TestCompareMetricStatesWithFlapping(t *testing.T) {
+	dataBase, mockCtrl := newMocks(t)
+	defer mockCtrl.Finish()
+	logger, _ := logging.GetLogger("Test")
+
+	Convey("Single event is sent when metric starts and stops flapping", t, func() {
+		triggerChecker := TriggerChecker{
+			triggerID: "SuperId",
+			database:  dataBase,
+			logger:    logger,
+			trigger:   &moira.Trigger{Flapping: &moira.FlappingParams{Window: 300, Changes: 3}},
+			lastCheck: &moira.CheckData{},
+		}
+		lastState := moira.MetricState{State: moira.StateOK, Timestamp: 1000, EventTimestamp: 1000}
+
+		dataBase.EXPECT().PushNotificationEvent(&moira.NotificationEvent{
+			TriggerID: "SuperId",
+			State:     moira.StateWARN,
+			OldState:  moira.StateOK,
+			Timestamp: 1060,
+			Metric:    "m1",
+		}, true).Return(nil)
+		actual, err := triggerChecker.compareMetricStates("m1", moira.MetricState{State: moira.StateWARN, Timestamp: 1060}, lastState)
+		So(err, ShouldBeNil)
+		So(actual.Flapping, ShouldBeFalse)
+
+		dataBase.EXPECT().PushNotificationEvent(&moira.NotificationEvent{
+			TriggerID: "SuperId",
+			State:     moira.StateOK,
+			OldState:  moira.StateWARN,
+			Timestamp: 1120,
+			Metric:    "m1",
+		}, true).Return(nil)
+		actual, err = triggerChecker.compareMetricStates("m1", moira.MetricState{State: moira.StateOK, Timestamp: 1120}, actual)
+		So(err, ShouldBeNil)
+		So(actual.Flapping, ShouldBeFalse)
+
+		dataBase.EXPECT().PushNotificationEvent(&moira.NotificationEvent{
+			TriggerID:        "SuperId",
+			State:            moira.StateWARN,
+			OldState:         moira.StateOK,
+			Timestamp:        1180,
+			Metric:           "m1",
+			MessageEventInfo: &moira.EventInfo{Flapping: &moira.FlappingInfo{Changes: 3, Window: 300}},
+		}, true).Return(nil)
+		actual, err = triggerChecker.compareMetricStates("m1", moira.MetricState{State: moira.StateWARN, Timestamp: 1180}, actual)
+		So(err, ShouldBeNil)
+		So(actual.Flapping, ShouldBeTrue)
+		So(actual.Suppressed, ShouldBeTrue)
+		So(actual.SuppressedState, ShouldEqual, moira.StateWARN)
+		So(actual.SuppressedReason, ShouldEqual, flappingSuppressReason)
+
+		actual, err = triggerChecker.compareMetricStates("m1", moira.MetricState{State: moira.StateOK, Timestamp: 1240}, actual)
+		So(err, ShouldBeNil)
+		actual, err = triggerChecker.compareMetricStates("m1", moira.MetricState{State: moira.StateERROR, Timestamp: 1300}, actual)
+		So(err, ShouldBeNil)
+		So(actual.Flapping, ShouldBeTrue)
+		So(actual.Suppressed, ShouldBeTrue)
+		So(actual.SuppressedState, ShouldEqual, moira.StateWARN)
+
+		dataBase.EXPECT().PushNotificationEvent(&moira.NotificationEvent{
+			TriggerID:        "SuperId",
+			State:            moira.StateERROR,
+			OldState:         moira.StateWARN,
+			Timestamp:        1700,
+			Metric:           "m1",
+			MessageEventInfo: &moira.EventInfo{Flapping: &moira.FlappingInfo{Stopped: true}},
+		}, true).Return(nil)
+		actual, err = triggerChecker.compareMetricStates("m1", moira.MetricState{State: moira.StateERROR, Timestamp: 1700}, actual)
+		So(err, ShouldBeNil)
+		So(actual.Flapping, ShouldBeFalse)
+		So(actual.Suppressed, ShouldBeFalse)
+		So(actual.SuppressedState, ShouldBeEmpty)
+		So(actual.SuppressedReason, ShouldBeEmpty)
+		So(actual.StateChanges, ShouldBeNil)
+	})
+}
@@ -0,0 +1,25 @@
+package checker
+
+import (
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/expression"
+)
+
+// getForecastTimestamp returns timestamp when trend of metric values within lookback period before valueTimestamp reaches limit
+func (triggerChecker *TriggerChecker) getForecastTimestamp(metricName string, valueTimestamp int64) (int64, bool) {
+	params := triggerChecker.trigger.Forecast
+	history, ok := triggerChecker.history[metricName]
+	if params == nil || !ok || history.StepTime <= 0 {
+		return 0, false
+	}
+
+	timestamps := make([]int64, 0)
+	values := make([]float64, 0)
+	for timestamp := valueTimestamp - params.Lookback; timestamp <= valueTimestamp; timestamp += history.StepTime {
+		if value := history.GetTimestampValue(timestamp); moira.IsValidFloat64(value) {
+			timestamps = append(timestamps, timestamp)
+			values = append(values, value)
+		}
+	}
+	return expression.GetForecastTimestamp(timestamps, values, valueTimestamp, params)
+}
@@ -0,0 +1,59 @@
+package checker
+
+import (
+	"testing"
+
+	"github.com/moira-alert/moira"
+	logging "github.com/moira-alert/moira/logging/zerolog_adapter"
+	metricSource "github.com/moira-alert/moira/metric_source"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestGetMetricDataStateForecast(t *testing.T) {
+	logger, _ := logging.GetLogger("Test")
+	metric := "disk.used"
+	var warnHorizon int64 = 7200
+	var errorHorizon int64 = 600
+	triggerChecker := &TriggerChecker{
+		logger: logger,
+		trigger: &moira.Trigger{
+			TriggerType: moira.ForecastTrigger,
+			Forecast:    &moira.ForecastParams{Lookback: 300, Limit: 100, WarnHorizon: &warnHorizon, ErrorHorizon: &errorHorizon},
+		},
+		history: map[string]metricSource.MetricData{
+			// grows by 1 per minute
+			metric: *metricSource.MakeMetricData(metric, []float64{10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20}, 60, 0),
+		},
+	}
+	lastState := moira.MetricState{State: moira.StateOK}
+	metrics := map[string]metricSource.MetricData{"t1": *metricSource.MakeMetricData(metric, []float64{20}, 60, 600)}
+
+	Convey("Limit is reached within warn horizon", t, func() {
+		var valueTimestamp, checkPoint int64 = 600, 0
+		metricState, err := triggerChecker.getMetricDataState(&metrics, &lastState, &valueTimestamp, &checkPoint, logger)
+		So(err, ShouldBeNil)
+		So(metricState.State, ShouldEqual, moira.StateWARN)
+		So(metricState.Values, ShouldResemble, map[string]float64{"t1": 20, moira.ForecastValueName: 80 * 60})
+	})
+
+	Convey("Limit is reached within error horizon", t, func() {
+		triggerChecker.trigger.Forecast.Limit = 25
+		defer func() { triggerChecker.trigger.Forecast.Limit = 100 }()
+		var valueTimestamp, checkPoint int64 = 600, 0
+		metricState, err := triggerChecker.getMetricDataState(&metrics, &lastState, &valueTimestamp, &checkPoint, logger)
+		So(err, ShouldBeNil)
+		So(metricState.State, ShouldEqual, moira.StateERROR)
+		So(metricState.Values[moira.ForecastValueName], ShouldEqual, 5*60)
+	})
+
+	Convey("Limit is never reached", t, func() {
+		triggerChecker.trigger.Forecast.Direction = moira.FallingTrigger
+		triggerChecker.trigger.Forecast.Limit = 0
+		defer func() { triggerChecker.trigger.Forecast.Direction = "" }()
+		var valueTimestamp, checkPoint int64 = 600, 0
+		metricState, err := triggerChecker.getMetricDataState(&metrics, &lastState, &valueTimestamp, &checkPoint, logger)
+		So(err, ShouldBeNil)
+		So(metricState.State, ShouldEqual, moira.StateOK)
+		So(metricState.Values, ShouldResemble, map[string]float64{"t1": 20})
+	})
+}
@@ -0,0 +1,36 @@
+package checker
+
+import (
+	"github.com/moira-alert/moira"
+	patternindex "github.com/moira-alert/moira/pattern_index"
+)
+
+// getThresholdOverride returns first trigger threshold override which pattern matches metric name
+func (triggerChecker *TriggerChecker) getThresholdOverride(metricName string) *moira.ThresholdOverride {
+	overrides := triggerChecker.trigger.Overrides
+	if len(overrides) == 0 {
+		return nil
+	}
+	if triggerChecker.overridesIndex == nil {
+		patterns := make([]string, 0, len(overrides))
+		for _, override := range overrides {
+			patterns = append(patterns, override.Pattern)
+		}
+		triggerChecker.overridesIndex = patternindex.NewPatternIndex(triggerChecker.logger, patterns)
+	}
+
+	matchedPatterns := triggerChecker.overridesIndex.MatchPatterns(metricName)
+	if len(matchedPatterns) == 0 {
+		return nil
+	}
+	matched := make(map[string]bool, len(matchedPatterns))
+	for _, pattern := range matchedPatterns {
+		matched[pattern] = true
+	}
+	for i := range overrides {
+		if matched[overrides[i].Pattern] {
+			return &overrides[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package checker
+
+import (
+	"testing"
+
+	"github.com/moira-alert/moira"
+	logging "github.com/moira-alert/moira/logging/zerolog_adapter"
+	metricSource "github.com/moira-alert/moira/metric_source"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestGetThresholdOverride(t *testing.T) {
+	logger, _ := logging.GetLogger("Test")
+	bigWarn, bigError := float64(100), float64(200)
+	hugeWarn, hugeError := float64(1000), float64(2000)
+
+	Convey("Test get threshold override", t, func() {
+		triggerChecker := TriggerChecker{
+			logger: logger,
+			trigger: &moira.Trigger{Overrides: []moira.ThresholdOverride{
+				{Pattern: "Servers.big-{01,02}.cpu", WarnValue: &bigWarn, ErrorValue: &bigError},
+				{Pattern: "Servers.big-*.cpu", WarnValue: &hugeWarn, ErrorValue: &hugeError},
+			}},
+		}
+
+		Convey("Trigger without overrides", func() {
+			triggerChecker.trigger.Overrides = nil
+			So(triggerChecker.getThresholdOverride("Servers.big-01.cpu"), ShouldBeNil)
+		})
+
+		Convey("First matching override is used", func() {
+			So(triggerChecker.getThresholdOverride("Servers.big-01.cpu"), ShouldResemble, &triggerChecker.trigger.Overrides[0])
+			So(triggerChecker.getThresholdOverride("Servers.big-03.cpu"), ShouldResemble, &triggerChecker.trigger.Overrides[1])
+		})
+
+		Convey("Metric without matching override", func() {
+			So(triggerChecker.getThresholdOverride("Servers.small-01.cpu"), ShouldBeNil)
+			So(triggerChecker.getThresholdOverride("Servers.big-01.cpu.user"), ShouldBeNil)
+		})
+	})
+}
+
+func TestGetMetricDataStateWithOverride(t *testing.T) {
+	logger, _ := logging.GetLogger("Test")
+	warnValue, errorValue := float64(10), float64(20)
+	overrideWarn, overrideError := float64(50), float64(100)
+	expression := "t1 > 30 ? ERROR : OK"
+
+	triggerChecker := TriggerChecker{
+		logger: logger,
+		trigger: &moira.Trigger{
+			WarnValue:   &warnValue,
+			ErrorValue:  &errorValue,
+			TriggerType: moira.RisingTrigger,
+			Overrides:   []moira.ThresholdOverride{{Pattern: "Servers.big-*.cpu", WarnValue: &overrideWarn, ErrorValue: &overrideError}},
+		},
+	}
+	var valueTimestamp, checkPoint int64 = 10, 0
+
+	Convey("Override thresholds are used for matching metric", t, func() {
+		metrics := map[string]metricSource.MetricData{
+			"t1": *metricSource.MakeMetricData("Servers.big-01.cpu", []float64{60}, 10, 10),
+		}
+		metricState, err := triggerChecker.getMetricDataState(&metrics, &moira.MetricState{}, &valueTimestamp, &checkPoint, logger)
+		So(err, ShouldBeNil)
+		So(metricState.State, ShouldEqual, moira.StateWARN)
+	})
+
+	Convey("Trigger thresholds are used for other metrics", t, func() {
+		metrics := map[string]metricSource.MetricData{
+			"t1": *metricSource.MakeMetricData("Servers.small-01.cpu", []float64{60}, 10, 10),
+		}
+		metricState, err := triggerChecker.getMetricDataState(&metrics, &moira.MetricState{}, &valueTimestamp, &checkPoint, logger)
+		So(err, ShouldBeNil)
+		So(metricState.State, ShouldEqual, moira.StateERROR)
+	})
+
+	Convey("Override expression is used for expression trigger", t, func() {
+		triggerChecker.trigger = &moira.Trigger{
+			TriggerType: moira.ExpressionTrigger,
+			Expression:  &expression,
+			Overrides:   []moira.ThresholdOverride{{Pattern: "Servers.big-*.cpu", Expression: "t1 > 80 ? ERROR : OK"}},
+		}
+		triggerChecker.overridesIndex = nil
+		metrics := map[string]metricSource.MetricData{
+			"t1": *metricSource.MakeMetricData("Servers.big-01.cpu", []float64{60}, 10, 10),
+		}
+		metricState, err := triggerChecker.getMetricDataState(&metrics, &moira.MetricState{}, &valueTimestamp, &checkPoint, logger)
+		So(err, ShouldBeNil)
+		So(metricState.State, ShouldEqual, moira.StateOK)
+	})
+}
@@ -0,0 +1,43 @@
+package checker
+
+import (
+	"github.com/moira-alert/moira"
+)
+
+// holdPendingState keeps last metric state while new state has not lasted for trigger pending condition.
+// Metric state changes and fires an event only when new state is kept long enough.
+// Checks are counted by trigger check timestamps, so several points of metric received between checks are one check
+func (triggerChecker *TriggerChecker) holdPendingState(currentState moira.MetricState, lastState moira.MetricState) moira.MetricState {
+	newState := currentState.State
+	currentState.PendingState = ""
+	currentState.PendingSince = 0
+	currentState.PendingChecks = 0
+	currentState.PendingLastCheck = 0
+
+	if newState == lastState.State {
+		return currentState
+	}
+	condition := triggerChecker.trigger.Pending.GetCondition(newState)
+	if condition.IsEmpty() {
+		return currentState
+	}
+
+	pendingSince, pendingChecks := currentState.Timestamp, 1
+	if lastState.PendingState == newState {
+		pendingSince = lastState.PendingSince
+		pendingChecks = lastState.PendingChecks
+		if lastState.PendingLastCheck != triggerChecker.until {
+			pendingChecks++
+		}
+	}
+	if condition.IsSatisfied(currentState.Timestamp-pendingSince, pendingChecks) {
+		return currentState
+	}
+
+	currentState.State = lastState.State
+	currentState.PendingState = newState
+	currentState.PendingSince = pendingSince
+	currentState.PendingChecks = pendingChecks
+	currentState.PendingLastCheck = triggerChecker.until
+	return currentState
+}
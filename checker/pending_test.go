@@ -0,0 +1,115 @@
+package checker
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/moira-alert/moira"
+	logging "github.com/moira-alert/moira/logging/zerolog_adapter"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestHoldPendingState(t *testing.T) {
+	Convey("Test hold pending state", t, func() {
+		triggerChecker := TriggerChecker{
+			trigger: &moira.Trigger{
+				Pending: &moira.PendingParams{
+					Period: 120,
+					States: map[moira.State]moira.PendingCondition{moira.StateOK: {Checks: 2}},
+				},
+			},
+		}
+		lastState := moira.MetricState{State: moira.StateOK, Timestamp: 1000}
+
+		Convey("Trigger without pending params does not hold state", func() {
+			triggerChecker.trigger.Pending = nil
+			actual := triggerChecker.holdPendingState(moira.MetricState{State: moira.StateERROR, Timestamp: 1060}, lastState)
+			So(actual, ShouldResemble, moira.MetricState{State: moira.StateERROR, Timestamp: 1060})
+		})
+
+		Convey("Same state clears pending fields", func() {
+			actual := triggerChecker.holdPendingState(
+				moira.MetricState{State: moira.StateOK, Timestamp: 1060, PendingState: moira.StateERROR, PendingSince: 1000, PendingChecks: 1},
+				lastState)
+			So(actual, ShouldResemble, moira.MetricState{State: moira.StateOK, Timestamp: 1060})
+		})
+
+		Convey("New state is pending until period passes", func() {
+			triggerChecker.until = 1070
+			actual := triggerChecker.holdPendingState(moira.MetricState{State: moira.StateERROR, Timestamp: 1060}, lastState)
+			So(actual, ShouldResemble, moira.MetricState{State: moira.StateOK, Timestamp: 1060, PendingState: moira.StateERROR, PendingSince: 1060, PendingChecks: 1, PendingLastCheck: 1070})
+
+			triggerChecker.until = 1130
+			actual = triggerChecker.holdPendingState(moira.MetricState{State: moira.StateERROR, Timestamp: 1120}, actual)
+			So(actual, ShouldResemble, moira.MetricState{State: moira.StateOK, Timestamp: 1120, PendingState: moira.StateERROR, PendingSince: 1060, PendingChecks: 2, PendingLastCheck: 1130})
+
+			triggerChecker.until = 1190
+			actual = triggerChecker.holdPendingState(moira.MetricState{State: moira.StateERROR, Timestamp: 1180}, actual)
+			So(actual, ShouldResemble, moira.MetricState{State: moira.StateERROR, Timestamp: 1180})
+		})
+
+		Convey("Points of one check are counted as one check", func() {
+			triggerChecker.trigger.Pending = &moira.PendingParams{Checks: 2}
+			triggerChecker.until = 1130
+			actual := triggerChecker.holdPendingState(moira.MetricState{State: moira.StateERROR, Timestamp: 1060}, lastState)
+			actual = triggerChecker.holdPendingState(moira.MetricState{State: moira.StateERROR, Timestamp: 1120}, actual)
+			So(actual, ShouldResemble, moira.MetricState{State: moira.StateOK, Timestamp: 1120, PendingState: moira.StateERROR, PendingSince: 1060, PendingChecks: 1, PendingLastCheck: 1130})
+
+			triggerChecker.until = 1190
+			actual = triggerChecker.holdPendingState(moira.MetricState{State: moira.StateERROR, Timestamp: 1180}, actual)
+			So(actual, ShouldResemble, moira.MetricState{State: moira.StateERROR, Timestamp: 1180})
+		})
+
+		Convey("Other pending state restarts pending", func() {
+			triggerChecker.until = 1070
+			actual := triggerChecker.holdPendingState(moira.MetricState{State: moira.StateERROR, Timestamp: 1060}, lastState)
+			triggerChecker.until = 1130
+			actual = triggerChecker.holdPendingState(moira.MetricState{State: moira.StateWARN, Timestamp: 1120}, actual)
+			So(actual, ShouldResemble, moira.MetricState{State: moira.StateOK, Timestamp: 1120, PendingState: moira.StateWARN, PendingSince: 1120, PendingChecks: 1, PendingLastCheck: 1130})
+		})
+
+		Convey("State condition overrides default one", func() {
+			lastState.State = moira.StateERROR
+			triggerChecker.until = 1070
+			actual := triggerChecker.holdPendingState(moira.MetricState{State: moira.StateOK, Timestamp: 1060}, lastState)
+			So(actual.State, ShouldEqual, moira.StateERROR)
+			So(actual.PendingState, ShouldEqual, moira.StateOK)
+
+			triggerChecker.until = 1130
+			actual = triggerChecker.holdPendingState(moira.MetricState{State: moira.StateOK, Timestamp: 1120}, actual)
+			So(actual, ShouldResemble, moira.MetricState{State: moira.StateOK, Timestamp: 1120})
+		})
+	})
+}
+
+func TestCompareMetricStatesWithPending(t *testing.T) {
+	dataBase, mockCtrl := newMocks(t)
+	defer mockCtrl.Finish()
+	logger, _ := logging.GetLogger("Test")
+
+	Convey("Event is pushed only after metric stays in new state for pending checks", t, func() {
+		triggerChecker := TriggerChecker{
+			triggerID: "SuperId",
+			database:  dataBase,
+			logger:    logger,
+			trigger:   &moira.Trigger{Pending: &moira.PendingParams{Checks: 2}},
+			lastCheck: &moira.CheckData{},
+		}
+		lastState := moira.MetricState{State: moira.StateOK, Timestamp: 1000, EventTimestamp: 1000}
+
+		triggerChecker.until = 1070
+		actual, err := triggerChecker.compareMetricStates("m1", moira.MetricState{State: moira.StateERROR, Timestamp: 1060}, lastState)
+		So(err, ShouldBeNil)
+		So(actual.State, ShouldEqual, moira.StateOK)
+		So(actual.PendingState, ShouldEqual, moira.StateERROR)
+		So(actual.EventTimestamp, ShouldEqual, 1000)
+
+		dataBase.EXPECT().PushNotificationEvent(gomock.Any(), true).Return(nil)
+		triggerChecker.until = 1130
+		actual, err = triggerChecker.compareMetricStates("m1", moira.MetricState{State: moira.StateERROR, Timestamp: 1120}, actual)
+		So(err, ShouldBeNil)
+		So(actual.State, ShouldEqual, moira.StateERROR)
+		So(actual.PendingState, ShouldBeEmpty)
+		So(actual.EventTimestamp, ShouldEqual, 1120)
+	})
+}
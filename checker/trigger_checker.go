@@ -5,8 +5,10 @@ import (
 
 	"github.com/moira-alert/moira"
 	"github.com/moira-alert/moira/database"
+	"github.com/moira-alert/moira/expression"
 	metricSource "github.com/moira-alert/moira/metric_source"
 	"github.com/moira-alert/moira/metrics"
+	patternindex "github.com/moira-alert/moira/pattern_index"
 )
 
 // TriggerChecker represents data, used for handling new trigger state
@@ -19,6 +21,8 @@ type TriggerChecker struct {
 
 	from  int64
 	until int64
+	// number of seconds preceding checked period which are fetched only to be read by expression window functions
+	window int64
 
 	triggerID string
 	trigger   *moira.Trigger
@@ -26,6 +30,13 @@ type TriggerChecker struct {
 
 	ttl      int64
 	ttlState moira.TTLState
+
+	// main target values preceding checked period by metric name, used by anomaly and forecast triggers
+	history map[string]metricSource.MetricData
+	// reason to suppress events because of parent triggers state, requested once per check
+	parentsSuppressReason *string
+	// index of trigger threshold overrides patterns, built on first use
+	overridesIndex *patternindex.PatternIndex
 }
 
 // MakeTriggerChecker initialize new triggerChecker data
@@ -51,6 +62,12 @@ func MakeTriggerChecker(triggerID string, dataBase moira.Database, logger moira.
 		return nil, err
 	}
 
+	// Trigger with invalid expression is still checked, so that check reports expression error as trigger exception
+	window, err := expression.GetTriggerWindow(&trigger)
+	if err != nil {
+		window = 0
+	}
+
 	triggerLogger := logger.Clone().String(moira.LogFieldNameTriggerID, triggerID)
 	if logLevel, ok := config.LogTriggersToLevel[triggerID]; ok {
 		if _, err := triggerLogger.Level(logLevel); err != nil {
@@ -65,8 +82,9 @@ func MakeTriggerChecker(triggerID string, dataBase moira.Database, logger moira.
 		metrics:  metrics.GetCheckMetrics(&trigger),
 		source:   source,
 
-		from:  calculateFrom(lastCheck.Timestamp, trigger.TTL),
-		until: until,
+		from:   calculateFrom(lastCheck.Timestamp, trigger.TTL),
+		until:  until,
+		window: window,
 
 		triggerID: triggerID,
 		trigger:   &trigger,
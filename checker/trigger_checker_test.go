@@ -11,6 +11,7 @@ import (
 	metricSource "github.com/moira-alert/moira/metric_source"
 	"github.com/moira-alert/moira/metric_source/local"
 	"github.com/moira-alert/moira/metrics"
+	mock_metric_source "github.com/moira-alert/moira/mock/metric_source"
 	mock_moira_alert "github.com/moira-alert/moira/mock/moira-alert"
 	. "github.com/smartystreets/goconvey/convey"
 )
@@ -28,14 +29,14 @@ func TestInitTriggerChecker(t *testing.T) {
 		Convey("Get trigger error", func() {
 			getTriggerError := fmt.Errorf("Oppps! Can't read trigger")
 			dataBase.EXPECT().GetTrigger(triggerID).Return(moira.Trigger{}, getTriggerError)
-			_, err := MakeTriggerChecker(triggerID, dataBase, logger, config, metricSource.CreateMetricSourceProvider(localSource, nil), &metrics.CheckerMetrics{})
+			_, err := MakeTriggerChecker(triggerID, dataBase, logger, config, metricSource.CreateMetricSourceProvider(localSource, nil, nil), &metrics.CheckerMetrics{})
 			So(err, ShouldBeError)
 			So(err, ShouldResemble, getTriggerError)
 		})
 
 		Convey("No trigger error", func() {
 			dataBase.EXPECT().GetTrigger(triggerID).Return(moira.Trigger{}, database.ErrNil)
-			_, err := MakeTriggerChecker(triggerID, dataBase, logger, config, metricSource.CreateMetricSourceProvider(localSource, nil), &metrics.CheckerMetrics{})
+			_, err := MakeTriggerChecker(triggerID, dataBase, logger, config, metricSource.CreateMetricSourceProvider(localSource, nil, nil), &metrics.CheckerMetrics{})
 			So(err, ShouldBeError)
 			So(err, ShouldResemble, ErrTriggerNotExists)
 		})
@@ -44,7 +45,7 @@ func TestInitTriggerChecker(t *testing.T) {
 			readLastCheckError := fmt.Errorf("Oppps! Can't read last check")
 			dataBase.EXPECT().GetTrigger(triggerID).Return(moira.Trigger{TriggerType: moira.RisingTrigger}, nil)
 			dataBase.EXPECT().GetTriggerLastCheck(triggerID).Return(moira.CheckData{}, readLastCheckError)
-			_, err := MakeTriggerChecker(triggerID, dataBase, logger, config, metricSource.CreateMetricSourceProvider(localSource, nil), &metrics.CheckerMetrics{})
+			_, err := MakeTriggerChecker(triggerID, dataBase, logger, config, metricSource.CreateMetricSourceProvider(localSource, nil, nil), &metrics.CheckerMetrics{})
 			So(err, ShouldBeError)
 			So(err, ShouldResemble, readLastCheckError)
 		})
@@ -100,7 +101,7 @@ func TestInitTriggerChecker(t *testing.T) {
 	Convey("Test trigger checker with lastCheck", t, func() {
 		dataBase.EXPECT().GetTrigger(triggerID).Return(trigger, nil)
 		dataBase.EXPECT().GetTriggerLastCheck(triggerID).Return(lastCheck, nil)
-		actual, err := MakeTriggerChecker(triggerID, dataBase, logger, config, metricSource.CreateMetricSourceProvider(localSource, nil), &metrics.CheckerMetrics{})
+		actual, err := MakeTriggerChecker(triggerID, dataBase, logger, config, metricSource.CreateMetricSourceProvider(localSource, nil, nil), &metrics.CheckerMetrics{})
 		So(err, ShouldBeNil)
 
 		expected := TriggerChecker{
@@ -122,7 +123,7 @@ func TestInitTriggerChecker(t *testing.T) {
 	Convey("Test trigger checker without lastCheck", t, func() {
 		dataBase.EXPECT().GetTrigger(triggerID).Return(trigger, nil)
 		dataBase.EXPECT().GetTriggerLastCheck(triggerID).Return(moira.CheckData{}, database.ErrNil)
-		actual, err := MakeTriggerChecker(triggerID, dataBase, logger, config, metricSource.CreateMetricSourceProvider(localSource, nil), &metrics.CheckerMetrics{})
+		actual, err := MakeTriggerChecker(triggerID, dataBase, logger, config, metricSource.CreateMetricSourceProvider(localSource, nil, nil), &metrics.CheckerMetrics{})
 		So(err, ShouldBeNil)
 
 		expected := TriggerChecker{
@@ -151,7 +152,7 @@ func TestInitTriggerChecker(t *testing.T) {
 	Convey("Test trigger checker without lastCheck and ttl", t, func() {
 		dataBase.EXPECT().GetTrigger(triggerID).Return(trigger, nil)
 		dataBase.EXPECT().GetTriggerLastCheck(triggerID).Return(moira.CheckData{}, database.ErrNil)
-		actual, err := MakeTriggerChecker(triggerID, dataBase, logger, config, metricSource.CreateMetricSourceProvider(localSource, nil), &metrics.CheckerMetrics{})
+		actual, err := MakeTriggerChecker(triggerID, dataBase, logger, config, metricSource.CreateMetricSourceProvider(localSource, nil, nil), &metrics.CheckerMetrics{})
 		So(err, ShouldBeNil)
 
 		expected := TriggerChecker{
@@ -177,7 +178,7 @@ func TestInitTriggerChecker(t *testing.T) {
 	Convey("Test trigger checker with lastCheck and without ttl", t, func() {
 		dataBase.EXPECT().GetTrigger(triggerID).Return(trigger, nil)
 		dataBase.EXPECT().GetTriggerLastCheck(triggerID).Return(lastCheck, nil)
-		actual, err := MakeTriggerChecker(triggerID, dataBase, logger, config, metricSource.CreateMetricSourceProvider(localSource, nil), &metrics.CheckerMetrics{})
+		actual, err := MakeTriggerChecker(triggerID, dataBase, logger, config, metricSource.CreateMetricSourceProvider(localSource, nil, nil), &metrics.CheckerMetrics{})
 		So(err, ShouldBeNil)
 
 		expected := TriggerChecker{
@@ -195,4 +196,40 @@ func TestInitTriggerChecker(t *testing.T) {
 		}
 		So(*actual, ShouldResemble, expected)
 	})
+
+	Convey("Trigger with invalid expression is checked with exception", t, func() {
+		source := mock_metric_source.NewMockMetricSource(mockCtrl)
+		fetchResult := mock_metric_source.NewMockFetchResult(mockCtrl)
+		invalidExpression := "unknown_function(t1, 600) > 1 ? ERROR : OK"
+		invalidTrigger := moira.Trigger{
+			ID:          triggerID,
+			Targets:     []string{"metric"},
+			TriggerType: moira.ExpressionTrigger,
+			Expression:  &invalidExpression,
+		}
+		exceptionLastCheck := moira.CheckData{
+			Metrics:   make(map[string]moira.MetricState),
+			State:     moira.StateEXCEPTION,
+			Timestamp: lastCheck.Timestamp,
+		}
+		dataBase.EXPECT().GetTrigger(triggerID).Return(invalidTrigger, nil)
+		dataBase.EXPECT().GetTriggerLastCheck(triggerID).Return(exceptionLastCheck, nil)
+		source.EXPECT().IsConfigured().Return(true, nil)
+		actual, err := MakeTriggerChecker(triggerID, dataBase, logger, config, metricSource.CreateMetricSourceProvider(source, nil, nil),
+			metrics.ConfigureCheckerMetrics(metrics.NewDummyRegistry(), false))
+		So(err, ShouldBeNil)
+		So(actual.window, ShouldEqual, 0)
+
+		source.EXPECT().Fetch("metric", actual.from, actual.until, true).Return(fetchResult, nil)
+		fetchResult.EXPECT().GetMetricsData().Return([]metricSource.MetricData{*metricSource.MakeMetricData("metric", []float64{1, 2}, 60, actual.from)})
+		fetchResult.EXPECT().GetPatternMetrics().Return([]string{"metric"}, nil)
+		dataBase.EXPECT().GetMetricsTTLSeconds().Return(int64(3600))
+		dataBase.EXPECT().RemoveMetricsValues([]string{"metric"}, gomock.Any()).Return(nil)
+		dataBase.EXPECT().SetTriggerLastCheck(triggerID, gomock.Any(), false).DoAndReturn(func(triggerID string, checkData *moira.CheckData, isRemote bool) error {
+			So(checkData.State, ShouldEqual, moira.StateEXCEPTION)
+			So(checkData.Message, ShouldContainSubstring, "unknown_function")
+			return nil
+		})
+		So(actual.Check(), ShouldBeNil)
+	})
 }
@@ -21,14 +21,18 @@ func (worker *Checker) startTriggerHandler(triggerIDsToCheck <-chan string, metr
 		}
 		err := worker.handleTrigger(triggerID, metrics)
 		if err != nil {
-			metrics.HandleError.Mark(1)
-			worker.Logger.Clone().String(moira.LogFieldNameTriggerID, triggerID).
-				Errorf("Failed to handle trigger %s: %s", triggerID, err.Error())
-			<-time.After(sleepAfterCheckingError)
+			worker.handleTriggerError(triggerID, err, metrics)
 		}
 	}
 }
 
+func (worker *Checker) handleTriggerError(triggerID string, err error, metrics *metrics.CheckMetrics) {
+	metrics.HandleError.Mark(1)
+	worker.Logger.Clone().String(moira.LogFieldNameTriggerID, triggerID).
+		Errorf("Failed to handle trigger %s: %s", triggerID, err.Error())
+	<-time.After(sleepAfterCheckingError)
+}
+
 func (worker *Checker) handleTrigger(triggerID string, metrics *metrics.CheckMetrics) error {
 	var err error
 	defer func() {
@@ -41,11 +45,18 @@ func (worker *Checker) handleTrigger(triggerID string, metrics *metrics.CheckMet
 }
 
 func (worker *Checker) handleTriggerInLock(triggerID string, metrics *metrics.CheckMetrics) error {
+	// Triggers of shard are locked only while their previous owner may still check them after rebalance
+	if !worker.isCheckLockNeeded(triggerID) {
+		startedAt := time.Now()
+		defer metrics.TriggersCheckTime.UpdateSince(startedAt)
+		return worker.checkTrigger(triggerID)
+	}
 	acquired, err := worker.Database.SetTriggerCheckLock(triggerID)
 	if err != nil {
 		return err
 	}
 	if acquired {
+		defer worker.Database.DeleteTriggerCheckLock(triggerID) //nolint
 		startedAt := time.Now()
 		defer metrics.TriggersCheckTime.UpdateSince(startedAt)
 		if err := worker.checkTrigger(triggerID); err != nil {
@@ -56,7 +67,6 @@ func (worker *Checker) handleTriggerInLock(triggerID string, metrics *metrics.Ch
 }
 
 func (worker *Checker) checkTrigger(triggerID string) error {
-	defer worker.Database.DeleteTriggerCheckLock(triggerID) //nolint
 	triggerChecker, err := checker.MakeTriggerChecker(triggerID, worker.Database, worker.Logger, worker.Config, worker.SourceProvider, worker.Metrics)
 	if err != nil {
 		if err == checker.ErrTriggerNotExists {
@@ -64,5 +74,10 @@ func (worker *Checker) checkTrigger(triggerID string) error {
 		}
 		return err
 	}
+	// Queued triggers are not checked while requests to their source are suspended, they will be queued again
+	if triggerChecker.IsSourceSuspended() {
+		worker.Logger.Debugf("Skip checking trigger %s, requests to its metric source are suspended", triggerID)
+		return nil
+	}
 	return triggerChecker.Check()
 }
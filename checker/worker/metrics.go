@@ -47,22 +47,35 @@ func (worker *Checker) handleMetricEvent(pattern string) error {
 
 func (worker *Checker) addTriggerIDsIfNeeded(triggerIDs []string) {
 	needToCheckTriggerIDs := worker.getTriggerIDsToCheck(triggerIDs)
-	if len(needToCheckTriggerIDs) > 0 {
-		worker.Database.AddLocalTriggersToCheck(needToCheckTriggerIDs) //nolint
+	if len(needToCheckTriggerIDs) == 0 {
+		return
 	}
+	if worker.Config.Sharding.Enabled {
+		worker.Database.AddLocalShardTriggersToCheck(worker.instanceID, needToCheckTriggerIDs) //nolint
+		return
+	}
+	worker.Database.AddLocalTriggersToCheck(needToCheckTriggerIDs) //nolint
 }
 
 func (worker *Checker) addRemoteTriggerIDsIfNeeded(triggerIDs []string) {
 	needToCheckRemoteTriggerIDs := worker.getTriggerIDsToCheck(triggerIDs)
-	if len(needToCheckRemoteTriggerIDs) > 0 {
-		worker.Database.AddRemoteTriggersToCheck(needToCheckRemoteTriggerIDs) //nolint
+	if len(needToCheckRemoteTriggerIDs) == 0 {
+		return
+	}
+	if worker.Config.Sharding.Enabled {
+		worker.Database.AddRemoteShardTriggersToCheck(worker.instanceID, needToCheckRemoteTriggerIDs) //nolint
+		return
 	}
+	worker.Database.AddRemoteTriggersToCheck(needToCheckRemoteTriggerIDs) //nolint
 }
 
 func (worker *Checker) getTriggerIDsToCheck(triggerIDs []string) []string {
 	lazyTriggerIDs := worker.lazyTriggerIDs.Load().(map[string]bool)
 	triggerIDsToCheck := make([]string, len(triggerIDs))
 	for _, triggerID := range triggerIDs {
+		if !worker.isOwnTrigger(triggerID) {
+			continue
+		}
 		if _, ok := lazyTriggerIDs[triggerID]; ok {
 			randomDuration := worker.getRandomLazyCacheDuration()
 			if err := worker.LazyTriggersCache.Add(triggerID, true, randomDuration); err != nil {
@@ -15,6 +15,10 @@ const (
 // localTriggerGetter starts NODATA checker and manages its subscription in Redis
 // to make sure there is always only one working checker
 func (worker *Checker) localTriggerGetter() error {
+	// Every checker instance looks for NODATA in triggers of its own shard
+	if worker.Config.Sharding.Enabled {
+		return worker.noDataChecker(worker.tomb.Dying())
+	}
 	w.NewWorker(
 		nodataWorkerName,
 		worker.Logger,
@@ -52,6 +56,7 @@ func (worker *Checker) checkNoData() error {
 		if err != nil {
 			return err
 		}
+		worker.updateShardTriggersCount(triggerIds)
 		worker.addTriggerIDsIfNeeded(triggerIds)
 	}
 	return nil
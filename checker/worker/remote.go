@@ -3,6 +3,8 @@ package worker
 import (
 	"time"
 
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/metric_source/prometheus"
 	"github.com/moira-alert/moira/metric_source/remote"
 	w "github.com/moira-alert/moira/worker"
 )
@@ -13,6 +15,10 @@ const (
 )
 
 func (worker *Checker) remoteTriggerGetter() error {
+	// Every checker instance checks remote triggers of its own shard
+	if worker.Config.Sharding.Enabled {
+		return worker.remoteTriggerChecker(worker.tomb.Dying())
+	}
 	w.NewWorker(
 		remoteTriggerName,
 		worker.Logger,
@@ -41,20 +47,76 @@ func (worker *Checker) remoteTriggerChecker(stop <-chan struct{}) error {
 }
 
 func (worker *Checker) checkRemote() error {
-	source, err := worker.SourceProvider.GetRemote()
+	sourcesAvailability := worker.getRemoteSourcesAvailability()
+	if !isAnySourceAvailable(sourcesAvailability) {
+		return nil
+	}
+	worker.Logger.Debug("Checking remote triggers")
+	triggerIds, err := worker.Database.GetRemoteTriggerIDs()
 	if err != nil {
 		return err
 	}
-	remoteAvailable, err := source.(*remote.Remote).IsRemoteAvailable()
-	if !remoteAvailable {
-		worker.Logger.Infof("Remote API is unavailable. Stop checking remote triggers. Error: %s", err.Error())
-	} else {
-		worker.Logger.Debug("Checking remote triggers")
-		triggerIds, err := worker.Database.GetRemoteTriggerIDs()
-		if err != nil {
-			return err
-		}
-		worker.addRemoteTriggerIDsIfNeeded(triggerIds)
+	// Triggers of remote clusters are checked by their own workers
+	triggerIds, err = worker.excludeRemoteClustersTriggerIDs(triggerIds)
+	if err != nil {
+		return err
 	}
+	triggerIds, err = worker.excludeUnavailableSourcesTriggerIDs(triggerIds, sourcesAvailability)
+	if err != nil {
+		return err
+	}
+	worker.addRemoteTriggerIDsIfNeeded(triggerIds)
 	return nil
 }
+
+// getRemoteSourcesAvailability checks every configured remote metric source sharing remote triggers queue
+func (worker *Checker) getRemoteSourcesAvailability() map[moira.TriggerSource]bool {
+	sourcesAvailability := make(map[moira.TriggerSource]bool)
+	if source, err := worker.SourceProvider.GetRemote(); err == nil {
+		remoteAvailable, err := source.(*remote.Remote).IsRemoteAvailable()
+		if !remoteAvailable {
+			worker.Logger.Infof("Remote API is unavailable. Stop checking remote triggers. Error: %s", err.Error())
+		}
+		sourcesAvailability[moira.GraphiteRemote] = remoteAvailable
+	}
+	if source, err := worker.SourceProvider.GetPrometheus(); err == nil {
+		prometheusAvailable, err := source.(*prometheus.Prometheus).IsAvailable()
+		if !prometheusAvailable {
+			worker.Logger.Infof("Prometheus API is unavailable. Stop checking prometheus triggers. Error: %s", err.Error())
+		}
+		sourcesAvailability[moira.PrometheusRemote] = prometheusAvailable
+	}
+	return sourcesAvailability
+}
+
+// excludeUnavailableSourcesTriggerIDs excludes triggers of unavailable metric sources,
+// triggers are loaded to find out their sources only if some of sources are unavailable
+func (worker *Checker) excludeUnavailableSourcesTriggerIDs(triggerIDs []string, sourcesAvailability map[moira.TriggerSource]bool) ([]string, error) {
+	allAvailable := true
+	for _, available := range sourcesAvailability {
+		allAvailable = allAvailable && available
+	}
+	if allAvailable || len(triggerIDs) == 0 {
+		return triggerIDs, nil
+	}
+	triggers, err := worker.Database.GetTriggers(triggerIDs)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]string, 0, len(triggerIDs))
+	for i, trigger := range triggers {
+		if trigger != nil && sourcesAvailability[trigger.GetTriggerSource()] {
+			result = append(result, triggerIDs[i])
+		}
+	}
+	return result, nil
+}
+
+func isAnySourceAvailable(sourcesAvailability map[moira.TriggerSource]bool) bool {
+	for _, available := range sourcesAvailability {
+		if available {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,166 @@
+package worker
+
+import (
+	"sort"
+	"time"
+
+	metricSource "github.com/moira-alert/moira/metric_source"
+	"github.com/moira-alert/moira/metric_source/remote"
+	"github.com/moira-alert/moira/metrics"
+	w "github.com/moira-alert/moira/worker"
+)
+
+const remoteClusterTriggerLockPrefix = "moira-remote-checker:"
+
+// remoteCluster is named remote graphite cluster whose triggers are checked by its own pool of workers
+type remoteCluster struct {
+	name    string
+	config  *remote.Config
+	source  metricSource.MetricSource
+	metrics *metrics.CheckMetrics
+}
+
+// getRemoteClusters returns configured remote clusters, clusters which are not enabled are skipped
+func (worker *Checker) getRemoteClusters() []*remoteCluster {
+	clusters := make([]*remoteCluster, 0, len(worker.RemoteClusters))
+	for _, clusterName := range worker.getRemoteClusterNames() {
+		source, err := worker.SourceProvider.GetRemoteCluster(clusterName)
+		if err != nil {
+			worker.Logger.Infof("Remote cluster %s checker disabled: %s", clusterName, err.Error())
+			continue
+		}
+		clusterMetrics, ok := worker.Metrics.RemoteClustersMetrics[clusterName]
+		if !ok {
+			clusterMetrics = worker.Metrics.RemoteMetrics
+		}
+		clusters = append(clusters, &remoteCluster{
+			name:    clusterName,
+			config:  worker.RemoteClusters[clusterName],
+			source:  source,
+			metrics: clusterMetrics,
+		})
+	}
+	return clusters
+}
+
+// getRemoteClusterNames returns sorted names of all configured remote clusters
+func (worker *Checker) getRemoteClusterNames() []string {
+	clusterNames := make([]string, 0, len(worker.RemoteClusters))
+	for clusterName := range worker.RemoteClusters {
+		clusterNames = append(clusterNames, clusterName)
+	}
+	sort.Strings(clusterNames)
+	return clusterNames
+}
+
+// startRemoteCluster starts getter of cluster triggers and pool of workers checking them
+func (worker *Checker) startRemoteCluster(cluster *remoteCluster) {
+	worker.tomb.Go(func() error { return worker.remoteClusterTriggerGetter(cluster) })
+
+	worker.Logger.Infof("Start %v parallel remote cluster %s checker(s)", worker.Config.MaxParallelRemoteChecks, cluster.name)
+	fetch := func(count int) ([]string, error) {
+		triggerIDs, err := worker.Database.GetRemoteClusterTriggersToCheck(cluster.name, worker.getShardID(), count)
+		return worker.filterOwnTriggers(triggerIDs), err
+	}
+	triggerIDsToCheck := worker.startTriggerToCheckGetter(fetch, worker.Config.MaxParallelRemoteChecks)
+	for i := 0; i < worker.Config.MaxParallelRemoteChecks; i++ {
+		worker.tomb.Go(func() error {
+			return worker.startRemoteClusterTriggerHandler(cluster, triggerIDsToCheck)
+		})
+	}
+}
+
+func (worker *Checker) remoteClusterTriggerGetter(cluster *remoteCluster) error {
+	checkRemoteCluster := func(stop <-chan struct{}) error {
+		return worker.remoteClusterTriggerChecker(cluster, stop)
+	}
+	// Every checker instance checks cluster triggers of its own shard
+	if worker.Config.Sharding.Enabled {
+		return checkRemoteCluster(worker.tomb.Dying())
+	}
+	w.NewWorker(
+		remoteTriggerName+" "+cluster.name,
+		worker.Logger,
+		worker.Database.NewLock(remoteClusterTriggerLockPrefix+cluster.name, nodataCheckerLockTTL),
+		checkRemoteCluster,
+	).Run(worker.tomb.Dying())
+
+	return nil
+}
+
+func (worker *Checker) remoteClusterTriggerChecker(cluster *remoteCluster, stop <-chan struct{}) error {
+	checkTicker := time.NewTicker(cluster.config.CheckInterval)
+	worker.Logger.Infof("%s %s started", remoteTriggerName, cluster.name)
+	for {
+		select {
+		case <-stop:
+			worker.Logger.Infof("%s %s stopped", remoteTriggerName, cluster.name)
+			checkTicker.Stop()
+			return nil
+		case <-checkTicker.C:
+			if err := worker.checkRemoteCluster(cluster); err != nil {
+				worker.Logger.Errorf("%s %s failed: %s", remoteTriggerName, cluster.name, err.Error())
+			}
+		}
+	}
+}
+
+func (worker *Checker) checkRemoteCluster(cluster *remoteCluster) error {
+	remoteAvailable, err := cluster.source.(*remote.Remote).IsRemoteAvailable()
+	if !remoteAvailable {
+		worker.Logger.Infof("Remote cluster %s API is unavailable. Stop checking its triggers. Error: %s", cluster.name, err.Error())
+		return nil
+	}
+	worker.Logger.Debugf("Checking remote cluster %s triggers", cluster.name)
+	triggerIDs, err := worker.Database.GetRemoteClusterTriggerIDs(cluster.name)
+	if err != nil {
+		return err
+	}
+	needToCheckTriggerIDs := worker.getTriggerIDsToCheck(triggerIDs)
+	if len(needToCheckTriggerIDs) == 0 {
+		return nil
+	}
+	return worker.Database.AddRemoteClusterTriggersToCheck(cluster.name, worker.getShardID(), needToCheckTriggerIDs)
+}
+
+// startRemoteClusterTriggerHandler is blocking func, it updates checks heartbeat of cluster after every check
+func (worker *Checker) startRemoteClusterTriggerHandler(cluster *remoteCluster, triggerIDsToCheck <-chan string) error {
+	for {
+		triggerID, ok := <-triggerIDsToCheck
+		if !ok {
+			return nil
+		}
+		if err := worker.handleTrigger(triggerID, cluster.metrics); err != nil {
+			worker.handleTriggerError(triggerID, err, cluster.metrics)
+			continue
+		}
+		if err := worker.Database.UpdateRemoteClusterChecksHeartbeat(cluster.name); err != nil {
+			worker.Logger.Errorf("Failed to update remote cluster %s checks heartbeat: %s", cluster.name, err.Error())
+		}
+	}
+}
+
+// excludeRemoteClustersTriggerIDs removes triggers of remote clusters from given remote triggers,
+// so default remote checker doesn't check them
+func (worker *Checker) excludeRemoteClustersTriggerIDs(triggerIDs []string) ([]string, error) {
+	if len(worker.RemoteClusters) == 0 {
+		return triggerIDs, nil
+	}
+	clustersTriggerIDs := make(map[string]bool)
+	for _, clusterName := range worker.getRemoteClusterNames() {
+		clusterTriggerIDs, err := worker.Database.GetRemoteClusterTriggerIDs(clusterName)
+		if err != nil {
+			return nil, err
+		}
+		for _, triggerID := range clusterTriggerIDs {
+			clustersTriggerIDs[triggerID] = true
+		}
+	}
+	result := make([]string, 0, len(triggerIDs))
+	for _, triggerID := range triggerIDs {
+		if !clustersTriggerIDs[triggerID] {
+			result = append(result, triggerID)
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,75 @@
+package worker
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/moira-alert/moira/checker"
+	logging "github.com/moira-alert/moira/logging/zerolog_adapter"
+	metricSource "github.com/moira-alert/moira/metric_source"
+	"github.com/moira-alert/moira/metric_source/remote"
+	"github.com/moira-alert/moira/metrics"
+	mock_metric_source "github.com/moira-alert/moira/mock/metric_source"
+	mock_moira_alert "github.com/moira-alert/moira/mock/moira-alert"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestRemoteClusters(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	dataBase := mock_moira_alert.NewMockDatabase(mockCtrl)
+	logger, _ := logging.GetLogger("Test")
+
+	euSource := mock_metric_source.NewMockMetricSource(mockCtrl)
+	usSource := mock_metric_source.NewMockMetricSource(mockCtrl)
+	sourceProvider := metricSource.CreateMetricSourceProvider(nil, nil, nil)
+	sourceProvider.RegisterRemoteCluster("eu", euSource)
+	sourceProvider.RegisterRemoteCluster("us", usSource)
+
+	checkerMetrics := metrics.ConfigureCheckerMetrics(metrics.NewDummyRegistry(), true)
+	checkerMetrics.ConfigureRemoteClusterMetrics(metrics.NewDummyRegistry(), "eu")
+
+	worker := &Checker{
+		Logger:         logger,
+		Database:       dataBase,
+		Config:         &checker.Config{},
+		SourceProvider: sourceProvider,
+		Metrics:        checkerMetrics,
+		RemoteClusters: map[string]*remote.Config{
+			"us": {Enabled: false},
+			"eu": {Enabled: true},
+		},
+	}
+
+	Convey("Test remote clusters", t, func() {
+		Convey("Only enabled clusters are checked", func() {
+			euSource.EXPECT().IsConfigured().Return(true, nil)
+			usSource.EXPECT().IsConfigured().Return(false, nil)
+			clusters := worker.getRemoteClusters()
+			So(clusters, ShouldHaveLength, 1)
+			So(clusters[0].name, ShouldEqual, "eu")
+			So(clusters[0].config, ShouldEqual, worker.RemoteClusters["eu"])
+			So(clusters[0].metrics, ShouldEqual, checkerMetrics.RemoteClustersMetrics["eu"])
+		})
+
+		Convey("Cluster names are sorted", func() {
+			So(worker.getRemoteClusterNames(), ShouldResemble, []string{"eu", "us"})
+		})
+
+		Convey("Triggers of clusters are excluded from default remote triggers", func() {
+			dataBase.EXPECT().GetRemoteClusterTriggerIDs("eu").Return([]string{"trigger-2"}, nil)
+			dataBase.EXPECT().GetRemoteClusterTriggerIDs("us").Return([]string{"trigger-3"}, nil)
+			triggerIDs, err := worker.excludeRemoteClustersTriggerIDs([]string{"trigger-1", "trigger-2", "trigger-3", "trigger-4"})
+			So(err, ShouldBeNil)
+			So(triggerIDs, ShouldResemble, []string{"trigger-1", "trigger-4"})
+		})
+
+		Convey("Shard ID is empty if sharding is disabled", func() {
+			worker.instanceID = "checker-1"
+			So(worker.getShardID(), ShouldBeEmpty)
+			worker.Config.Sharding.Enabled = true
+			So(worker.getShardID(), ShouldEqual, "checker-1")
+			worker.Config.Sharding.Enabled = false
+		})
+	})
+}
@@ -0,0 +1,46 @@
+package worker
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/moira-alert/moira"
+	mock_moira_alert "github.com/moira-alert/moira/mock/moira-alert"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestExcludeUnavailableSourcesTriggerIDs(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	dataBase := mock_moira_alert.NewMockDatabase(mockCtrl)
+	worker := &Checker{Database: dataBase}
+	triggerIDs := []string{"graphite", "prometheus", "removed"}
+
+	Convey("Test exclude unavailable sources triggers", t, func() {
+		Convey("All triggers are kept if all sources are available", func() {
+			sourcesAvailability := map[moira.TriggerSource]bool{moira.GraphiteRemote: true, moira.PrometheusRemote: true}
+			So(isAnySourceAvailable(sourcesAvailability), ShouldBeTrue)
+			actual, err := worker.excludeUnavailableSourcesTriggerIDs(triggerIDs, sourcesAvailability)
+			So(err, ShouldBeNil)
+			So(actual, ShouldResemble, triggerIDs)
+		})
+
+		Convey("Triggers of unavailable source are excluded", func() {
+			sourcesAvailability := map[moira.TriggerSource]bool{moira.GraphiteRemote: false, moira.PrometheusRemote: true}
+			So(isAnySourceAvailable(sourcesAvailability), ShouldBeTrue)
+			dataBase.EXPECT().GetTriggers(triggerIDs).Return([]*moira.Trigger{
+				{ID: "graphite", IsRemote: true},
+				{ID: "prometheus", IsRemote: true, TriggerSource: moira.PrometheusRemote},
+				nil,
+			}, nil)
+			actual, err := worker.excludeUnavailableSourcesTriggerIDs(triggerIDs, sourcesAvailability)
+			So(err, ShouldBeNil)
+			So(actual, ShouldResemble, []string{"prometheus"})
+		})
+
+		Convey("No source is available", func() {
+			So(isAnySourceAvailable(map[moira.TriggerSource]bool{moira.GraphiteRemote: false}), ShouldBeFalse)
+			So(isAnySourceAvailable(map[moira.TriggerSource]bool{}), ShouldBeFalse)
+		})
+	})
+}
@@ -0,0 +1,222 @@
+package worker
+
+import (
+	"crypto/md5" //nolint
+	"encoding/binary"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+// ringVirtualNodes is number of points of every checker instance on hash ring, it smooths shards sizes
+const ringVirtualNodes = 100
+
+// hashRing assigns trigger IDs to checker instances by consistent hashing,
+// so only triggers of added or removed instance change owner on rebalance
+type hashRing struct {
+	instances []string
+	points    []uint32
+	owners    map[uint32]string
+	// ring used before rebalance which built this ring, it is nil for ring which is not result of rebalance
+	previous     *hashRing
+	rebalancedAt time.Time
+}
+
+func newHashRing(instanceIDs []string) *hashRing {
+	instances := append([]string(nil), instanceIDs...)
+	sort.Strings(instances)
+	ring := &hashRing{
+		instances: instances,
+		points:    make([]uint32, 0, len(instances)*ringVirtualNodes),
+		owners:    make(map[uint32]string, len(instances)*ringVirtualNodes),
+	}
+	for _, instanceID := range instances {
+		for i := 0; i < ringVirtualNodes; i++ {
+			point := ringHash(instanceID + "#" + strconv.Itoa(i))
+			if _, ok := ring.owners[point]; ok {
+				continue
+			}
+			ring.owners[point] = instanceID
+			ring.points = append(ring.points, point)
+		}
+	}
+	sort.Slice(ring.points, func(i, j int) bool { return ring.points[i] < ring.points[j] })
+	return ring
+}
+
+// getOwner returns ID of checker instance which checks given trigger
+func (ring *hashRing) getOwner(triggerID string) string {
+	if len(ring.points) == 0 {
+		return ""
+	}
+	hash := ringHash(triggerID)
+	index := sort.Search(len(ring.points), func(i int) bool { return ring.points[i] >= hash })
+	if index == len(ring.points) {
+		index = 0
+	}
+	return ring.owners[ring.points[index]]
+}
+
+// rebalance builds ring of given instances which remembers this ring as previous one
+func (ring *hashRing) rebalance(instanceIDs []string) *hashRing {
+	newRing := newHashRing(instanceIDs)
+	newRing.previous = &hashRing{instances: ring.instances, points: ring.points, owners: ring.owners}
+	newRing.rebalancedAt = time.Now()
+	return newRing
+}
+
+// isOwnerChanged checks that trigger changed owner on rebalance which built this ring
+func (ring *hashRing) isOwnerChanged(triggerID string) bool {
+	return ring.previous != nil && ring.previous.getOwner(triggerID) != ring.getOwner(triggerID)
+}
+
+// getRemovedInstances returns instances of ring which are absent in given list
+func (ring *hashRing) getRemovedInstances(instanceIDs []string) []string {
+	alive := make(map[string]bool, len(instanceIDs))
+	for _, instanceID := range instanceIDs {
+		alive[instanceID] = true
+	}
+	removed := make([]string, 0)
+	for _, instanceID := range ring.instances {
+		if !alive[instanceID] {
+			removed = append(removed, instanceID)
+		}
+	}
+	return removed
+}
+
+// hasInstances checks that ring consists of given instances
+func (ring *hashRing) hasInstances(instanceIDs []string) bool {
+	if len(ring.instances) != len(instanceIDs) {
+		return false
+	}
+	return len(ring.getRemovedInstances(instanceIDs)) == 0
+}
+
+// ringHash returns evenly distributed hash of key, it is not used for security
+func ringHash(key string) uint32 {
+	sum := md5.Sum([]byte(key)) //nolint
+	return binary.BigEndian.Uint32(sum[:4])
+}
+
+// startSharding registers checker instance and starts worker which keeps its heartbeat and rebalances shards
+// when checker instances come and go
+func (worker *Checker) startSharding() error {
+	instanceID, err := uuid.NewV4()
+	if err != nil {
+		return err
+	}
+	worker.instanceID = instanceID.String()
+	// Owners of triggers are unknown before instance joins, so all its triggers are considered moved to it by first rebalance
+	worker.shardRing.Store(newHashRing(nil))
+	if err := worker.refreshShardRing(); err != nil {
+		return err
+	}
+	worker.Logger.Infof("Triggers sharding enabled, checker instance ID: %s", worker.instanceID)
+	worker.tomb.Go(worker.shardingWorker)
+	return nil
+}
+
+func (worker *Checker) shardingWorker() error {
+	checkTicker := time.NewTicker(worker.Config.Sharding.HeartbeatInterval)
+	for {
+		select {
+		case <-worker.tomb.Dying():
+			checkTicker.Stop()
+			if err := worker.Database.RemoveCheckerInstance(worker.instanceID, worker.getRemoteClusterNames()); err != nil {
+				worker.Logger.Errorf("Failed to remove checker instance: %s", err.Error())
+			}
+			worker.Logger.Info("Triggers sharding stopped")
+			return nil
+		case <-checkTicker.C:
+			if err := worker.refreshShardRing(); err != nil {
+				worker.Logger.Errorf("Failed to refresh checker shards: %s", err.Error())
+			}
+		}
+	}
+}
+
+// refreshShardRing updates heartbeat of checker instance and rebuilds hash ring if alive instances changed
+func (worker *Checker) refreshShardRing() error {
+	if err := worker.Database.UpdateCheckerInstanceHeartbeat(worker.instanceID); err != nil {
+		return err
+	}
+	aliveSince := time.Now().Add(-worker.Config.Sharding.InstanceTimeout).Unix()
+	instanceIDs, err := worker.Database.GetCheckerInstances(aliveSince)
+	if err != nil {
+		return err
+	}
+
+	worker.Metrics.ShardCheckersCount.Update(int64(len(instanceIDs)))
+	ring := worker.shardRing.Load().(*hashRing)
+	if ring.hasInstances(instanceIDs) {
+		return nil
+	}
+	for _, instanceID := range ring.getRemovedInstances(instanceIDs) {
+		if err := worker.Database.RemoveCheckerInstance(instanceID, worker.getRemoteClusterNames()); err != nil {
+			worker.Logger.Errorf("Failed to remove checker instance %s: %s", instanceID, err.Error())
+		}
+	}
+	worker.shardRing.Store(ring.rebalance(instanceIDs))
+	worker.Metrics.ShardRebalances.Mark(1)
+	worker.Logger.Infof("Triggers shards rebalanced between %d checker instances", len(instanceIDs))
+	return nil
+}
+
+// isOwnTrigger checks that trigger belongs to shard of checker instance, every trigger does if sharding is disabled
+func (worker *Checker) isOwnTrigger(triggerID string) bool {
+	if !worker.Config.Sharding.Enabled {
+		return true
+	}
+	return worker.shardRing.Load().(*hashRing).getOwner(triggerID) == worker.instanceID
+}
+
+// isCheckLockNeeded checks that trigger may be checked by other checker instance at the same time.
+// Without sharding every instance checks any trigger, with sharding only trigger which changed owner on recent rebalance
+// may still be checked by its previous owner until it notices rebalance
+func (worker *Checker) isCheckLockNeeded(triggerID string) bool {
+	if !worker.Config.Sharding.Enabled {
+		return true
+	}
+	ring := worker.shardRing.Load().(*hashRing)
+	if time.Since(ring.rebalancedAt) > worker.Config.Sharding.InstanceTimeout {
+		return false
+	}
+	return ring.isOwnerChanged(triggerID)
+}
+
+// filterOwnTriggers drops triggers which were queued to shard of checker instance before they moved to other shard on rebalance,
+// their new owner queues them itself
+func (worker *Checker) filterOwnTriggers(triggerIDs []string) []string {
+	ownTriggerIDs := make([]string, 0, len(triggerIDs))
+	for _, triggerID := range triggerIDs {
+		if worker.isOwnTrigger(triggerID) {
+			ownTriggerIDs = append(ownTriggerIDs, triggerID)
+		}
+	}
+	return ownTriggerIDs
+}
+
+// getShardID returns ID of checker instance shard, it is empty if sharding is disabled
+func (worker *Checker) getShardID() string {
+	if !worker.Config.Sharding.Enabled {
+		return ""
+	}
+	return worker.instanceID
+}
+
+// updateShardTriggersCount reports number of given triggers belonging to shard of checker instance
+func (worker *Checker) updateShardTriggersCount(triggerIDs []string) {
+	if !worker.Config.Sharding.Enabled {
+		return
+	}
+	var count int64
+	for _, triggerID := range triggerIDs {
+		if worker.isOwnTrigger(triggerID) {
+			count++
+		}
+	}
+	worker.Metrics.ShardTriggersCount.Update(count)
+}
@@ -0,0 +1,156 @@
+package worker
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/checker"
+	"github.com/moira-alert/moira/database"
+	logging "github.com/moira-alert/moira/logging/zerolog_adapter"
+	"github.com/moira-alert/moira/metrics"
+	mock_moira_alert "github.com/moira-alert/moira/mock/moira-alert"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestHashRing(t *testing.T) {
+	triggerIDs := make([]string, 0, 1000)
+	for i := 0; i < 1000; i++ {
+		triggerIDs = append(triggerIDs, fmt.Sprintf("trigger-%d", i))
+	}
+	getOwners := func(ring *hashRing) map[string]string {
+		owners := make(map[string]string, len(triggerIDs))
+		for _, triggerID := range triggerIDs {
+			owners[triggerID] = ring.getOwner(triggerID)
+		}
+		return owners
+	}
+
+	Convey("Test hash ring", t, func() {
+		Convey("Empty ring has no owners", func() {
+			So(newHashRing(nil).getOwner("trigger-1"), ShouldBeEmpty)
+		})
+
+		Convey("Ring does not depend on instances order", func() {
+			So(getOwners(newHashRing([]string{"a", "b", "c"})), ShouldResemble, getOwners(newHashRing([]string{"c", "a", "b"})))
+		})
+
+		Convey("Triggers are spread between all instances", func() {
+			shards := make(map[string]int)
+			for _, owner := range getOwners(newHashRing([]string{"a", "b", "c"})) {
+				shards[owner]++
+			}
+			So(shards, ShouldHaveLength, 3)
+			for _, size := range shards {
+				So(size, ShouldBeGreaterThan, 200)
+			}
+		})
+
+		Convey("Only triggers of removed instance change owner", func() {
+			before := getOwners(newHashRing([]string{"a", "b", "c"}))
+			after := getOwners(newHashRing([]string{"a", "b"}))
+			for triggerID, owner := range before {
+				if owner != "c" {
+					So(after[triggerID], ShouldEqual, owner)
+				} else {
+					So(after[triggerID], ShouldBeIn, []string{"a", "b"})
+				}
+			}
+		})
+
+		Convey("Instances changes are detected", func() {
+			ring := newHashRing([]string{"a", "b", "c"})
+			So(ring.hasInstances([]string{"c", "b", "a"}), ShouldBeTrue)
+			So(ring.hasInstances([]string{"a", "b"}), ShouldBeFalse)
+			So(ring.hasInstances([]string{"a", "b", "d"}), ShouldBeFalse)
+			So(ring.getRemovedInstances([]string{"a", "d"}), ShouldResemble, []string{"b", "c"})
+		})
+	})
+}
+
+func TestFilterOwnTriggers(t *testing.T) {
+	Convey("Test filter own triggers", t, func() {
+		worker := &Checker{Config: &checker.Config{Sharding: checker.ShardingConfig{Enabled: true}}, instanceID: "a"}
+		ring := newHashRing([]string{"a", "b"})
+		worker.shardRing.Store(ring)
+
+		triggerIDs := make([]string, 0, 100)
+		ownTriggerIDs := make([]string, 0, 100)
+		for i := 0; i < 100; i++ {
+			triggerID := fmt.Sprintf("trigger-%d", i)
+			triggerIDs = append(triggerIDs, triggerID)
+			if ring.getOwner(triggerID) == "a" {
+				ownTriggerIDs = append(ownTriggerIDs, triggerID)
+			}
+		}
+
+		Convey("Triggers moved to other shard are dropped", func() {
+			So(worker.filterOwnTriggers(triggerIDs), ShouldResemble, ownTriggerIDs)
+		})
+
+		Convey("Every trigger is own if sharding is disabled", func() {
+			worker.Config.Sharding.Enabled = false
+			So(worker.filterOwnTriggers(triggerIDs), ShouldResemble, triggerIDs)
+		})
+	})
+}
+
+func TestIsCheckLockNeeded(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	dataBase := mock_moira_alert.NewMockDatabase(mockCtrl)
+	logger, _ := logging.GetLogger("Test")
+	checkMetrics := metrics.ConfigureCheckerMetrics(metrics.NewDummyRegistry(), false).LocalMetrics
+	worker := &Checker{
+		Database: dataBase,
+		Logger:   logger,
+		Config:   &checker.Config{Sharding: checker.ShardingConfig{Enabled: true, InstanceTimeout: time.Minute}},
+	}
+	ring := newHashRing([]string{"a", "b"}).rebalance([]string{"a", "b", "c"})
+	var movedTriggerID, keptTriggerID string
+	for i := 0; movedTriggerID == "" || keptTriggerID == ""; i++ {
+		triggerID := fmt.Sprintf("trigger-%d", i)
+		if ring.isOwnerChanged(triggerID) {
+			movedTriggerID = triggerID
+		} else {
+			keptTriggerID = triggerID
+		}
+	}
+
+	Convey("Test check lock is needed", t, func() {
+		Convey("Every trigger is locked if sharding is disabled", func() {
+			worker.Config.Sharding.Enabled = false
+			So(worker.isCheckLockNeeded(keptTriggerID), ShouldBeTrue)
+			worker.Config.Sharding.Enabled = true
+		})
+
+		Convey("Only triggers which changed owner are locked after rebalance", func() {
+			worker.shardRing.Store(ring)
+			So(worker.isCheckLockNeeded(movedTriggerID), ShouldBeTrue)
+			So(worker.isCheckLockNeeded(keptTriggerID), ShouldBeFalse)
+		})
+
+		Convey("Triggers are not locked when rebalance is over", func() {
+			ring.rebalancedAt = time.Now().Add(-time.Hour)
+			worker.shardRing.Store(ring)
+			So(worker.isCheckLockNeeded(movedTriggerID), ShouldBeFalse)
+			ring.rebalancedAt = time.Now()
+		})
+
+		Convey("Stable ring issues no lock calls", func() {
+			worker.shardRing.Store(newHashRing([]string{"a", "b", "c"}))
+			dataBase.EXPECT().GetTrigger(movedTriggerID).Return(moira.Trigger{}, database.ErrNil)
+			So(worker.handleTriggerInLock(movedTriggerID, checkMetrics), ShouldBeNil)
+		})
+
+		Convey("Trigger which changed owner is checked in lock", func() {
+			worker.shardRing.Store(ring)
+			dataBase.EXPECT().SetTriggerCheckLock(movedTriggerID).Return(true, nil)
+			dataBase.EXPECT().GetTrigger(movedTriggerID).Return(moira.Trigger{}, database.ErrNil)
+			dataBase.EXPECT().DeleteTriggerCheckLock(movedTriggerID).Return(nil)
+			So(worker.handleTriggerInLock(movedTriggerID, checkMetrics), ShouldBeNil)
+		})
+	})
+}
@@ -7,6 +7,36 @@ import (
 const sleepAfterGetTriggerIDError = time.Second * 1
 const sleepWhenNoTriggerToCheck = time.Millisecond * 500
 
+func (worker *Checker) getLocalTriggersToCheck(count int) ([]string, error) {
+	if worker.Config.Sharding.Enabled {
+		triggerIDs, err := worker.Database.GetLocalShardTriggersToCheck(worker.instanceID, count)
+		return worker.filterOwnTriggers(triggerIDs), err
+	}
+	return worker.Database.GetLocalTriggersToCheck(count)
+}
+
+func (worker *Checker) getRemoteTriggersToCheck(count int) ([]string, error) {
+	if worker.Config.Sharding.Enabled {
+		triggerIDs, err := worker.Database.GetRemoteShardTriggersToCheck(worker.instanceID, count)
+		return worker.filterOwnTriggers(triggerIDs), err
+	}
+	return worker.Database.GetRemoteTriggersToCheck(count)
+}
+
+func (worker *Checker) getLocalTriggersToCheckCount() (int64, error) {
+	if worker.Config.Sharding.Enabled {
+		return worker.Database.GetLocalShardTriggersToCheckCount(worker.instanceID)
+	}
+	return worker.Database.GetLocalTriggersToCheckCount()
+}
+
+func (worker *Checker) getRemoteTriggersToCheckCount() (int64, error) {
+	if worker.Config.Sharding.Enabled {
+		return worker.Database.GetRemoteShardTriggersToCheckCount(worker.instanceID)
+	}
+	return worker.Database.GetRemoteTriggersToCheckCount()
+}
+
 func (worker *Checker) startTriggerToCheckGetter(fetch func(int) ([]string, error), batchSize int) <-chan string {
 	triggerIDsToCheck := make(chan string, batchSize*2) //nolint
 	worker.tomb.Go(func() error { return worker.triggerToCheckGetter(fetch, batchSize, triggerIDsToCheck) })
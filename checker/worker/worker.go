@@ -22,15 +22,19 @@ type Checker struct {
 	Database          moira.Database
 	Config            *checker.Config
 	RemoteConfig      *remote.Config
+	RemoteClusters    map[string]*remote.Config
 	SourceProvider    *metricSource.SourceProvider
 	Metrics           *metrics.CheckerMetrics
 	TriggerCache      *cache.Cache
 	LazyTriggersCache *cache.Cache
 	PatternCache      *cache.Cache
 	lazyTriggerIDs    atomic.Value
+	instanceID        string
+	shardRing         atomic.Value
 	lastData          int64
 	tomb              tomb.Tomb
 	remoteEnabled     bool
+	remoteClusters    []*remoteCluster
 }
 
 // Start start schedule new MetricEvents and check for NODATA triggers
@@ -42,6 +46,12 @@ func (worker *Checker) Start() error {
 
 	worker.lastData = time.Now().UTC().Unix()
 
+	if worker.Config.Sharding.Enabled {
+		if err := worker.startSharding(); err != nil {
+			return err
+		}
+	}
+
 	metricEventsChannel, err := worker.Database.SubscribeMetricEvents(&worker.tomb)
 	if err != nil {
 		return err
@@ -52,10 +62,12 @@ func (worker *Checker) Start() error {
 
 	worker.tomb.Go(worker.localTriggerGetter)
 
-	_, err = worker.SourceProvider.GetRemote()
-	worker.remoteEnabled = err == nil
+	_, remoteErr := worker.SourceProvider.GetRemote()
+	_, prometheusErr := worker.SourceProvider.GetPrometheus()
+	worker.remoteEnabled = remoteErr == nil || prometheusErr == nil
+	worker.remoteClusters = worker.getRemoteClusters()
 
-	if worker.remoteEnabled && worker.Config.MaxParallelRemoteChecks == 0 {
+	if (worker.remoteEnabled || len(worker.remoteClusters) > 0) && worker.Config.MaxParallelRemoteChecks == 0 {
 		worker.Config.MaxParallelRemoteChecks = runtime.NumCPU()
 		worker.Logger.Infof("MaxParallelRemoteChecks is not configured, set it to the number of CPU - %d", worker.Config.MaxParallelRemoteChecks)
 	}
@@ -68,7 +80,7 @@ func (worker *Checker) Start() error {
 	}
 
 	worker.Logger.Infof("Start %v parallel local checker(s)", worker.Config.MaxParallelChecks)
-	localTriggerIdsToCheckChan := worker.startTriggerToCheckGetter(worker.Database.GetLocalTriggersToCheck, worker.Config.MaxParallelChecks)
+	localTriggerIdsToCheckChan := worker.startTriggerToCheckGetter(worker.getLocalTriggersToCheck, worker.Config.MaxParallelChecks)
 	for i := 0; i < worker.Config.MaxParallelChecks; i++ {
 		worker.tomb.Go(func() error {
 			return worker.newMetricsHandler(metricEventsChannel)
@@ -80,13 +92,16 @@ func (worker *Checker) Start() error {
 
 	if worker.remoteEnabled {
 		worker.Logger.Infof("Start %v parallel remote checker(s)", worker.Config.MaxParallelRemoteChecks)
-		remoteTriggerIdsToCheckChan := worker.startTriggerToCheckGetter(worker.Database.GetRemoteTriggersToCheck, worker.Config.MaxParallelRemoteChecks)
+		remoteTriggerIdsToCheckChan := worker.startTriggerToCheckGetter(worker.getRemoteTriggersToCheck, worker.Config.MaxParallelRemoteChecks)
 		for i := 0; i < worker.Config.MaxParallelRemoteChecks; i++ {
 			worker.tomb.Go(func() error {
 				return worker.startTriggerHandler(remoteTriggerIdsToCheckChan, worker.Metrics.RemoteMetrics)
 			})
 		}
 	}
+	for _, cluster := range worker.remoteClusters {
+		worker.startRemoteCluster(cluster)
+	}
 	worker.Logger.Info("Checking new events started")
 
 	go func() {
@@ -108,16 +123,22 @@ func (worker *Checker) checkTriggersToCheckCount() error {
 		case <-worker.tomb.Dying():
 			return nil
 		case <-checkTicker.C:
-			triggersToCheckCount, err = worker.Database.GetLocalTriggersToCheckCount()
+			triggersToCheckCount, err = worker.getLocalTriggersToCheckCount()
 			if err == nil {
 				worker.Metrics.LocalMetrics.TriggersToCheckCount.Update(triggersToCheckCount)
 			}
 			if worker.remoteEnabled {
-				remoteTriggersToCheckCount, err = worker.Database.GetRemoteTriggersToCheckCount()
+				remoteTriggersToCheckCount, err = worker.getRemoteTriggersToCheckCount()
 				if err == nil {
 					worker.Metrics.RemoteMetrics.TriggersToCheckCount.Update(remoteTriggersToCheckCount)
 				}
 			}
+			for _, cluster := range worker.remoteClusters {
+				clusterTriggersToCheckCount, err := worker.Database.GetRemoteClusterTriggersToCheckCount(cluster.name, worker.getShardID())
+				if err == nil {
+					cluster.metrics.TriggersToCheckCount.Update(clusterTriggersToCheckCount)
+				}
+			}
 		}
 	}
 }
@@ -8,15 +8,18 @@ import (
 
 	"github.com/moira-alert/moira/api"
 	"github.com/moira-alert/moira/cmd"
+	"github.com/moira-alert/moira/expression"
 )
 
 type config struct {
-	Redis     cmd.RedisConfig     `yaml:"redis"`
-	Logger    cmd.LoggerConfig    `yaml:"log"`
-	API       apiConfig           `yaml:"api"`
-	Web       webConfig           `yaml:"web"`
-	Telemetry cmd.TelemetryConfig `yaml:"telemetry"`
-	Remote    cmd.RemoteConfig    `yaml:"remote"`
+	Redis          cmd.RedisConfig           `yaml:"redis"`
+	Logger         cmd.LoggerConfig          `yaml:"log"`
+	API            apiConfig                 `yaml:"api"`
+	Web            webConfig                 `yaml:"web"`
+	Telemetry      cmd.TelemetryConfig       `yaml:"telemetry"`
+	Remote         cmd.RemoteConfig          `yaml:"remote"`
+	Prometheus     cmd.PrometheusConfig      `yaml:"prometheus"`
+	RemoteClusters []cmd.RemoteClusterConfig `yaml:"remote_clusters"`
 }
 
 type apiConfig struct {
@@ -58,7 +61,7 @@ func (config *apiConfig) getSettings(localMetricTTL, remoteMetricTTL string) *ap
 	}
 }
 
-func (config *webConfig) getSettings(isRemoteEnabled bool) ([]byte, error) {
+func (config *webConfig) getSettings(isRemoteEnabled, isPrometheusEnabled bool, remoteClusters []string) ([]byte, error) {
 	webContacts := make([]api.WebContact, 0, len(config.Contacts))
 	for _, configContact := range config.Contacts {
 		contact := api.WebContact{
@@ -70,10 +73,22 @@ func (config *webConfig) getSettings(isRemoteEnabled bool) ([]byte, error) {
 		}
 		webContacts = append(webContacts, contact)
 	}
+	functionDescriptions := expression.GetFunctionDescriptions()
+	expressionFunctions := make([]api.WebExpressionFunction, 0, len(functionDescriptions))
+	for _, description := range functionDescriptions {
+		expressionFunctions = append(expressionFunctions, api.WebExpressionFunction{
+			Name:        description.Name,
+			Signature:   description.Signature,
+			Description: description.Description,
+		})
+	}
 	configContent, err := json.Marshal(api.WebConfig{
-		SupportEmail:  config.SupportEmail,
-		RemoteAllowed: isRemoteEnabled,
-		Contacts:      webContacts,
+		SupportEmail:        config.SupportEmail,
+		RemoteAllowed:       isRemoteEnabled,
+		PrometheusAllowed:   isPrometheusEnabled,
+		RemoteClusters:      remoteClusters,
+		Contacts:            webContacts,
+		ExpressionFunctions: expressionFunctions,
 	})
 	if err != nil {
 		return make([]byte, 0), fmt.Errorf("failed to parse web config: %s", err.Error())
@@ -116,5 +131,10 @@ func getDefault() config {
 			Timeout:    "60s",
 			MetricsTTL: "7d",
 		},
+		Prometheus: cmd.PrometheusConfig{
+			MetricsTTL: "7d",
+			Timeout:    "60s",
+			Step:       "60s",
+		},
 	}
 }
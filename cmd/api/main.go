@@ -19,6 +19,7 @@ import (
 	logging "github.com/moira-alert/moira/logging/zerolog_adapter"
 	metricSource "github.com/moira-alert/moira/metric_source"
 	"github.com/moira-alert/moira/metric_source/local"
+	"github.com/moira-alert/moira/metric_source/prometheus"
 	"github.com/moira-alert/moira/metric_source/remote"
 )
 
@@ -105,9 +106,25 @@ func main() {
 	localSource := local.Create(database)
 	remoteConfig := config.Remote.GetRemoteSourceSettings()
 	remoteSource := remote.Create(remoteConfig)
-	metricSourceProvider := metricSource.CreateMetricSourceProvider(localSource, remoteSource)
+	if _, err := remoteSource.IsConfigured(); err != nil && err != remote.ErrRemoteStorageDisabled {
+		logger.Fatalf("Can not configure remote: %s", err.Error())
+	}
+	prometheusConfig := config.Prometheus.GetPrometheusSourceSettings()
+	prometheusSource := prometheus.Create(prometheusConfig)
+	metricSourceProvider := metricSource.CreateMetricSourceProvider(localSource, remoteSource, prometheusSource)
+	remoteClustersConfig, err := cmd.GetRemoteClustersSettings(config.RemoteClusters, config.Remote)
+	if err != nil {
+		logger.Fatalf("Can not configure remote clusters: %s", err.Error())
+	}
+	for clusterName, clusterConfig := range remoteClustersConfig {
+		clusterSource := remote.Create(clusterConfig)
+		if _, err := clusterSource.IsConfigured(); err != nil && err != remote.ErrRemoteStorageDisabled {
+			logger.Fatalf("Can not configure remote cluster %s: %s", clusterName, err.Error())
+		}
+		metricSourceProvider.RegisterRemoteCluster(clusterName, clusterSource)
+	}
 
-	webConfigContent, err := config.Web.getSettings(remoteConfig.Enabled)
+	webConfigContent, err := config.Web.getSettings(remoteConfig.Enabled, prometheusConfig.Enabled, metricSourceProvider.GetRemoteClusterNames())
 	if err != nil {
 		logger.Fatal(err)
 	}
@@ -8,11 +8,13 @@ import (
 )
 
 type config struct {
-	Redis     cmd.RedisConfig     `yaml:"redis"`
-	Logger    cmd.LoggerConfig    `yaml:"log"`
-	Checker   checkerConfig       `yaml:"checker"`
-	Telemetry cmd.TelemetryConfig `yaml:"telemetry"`
-	Remote    cmd.RemoteConfig    `yaml:"remote"`
+	Redis          cmd.RedisConfig           `yaml:"redis"`
+	Logger         cmd.LoggerConfig          `yaml:"log"`
+	Checker        checkerConfig             `yaml:"checker"`
+	Telemetry      cmd.TelemetryConfig       `yaml:"telemetry"`
+	Remote         cmd.RemoteConfig          `yaml:"remote"`
+	Prometheus     cmd.PrometheusConfig      `yaml:"prometheus"`
+	RemoteClusters []cmd.RemoteClusterConfig `yaml:"remote_clusters"`
 }
 
 type triggerLogConfig struct {
@@ -40,6 +42,21 @@ type checkerConfig struct {
 	MaxParallelRemoteChecks int `yaml:"max_parallel_remote_checks"`
 	// Specify log level by entities
 	SetLogLevel triggersLogConfig `yaml:"set_log_level"`
+	// Sharding of triggers between checker instances
+	Sharding shardingConfig `yaml:"sharding"`
+	// If true, fetches of remote metric sources made by triggers checked within the same remote check interval are coalesced
+	RemoteFetchCache bool `yaml:"remote_fetch_cache"`
+}
+
+type shardingConfig struct {
+	// If true, every checker instance checks only triggers of its own shard without trigger check locks.
+	// Locks guard checks of triggers which changed owner only for instance_timeout after rebalance
+	// Shards are assigned to alive instances by consistent hashing of trigger IDs and rebalanced when instances come and go
+	Enabled bool `yaml:"enabled"`
+	// Period for checker instance to report it is alive and to refresh list of alive instances
+	HeartbeatInterval string `yaml:"heartbeat_interval"`
+	// Period after last heartbeat for checker instance to be considered dead. Its shard is rebalanced between other instances
+	InstanceTimeout string `yaml:"instance_timeout"`
 }
 
 func (config *checkerConfig) getSettings(logger moira.Logger) *checker.Config {
@@ -57,6 +74,11 @@ func (config *checkerConfig) getSettings(logger moira.Logger) *checker.Config {
 		MaxParallelChecks:           config.MaxParallelChecks,
 		MaxParallelRemoteChecks:     config.MaxParallelRemoteChecks,
 		LogTriggersToLevel:          logTriggersToLevel,
+		Sharding: checker.ShardingConfig{
+			Enabled:           config.Sharding.Enabled,
+			HeartbeatInterval: to.Duration(config.Sharding.HeartbeatInterval),
+			InstanceTimeout:   to.Duration(config.Sharding.InstanceTimeout),
+		},
 	}
 }
 
@@ -80,6 +102,12 @@ func getDefault() config {
 			StopCheckingInterval:      "30s",
 			MaxParallelChecks:         0,
 			MaxParallelRemoteChecks:   0,
+			Sharding: shardingConfig{
+				Enabled:           false,
+				HeartbeatInterval: "5s",
+				InstanceTimeout:   "15s",
+			},
+			RemoteFetchCache: true,
 		},
 		Telemetry: cmd.TelemetryConfig{
 			Listen: ":8092",
@@ -93,9 +121,18 @@ func getDefault() config {
 			Pprof: cmd.ProfilerConfig{Enabled: false},
 		},
 		Remote: cmd.RemoteConfig{
-			CheckInterval: "60s",
-			Timeout:       "60s",
-			MetricsTTL:    "7d",
+			CheckInterval:           "60s",
+			Timeout:                 "60s",
+			MetricsTTL:              "7d",
+			MaxRetries:              2,
+			RetryDelay:              "1s",
+			CircuitBreakerThreshold: 10,
+			CircuitBreakerTimeout:   "1m",
+		},
+		Prometheus: cmd.PrometheusConfig{
+			MetricsTTL: "7d",
+			Timeout:    "60s",
+			Step:       "60s",
 		},
 	}
 }
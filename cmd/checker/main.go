@@ -10,6 +10,7 @@ import (
 
 	metricSource "github.com/moira-alert/moira/metric_source"
 	"github.com/moira-alert/moira/metric_source/local"
+	"github.com/moira-alert/moira/metric_source/prometheus"
 	"github.com/moira-alert/moira/metric_source/remote"
 	"github.com/patrickmn/go-cache"
 
@@ -80,10 +81,37 @@ func main() {
 	remoteConfig := config.Remote.GetRemoteSourceSettings()
 	localSource := local.Create(database)
 	remoteSource := remote.Create(remoteConfig)
-	metricSourceProvider := metricSource.CreateMetricSourceProvider(localSource, remoteSource)
+	if _, err := remoteSource.IsConfigured(); err != nil && err != remote.ErrRemoteStorageDisabled {
+		logger.Fatalf("Can not configure remote: %s", err.Error())
+	}
+	prometheusSource := prometheus.Create(config.Prometheus.GetPrometheusSourceSettings())
+	metricSourceProvider := metricSource.CreateMetricSourceProvider(localSource, remoteSource, prometheusSource)
+	remoteClustersConfig, err := cmd.GetRemoteClustersSettings(config.RemoteClusters, config.Remote)
+	if err != nil {
+		logger.Fatalf("Can not configure remote clusters: %s", err.Error())
+	}
+	clusterSources := make(map[string]metricSource.MetricSource, len(remoteClustersConfig))
+	for clusterName, clusterConfig := range remoteClustersConfig {
+		clusterSources[clusterName] = remote.Create(clusterConfig)
+		if _, err := clusterSources[clusterName].IsConfigured(); err != nil && err != remote.ErrRemoteStorageDisabled {
+			logger.Fatalf("Can not configure remote cluster %s: %s", clusterName, err.Error())
+		}
+		metricSourceProvider.RegisterRemoteCluster(clusterName, clusterSources[clusterName])
+	}
 
-	isConfigured, _ := remoteSource.IsConfigured()
-	checkerMetrics := metrics.ConfigureCheckerMetrics(telemetry.Metrics, isConfigured)
+	isRemoteConfigured, _ := remoteSource.IsConfigured()
+	isPrometheusConfigured, _ := prometheusSource.IsConfigured()
+	checkerMetrics := metrics.ConfigureCheckerMetrics(telemetry.Metrics, isRemoteConfigured || isPrometheusConfigured || len(remoteClustersConfig) > 0)
+	for clusterName := range remoteClustersConfig {
+		checkerMetrics.ConfigureRemoteClusterMetrics(telemetry.Metrics, clusterName)
+	}
+	if config.Checker.RemoteFetchCache {
+		metricSourceProvider.EnableFetchCache(remoteSource, remoteConfig.CheckInterval, checkerMetrics.FetchCache)
+		metricSourceProvider.EnableFetchCache(prometheusSource, remoteConfig.CheckInterval, checkerMetrics.FetchCache)
+		for clusterName, clusterSource := range clusterSources {
+			metricSourceProvider.EnableFetchCache(clusterSource, remoteClustersConfig[clusterName].CheckInterval, checkerMetrics.FetchCache)
+		}
+	}
 	checkerSettings := config.Checker.getSettings(logger)
 	if triggerID != nil && *triggerID != "" {
 		checkSingleTrigger(database, checkerMetrics, checkerSettings, metricSourceProvider)
@@ -94,6 +122,7 @@ func main() {
 		Database:          database,
 		Config:            checkerSettings,
 		RemoteConfig:      remoteConfig,
+		RemoteClusters:    remoteClustersConfig,
 		SourceProvider:    metricSourceProvider,
 		Metrics:           checkerMetrics,
 		TriggerCache:      cache.New(checkerSettings.CheckInterval, time.Minute*60), //nolint
@@ -3,11 +3,13 @@ package cmd
 import (
 	"fmt"
 	"io/ioutil"
+	"regexp"
 	"strings"
 
 	"github.com/moira-alert/moira/metrics"
 
 	"github.com/moira-alert/moira/image_store/s3"
+	prometheusSource "github.com/moira-alert/moira/metric_source/prometheus"
 	remoteSource "github.com/moira-alert/moira/metric_source/remote"
 	"github.com/xiam/to"
 	"gopkg.in/yaml.v2"
@@ -112,6 +114,71 @@ type RemoteConfig struct {
 	Password string `yaml:"password"`
 	// If true, remote worker will be enabled.
 	Enabled bool `yaml:"enabled"`
+	// Max count of trigger targets fetched by one render request. Batching is disabled if value is less than 2
+	MaxBatchTargets int `yaml:"max_batch_targets"`
+	// Max count of retries of remote request failed with timeout, connection, 408, 429 or 5xx error. Retries are disabled if value is 0.
+	// Request with all its retries takes no longer than check_interval
+	MaxRetries int `yaml:"max_retries"`
+	// Delay before first retry of remote request. It is doubled every next retry and randomized by jitter
+	RetryDelay string `yaml:"retry_delay"`
+	// Count of consecutive failed remote requests after which requests are suspended. Circuit breaker is disabled if value is 0
+	CircuitBreakerThreshold int `yaml:"circuit_breaker_threshold"`
+	// Period remote requests are suspended for when circuit breaker is open
+	CircuitBreakerTimeout string `yaml:"circuit_breaker_timeout"`
+	// File password for basic auth is read from. It takes precedence over password
+	PasswordFile string `yaml:"password_file"`
+	// Token sent in Authorization header. It takes precedence over basic auth
+	BearerToken string `yaml:"bearer_token"`
+	// File bearer token is read from. It takes precedence over bearer_token
+	BearerTokenFile string `yaml:"bearer_token_file"`
+	// Headers added to every remote request
+	Headers map[string]string `yaml:"headers"`
+	// Headers added to every remote request with values read from files, e.g. X-Api-Key: /etc/moira/api-key
+	HeaderFiles map[string]string `yaml:"header_files"`
+	// TLS settings of connection to remote storage
+	TLS RemoteTLSConfig `yaml:"tls"`
+}
+
+// RemoteTLSConfig is TLS settings structure of connection to remote storage
+type RemoteTLSConfig struct {
+	// File with CA certificates used to verify remote server certificate. System CA certificates are used if not set
+	CAFile string `yaml:"ca_file"`
+	// Files with client certificate and its key used for mutual TLS
+	CertFile string `yaml:"cert_file"`
+	KeyFile  string `yaml:"key_file"`
+	// If true, remote server certificate is not verified
+	InsecureSkipVerify bool `yaml:"insecure_skip_verify"`
+}
+
+// PrometheusConfig is Prometheus-compatible remote storage settings structure
+type PrometheusConfig struct {
+	// Prometheus API url e.g http://prometheus:9090, HTTP API of VictoriaMetrics or Thanos Query can be used as well
+	URL string `yaml:"url"`
+	// Moira won't fetch metrics older than this value from prometheus
+	MetricsTTL string `yaml:"metrics_ttl"`
+	// Timeout for prometheus requests
+	Timeout string `yaml:"timeout"`
+	// Resolution of metrics fetched by PromQL queries
+	Step string `yaml:"step"`
+	// Username for basic auth
+	User string `yaml:"user"`
+	// Password for basic auth
+	Password string `yaml:"password"`
+	// If true, prometheus triggers will be checked by remote worker with remote check interval
+	Enabled bool `yaml:"enabled"`
+}
+
+// GetPrometheusSourceSettings returns prometheus config parsed from moira config files
+func (config *PrometheusConfig) GetPrometheusSourceSettings() *prometheusSource.Config {
+	return &prometheusSource.Config{
+		URL:        config.URL,
+		MetricsTTL: to.Duration(config.MetricsTTL),
+		Timeout:    to.Duration(config.Timeout),
+		Step:       to.Duration(config.Step),
+		User:       config.User,
+		Password:   config.Password,
+		Enabled:    config.Enabled,
+	}
 }
 
 // ImageStoreConfig defines the configuration for all the image stores to be initialized by InitImageStores
@@ -122,14 +189,82 @@ type ImageStoreConfig struct {
 // GetRemoteSourceSettings returns remote config parsed from moira config files
 func (config *RemoteConfig) GetRemoteSourceSettings() *remoteSource.Config {
 	return &remoteSource.Config{
-		URL:           config.URL,
-		CheckInterval: to.Duration(config.CheckInterval),
-		MetricsTTL:    to.Duration(config.MetricsTTL),
-		Timeout:       to.Duration(config.Timeout),
-		User:          config.User,
-		Password:      config.Password,
-		Enabled:       config.Enabled,
+		URL:                     config.URL,
+		CheckInterval:           to.Duration(config.CheckInterval),
+		MetricsTTL:              to.Duration(config.MetricsTTL),
+		Timeout:                 to.Duration(config.Timeout),
+		User:                    config.User,
+		Password:                config.Password,
+		Enabled:                 config.Enabled,
+		MaxBatchTargets:         config.MaxBatchTargets,
+		MaxRetries:              config.MaxRetries,
+		RetryDelay:              to.Duration(config.RetryDelay),
+		CircuitBreakerThreshold: config.CircuitBreakerThreshold,
+		CircuitBreakerTimeout:   to.Duration(config.CircuitBreakerTimeout),
+		PasswordFile:            config.PasswordFile,
+		BearerToken:             config.BearerToken,
+		BearerTokenFile:         config.BearerTokenFile,
+		Headers:                 config.Headers,
+		HeaderFiles:             config.HeaderFiles,
+		TLS: remoteSource.TLSConfig{
+			CAFile:             config.TLS.CAFile,
+			CertFile:           config.TLS.CertFile,
+			KeyFile:            config.TLS.KeyFile,
+			InsecureSkipVerify: config.TLS.InsecureSkipVerify,
+		},
+	}
+}
+
+// remoteClusterNameRegex is used to validate cluster names which are used as parts of database keys and metric names
+var remoteClusterNameRegex = regexp.MustCompile(`^[a-zA-Z0-9_]+$`)
+
+// RemoteClusterConfig is settings structure of named remote graphite cluster triggers can be bound to
+type RemoteClusterConfig struct {
+	// Name of cluster used in triggers, e.g. eu-west
+	Name         string `yaml:"name"`
+	RemoteConfig `yaml:",inline"`
+}
+
+// GetRemoteClustersSettings returns named remote graphite clusters config parsed from moira config files.
+// Check interval, metrics TTL, timeout, batching, retries and circuit breaker settings of cluster are taken
+// from default remote config if they are not set
+func GetRemoteClustersSettings(clusters []RemoteClusterConfig, defaults RemoteConfig) (map[string]*remoteSource.Config, error) {
+	settings := make(map[string]*remoteSource.Config, len(clusters))
+	for _, cluster := range clusters {
+		if !remoteClusterNameRegex.MatchString(cluster.Name) {
+			return nil, fmt.Errorf("remote cluster name '%s' must consist of latin letters, digits and underscores", cluster.Name)
+		}
+		if _, ok := settings[cluster.Name]; ok {
+			return nil, fmt.Errorf("remote cluster name '%s' is duplicated", cluster.Name)
+		}
+		clusterConfig := cluster.RemoteConfig
+		if clusterConfig.CheckInterval == "" {
+			clusterConfig.CheckInterval = defaults.CheckInterval
+		}
+		if clusterConfig.MetricsTTL == "" {
+			clusterConfig.MetricsTTL = defaults.MetricsTTL
+		}
+		if clusterConfig.Timeout == "" {
+			clusterConfig.Timeout = defaults.Timeout
+		}
+		if clusterConfig.MaxBatchTargets == 0 {
+			clusterConfig.MaxBatchTargets = defaults.MaxBatchTargets
+		}
+		if clusterConfig.MaxRetries == 0 {
+			clusterConfig.MaxRetries = defaults.MaxRetries
+		}
+		if clusterConfig.RetryDelay == "" {
+			clusterConfig.RetryDelay = defaults.RetryDelay
+		}
+		if clusterConfig.CircuitBreakerThreshold == 0 {
+			clusterConfig.CircuitBreakerThreshold = defaults.CircuitBreakerThreshold
+		}
+		if clusterConfig.CircuitBreakerTimeout == "" {
+			clusterConfig.CircuitBreakerTimeout = defaults.CircuitBreakerTimeout
+		}
+		settings[cluster.Name] = clusterConfig.GetRemoteSourceSettings()
 	}
+	return settings, nil
 }
 
 // ReadConfig parses config file by the given path into Moira-used type
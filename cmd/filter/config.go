@@ -2,6 +2,8 @@ package main
 
 import (
 	"github.com/moira-alert/moira/cmd"
+	"github.com/moira-alert/moira/filter"
+	"github.com/xiam/to"
 )
 
 type config struct {
@@ -14,6 +16,15 @@ type config struct {
 type filterConfig struct {
 	// Metrics listener uri
 	Listen string `yaml:"listen"`
+	// Metrics listener TLS settings
+	TLS tlsConfig `yaml:"tls"`
+	// Metrics UDP listener uri. UDP listener is disabled if empty.
+	UDPListen string `yaml:"udp_listen"`
+	// Metrics pickle protocol listener uri. Pickle listener is disabled if empty.
+	PickleListen string `yaml:"pickle_listen"`
+	// Metrics InfluxDB line protocol listener uri. Every field of line is converted to metric "<measurement>.<field>" tagged with line tags.
+	// InfluxDB listener is disabled if empty.
+	InfluxListen string `yaml:"influx_listen"`
 	// Retentions config file path.
 	// Simply use your original storage-schemas.conf or create new if you're using Moira without existing Graphite installation.
 	RetentionConfig string `yaml:"retention_config"`
@@ -26,6 +37,143 @@ type filterConfig struct {
 	MaxParallelMatches int `yaml:"max_parallel_matches"`
 	// Period in which patterns will be reloaded from Redis.
 	PatternsUpdatePeriod string `yaml:"patterns_update_period"`
+	// Prometheus remote write receiver settings.
+	PrometheusRemoteWrite remoteWriteConfig `yaml:"prometheus_remote_write"`
+	// StatsD listener settings.
+	StatsD statsdConfig `yaml:"statsd"`
+	// Rules to rewrite metrics before pattern matching. Rules are applied in order of declaration.
+	RewriteRules []rewriteRuleConfig `yaml:"rewrite_rules"`
+	// Ingestion limits settings.
+	Limits limitsConfig `yaml:"limits"`
+	// Acceptance window for point timestamps.
+	TimestampPolicy timestampPolicyConfig `yaml:"timestamp_policy"`
+	// Max number of samples per second published for every pattern tapped via API. Metric tap is disabled if 0.
+	MetricTapSamplesPerSecond int `yaml:"metric_tap_samples_per_second"`
+}
+
+func (config *filterConfig) getSettings() *filter.Config {
+	rewriteRules := make([]filter.RewriteRule, 0, len(config.RewriteRules))
+	for _, rule := range config.RewriteRules {
+		rewriteRules = append(rewriteRules, rule.getSettings())
+	}
+	return &filter.Config{
+		Enabled:         true,
+		Listen:          config.Listen,
+		RetentionConfig: config.RetentionConfig,
+		TLS:             config.TLS.getSettings(),
+		RewriteRules:    rewriteRules,
+		Limits:          config.Limits.getSettings(),
+		TimestampPolicy: config.TimestampPolicy.getSettings(),
+	}
+}
+
+type timestampPolicyConfig struct {
+	// Points with timestamps older than now minus this period are out of window, e.g. "24h". Unlimited if empty.
+	MaxPast string `yaml:"max_past"`
+	// Points with timestamps newer than now plus this period are out of window, e.g. "10m". Unlimited if empty.
+	MaxFuture string `yaml:"max_future"`
+	// Action for points out of window: "drop", "clamp" to replace timestamp with current time or "pass" to save as is.
+	// Points are dropped if empty. Last point out of window for every reason is available in API: GET /pattern/timestamps.
+	Action string `yaml:"action"`
+}
+
+func (config *timestampPolicyConfig) getSettings() filter.TimestampPolicyConfig {
+	return filter.TimestampPolicyConfig{
+		MaxPast:   to.Duration(config.MaxPast),
+		MaxFuture: to.Duration(config.MaxFuture),
+		Action:    filter.TimestampAction(config.Action),
+	}
+}
+
+type limitsConfig struct {
+	// Max number of distinct metrics matched by one pattern. New metrics over the limit are not saved for the pattern.
+	// Limit is per filter instance: every instance counts only metrics it received since start,
+	// metrics already stored in database are not counted. 0 means no limit.
+	MaxPatternMetrics int `yaml:"max_pattern_metrics"`
+	// Metric stops counting towards pattern limit if it was not received during this period.
+	PatternMetricsTTL string `yaml:"pattern_metrics_ttl"`
+	// Number of leading metric name nodes which form rate limited prefix, e.g. 2 for "Team.service.*".
+	PrefixDepth int `yaml:"prefix_depth"`
+	// Max number of points per second for metrics with the same prefix. Points over the limit are dropped.
+	// Limit is per filter instance. 0 means no limit.
+	MaxPrefixPointsPerSecond int `yaml:"max_prefix_points_per_second"`
+}
+
+func (config *limitsConfig) getSettings() filter.LimitsConfig {
+	return filter.LimitsConfig{
+		MaxPatternMetrics:        config.MaxPatternMetrics,
+		PatternMetricsTTL:        to.Duration(config.PatternMetricsTTL),
+		PrefixDepth:              config.PrefixDepth,
+		MaxPrefixPointsPerSecond: config.MaxPrefixPointsPerSecond,
+	}
+}
+
+type rewriteRuleConfig struct {
+	// Regular expression to match metric name, e.g. "^OldTeam\\.(.*)$"
+	Match string `yaml:"match"`
+	// Replacement for matched metric name, can contain references to Match submatches, e.g. "NewTeam.$1"
+	Rename string `yaml:"rename"`
+	// Tags to add to matched metric
+	AddTags map[string]string `yaml:"add_tags"`
+	// Names of tags to remove from matched metric
+	DropTags []string `yaml:"drop_tags"`
+	// If true, matched metric will be dropped
+	Drop bool `yaml:"drop"`
+}
+
+func (config *rewriteRuleConfig) getSettings() filter.RewriteRule {
+	return filter.RewriteRule{
+		Match:    config.Match,
+		Rename:   config.Rename,
+		AddTags:  config.AddTags,
+		DropTags: config.DropTags,
+		Drop:     config.Drop,
+	}
+}
+
+type tlsConfig struct {
+	// If true, metrics listener will accept only TLS connections.
+	Enabled bool `yaml:"enabled"`
+	// Path to server certificate in PEM format
+	CertFile string `yaml:"cert_file"`
+	// Path to server certificate private key in PEM format
+	KeyFile string `yaml:"key_file"`
+	// Path to CA certificates in PEM format. If set, clients must present certificate signed by one of these CAs.
+	ClientCAFile string `yaml:"client_ca_file"`
+	// Metric prefixes allowed for clients by certificate common name, format: {common_name: [prefix1, prefix2]}.
+	// Requires client_ca_file. Prefix matches whole metric name nodes, e.g. "Team.service" allows "Team.service.rps"
+	// but not "Team.services.rps". Metrics outside of allowed prefixes are dropped. Clients which are not listed can't send any metrics.
+	AllowedPrefixes map[string][]string `yaml:"allowed_prefixes"`
+}
+
+func (config *tlsConfig) getSettings() filter.TLSConfig {
+	return filter.TLSConfig{
+		Enabled:         config.Enabled,
+		CertFile:        config.CertFile,
+		KeyFile:         config.KeyFile,
+		ClientCAFile:    config.ClientCAFile,
+		AllowedPrefixes: config.AllowedPrefixes,
+	}
+}
+
+type statsdConfig struct {
+	// StatsD UDP listener uri. StatsD listener is disabled if empty.
+	// Aggregated values are saved with metric type prefix: "counters.<name>.count", "counters.<name>.rate",
+	// "gauges.<name>", "timers.<name>.<aggregation>" and "sets.<name>.count".
+	Listen string `yaml:"listen"`
+	// Period to aggregate StatsD metrics for. Aggregated values are matched and saved once per period.
+	FlushInterval string `yaml:"flush_interval"`
+	// Percentiles to calculate for timers, e.g. [90, 99.9]
+	Percentiles []float64 `yaml:"percentiles"`
+}
+
+type remoteWriteConfig struct {
+	// If true, filter will accept metrics via Prometheus remote write protocol.
+	Enabled bool `yaml:"enabled"`
+	// Remote write HTTP listener uri
+	Listen string `yaml:"listen"`
+	// URL path Prometheus sends remote write requests to
+	Path string `yaml:"path"`
 }
 
 func getDefault() config {
@@ -42,11 +190,36 @@ func getDefault() config {
 			LogPrettyFormat: false,
 		},
 		Filter: filterConfig{
-			Listen:               ":2003",
-			RetentionConfig:      "/etc/moira/storage-schemas.conf",
-			CacheCapacity:        10, //nolint
-			MaxParallelMatches:   0,
-			PatternsUpdatePeriod: "1s",
+			Listen:                    ":2003",
+			UDPListen:                 "",
+			PickleListen:              "",
+			InfluxListen:              "",
+			RetentionConfig:           "/etc/moira/storage-schemas.conf",
+			CacheCapacity:             10, //nolint
+			MaxParallelMatches:        0,
+			PatternsUpdatePeriod:      "1s",
+			MetricTapSamplesPerSecond: 10, //nolint
+			Limits: limitsConfig{
+				MaxPatternMetrics:        0,
+				PatternMetricsTTL:        "1h",
+				PrefixDepth:              1,
+				MaxPrefixPointsPerSecond: 0,
+			},
+			TimestampPolicy: timestampPolicyConfig{
+				MaxPast:   "",
+				MaxFuture: "",
+				Action:    "drop",
+			},
+			PrometheusRemoteWrite: remoteWriteConfig{
+				Enabled: false,
+				Listen:  ":9201",
+				Path:    "/api/v1/write",
+			},
+			StatsD: statsdConfig{
+				Listen:        "",
+				FlushInterval: "10s",
+				Percentiles:   []float64{90}, //nolint
+			},
 		},
 		Telemetry: cmd.TelemetryConfig{
 			Listen: ":8094",
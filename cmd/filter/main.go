@@ -15,7 +15,9 @@ import (
 	"github.com/moira-alert/moira/filter/connection"
 	"github.com/moira-alert/moira/filter/heartbeat"
 	matchedmetrics "github.com/moira-alert/moira/filter/matched_metrics"
+	metrictap "github.com/moira-alert/moira/filter/metric_tap"
 	"github.com/moira-alert/moira/filter/patterns"
+	"github.com/moira-alert/moira/filter/statsd"
 	logging "github.com/moira-alert/moira/logging/zerolog_adapter"
 	"github.com/moira-alert/moira/metrics"
 	"github.com/xiam/to"
@@ -90,9 +92,10 @@ func main() {
 		logger.Fatalf("Failed to initialize cache storage with config [%s]: %s", config.Filter.RetentionConfig, err.Error())
 	}
 
-	patternStorage, err := filter.NewPatternStorage(database, filterMetrics, logger)
+	filterSettings := config.Filter.getSettings()
+	patternStorage, err := filter.NewPatternStorage(filterSettings, database, filterMetrics, logger)
 	if err != nil {
-		logger.Fatalf("Failed to refresh pattern storage: %s", err.Error())
+		logger.Fatalf("Failed to initialize pattern storage: %s", err.Error())
 	}
 
 	// Refresh Patterns on first init
@@ -111,7 +114,12 @@ func main() {
 	defer stopHeartbeatWorker(heartbeatWorker)
 
 	// Start metrics listener
-	listener, err := connection.NewListener(config.Filter.Listen, logger, filterMetrics)
+	var listener *connection.MetricsListener
+	if filterSettings.TLS.Enabled {
+		listener, err = connection.NewTLSListener(filterSettings.Listen, filterSettings.TLS, logger, filterMetrics)
+	} else {
+		listener, err = connection.NewListener(filterSettings.Listen, logger, filterMetrics)
+	}
 	if err != nil {
 		logger.Fatalf("Failed to start listen: %s", err.Error())
 	}
@@ -120,13 +128,70 @@ func main() {
 	patternMatcher := patterns.NewMatcher(logger, filterMetrics, patternStorage)
 	metricsChan := patternMatcher.Start(config.Filter.MaxParallelMatches, lineChan)
 
+	// Start metric tap
+	metricTap := metrictap.NewTap(database, logger, config.Filter.MetricTapSamplesPerSecond)
+	metricTap.Start()
+	defer stopMetricTap(metricTap)
+
 	// Start metrics matcher
 	cacheCapacity := config.Filter.CacheCapacity
-	metricsMatcher := matchedmetrics.NewMetricsMatcher(filterMetrics, logger, database, cacheStorage, cacheCapacity)
+	metricsMatcher := matchedmetrics.NewMetricsMatcher(filterMetrics, logger, database, cacheStorage, metricTap, cacheCapacity)
 	metricsMatcher.Start(metricsChan)
 	defer metricsMatcher.Wait()  // First stop listener
 	defer stopListener(listener) // Then waiting for metrics matcher handle all received events
 
+	// Start UDP listener
+	if config.Filter.UDPListen != "" {
+		udpListener, err := connection.NewUDPListener(config.Filter.UDPListen, logger, filterMetrics)
+		if err != nil {
+			logger.Fatalf("Failed to start UDP listen: %s", err.Error())
+		}
+		udpListener.Listen(lineChan)
+		defer stopUDPListener(udpListener) // Stop UDP listener before closing lineChan
+	}
+
+	// Start pickle listener
+	if config.Filter.PickleListen != "" {
+		pickleListener, err := connection.NewPickleListener(config.Filter.PickleListen, logger, filterMetrics)
+		if err != nil {
+			logger.Fatalf("Failed to start pickle listen: %s", err.Error())
+		}
+		pickleListener.ListenTo(lineChan)
+		defer stopListener(pickleListener) // Stop pickle listener before closing lineChan
+	}
+
+	// Start InfluxDB line protocol listener
+	if config.Filter.InfluxListen != "" {
+		influxListener, err := connection.NewInfluxListener(config.Filter.InfluxListen, logger, filterMetrics)
+		if err != nil {
+			logger.Fatalf("Failed to start InfluxDB listen: %s", err.Error())
+		}
+		patternMatcher.StartParsed(config.Filter.MaxParallelMatches, influxListener.ListenParsed())
+		defer stopListener(influxListener) // Stop InfluxDB listener before closing lineChan
+	}
+
+	// Start StatsD listener
+	if config.Filter.StatsD.Listen != "" {
+		statsdListener, err := statsd.NewListener(config.Filter.StatsD.Listen, to.Duration(config.Filter.StatsD.FlushInterval),
+			config.Filter.StatsD.Percentiles, logger, filterMetrics)
+		if err != nil {
+			logger.Fatalf("Failed to start StatsD listen: %s", err.Error())
+		}
+		statsdListener.Listen(lineChan)
+		defer stopStatsDListener(statsdListener) // Stop StatsD listener before closing lineChan
+	}
+
+	// Start Prometheus remote write listener
+	if config.Filter.PrometheusRemoteWrite.Enabled {
+		remoteWriteListener, err := connection.NewRemoteWriteListener(config.Filter.PrometheusRemoteWrite.Listen,
+			config.Filter.PrometheusRemoteWrite.Path, logger, filterMetrics)
+		if err != nil {
+			logger.Fatalf("Failed to start remote write listener: %s", err.Error())
+		}
+		patternMatcher.StartParsed(config.Filter.MaxParallelMatches, remoteWriteListener.Listen())
+		defer stopRemoteWriteListener(remoteWriteListener) // Stop remote write listener before closing lineChan
+	}
+
 	logger.Infof("Moira Filter started. Version: %s", MoiraVersion)
 	ch := make(chan os.Signal, 1)
 	signal.Notify(ch, syscall.SIGINT, syscall.SIGTERM)
@@ -140,6 +205,30 @@ func stopListener(listener *connection.MetricsListener) {
 	}
 }
 
+func stopUDPListener(listener *connection.UDPListener) {
+	if err := listener.Stop(); err != nil {
+		logger.Errorf("Failed to stop UDP listener: %v", err)
+	}
+}
+
+func stopStatsDListener(listener *statsd.Listener) {
+	if err := listener.Stop(); err != nil {
+		logger.Errorf("Failed to stop StatsD listener: %v", err)
+	}
+}
+
+func stopRemoteWriteListener(listener *connection.RemoteWriteListener) {
+	if err := listener.Stop(); err != nil {
+		logger.Errorf("Failed to stop remote write listener: %v", err)
+	}
+}
+
+func stopMetricTap(tap *metrictap.Tap) {
+	if err := tap.Stop(); err != nil {
+		logger.Errorf("Failed to stop metric tap: %v", err)
+	}
+}
+
 func stopHeartbeatWorker(heartbeatWorker *heartbeat.Worker) {
 	if err := heartbeatWorker.Stop(); err != nil {
 		logger.Errorf("Failed to stop heartbeat worker: %v", err)
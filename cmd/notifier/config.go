@@ -13,12 +13,14 @@ import (
 )
 
 type config struct {
-	Redis       cmd.RedisConfig      `yaml:"redis"`
-	Logger      cmd.LoggerConfig     `yaml:"log"`
-	Notifier    notifierConfig       `yaml:"notifier"`
-	Telemetry   cmd.TelemetryConfig  `yaml:"telemetry"`
-	Remote      cmd.RemoteConfig     `yaml:"remote"`
-	ImageStores cmd.ImageStoreConfig `yaml:"image_store"`
+	Redis          cmd.RedisConfig           `yaml:"redis"`
+	Logger         cmd.LoggerConfig          `yaml:"log"`
+	Notifier       notifierConfig            `yaml:"notifier"`
+	Telemetry      cmd.TelemetryConfig       `yaml:"telemetry"`
+	Remote         cmd.RemoteConfig          `yaml:"remote"`
+	Prometheus     cmd.PrometheusConfig      `yaml:"prometheus"`
+	RemoteClusters []cmd.RemoteClusterConfig `yaml:"remote_clusters"`
+	ImageStores    cmd.ImageStoreConfig      `yaml:"image_store"`
 }
 
 type entityLogConfig struct {
@@ -114,6 +116,11 @@ func getDefault() config {
 			Timeout:    "60s",
 			MetricsTTL: "24h",
 		},
+		Prometheus: cmd.PrometheusConfig{
+			MetricsTTL: "7d",
+			Timeout:    "60s",
+			Step:       "60s",
+		},
 		ImageStores: cmd.ImageStoreConfig{},
 	}
 }
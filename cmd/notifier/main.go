@@ -9,6 +9,7 @@ import (
 
 	metricSource "github.com/moira-alert/moira/metric_source"
 	"github.com/moira-alert/moira/metric_source/local"
+	"github.com/moira-alert/moira/metric_source/prometheus"
 	"github.com/moira-alert/moira/metric_source/remote"
 
 	"github.com/moira-alert/moira"
@@ -80,7 +81,22 @@ func main() {
 	localSource := local.Create(database)
 	remoteConfig := config.Remote.GetRemoteSourceSettings()
 	remoteSource := remote.Create(remoteConfig)
-	metricSourceProvider := metricSource.CreateMetricSourceProvider(localSource, remoteSource)
+	if _, err := remoteSource.IsConfigured(); err != nil && err != remote.ErrRemoteStorageDisabled {
+		logger.Fatalf("Can not configure remote: %s", err.Error())
+	}
+	prometheusSource := prometheus.Create(config.Prometheus.GetPrometheusSourceSettings())
+	metricSourceProvider := metricSource.CreateMetricSourceProvider(localSource, remoteSource, prometheusSource)
+	remoteClustersConfig, err := cmd.GetRemoteClustersSettings(config.RemoteClusters, config.Remote)
+	if err != nil {
+		logger.Fatalf("Can not configure remote clusters: %s", err.Error())
+	}
+	for clusterName, clusterConfig := range remoteClustersConfig {
+		clusterSource := remote.Create(clusterConfig)
+		if _, err := clusterSource.IsConfigured(); err != nil && err != remote.ErrRemoteStorageDisabled {
+			logger.Fatalf("Can not configure remote cluster %s: %s", clusterName, err.Error())
+		}
+		metricSourceProvider.RegisterRemoteCluster(clusterName, clusterSource)
+	}
 
 	// Initialize the image store
 	imageStoreMap := cmd.InitImageStores(config.ImageStores, logger)
@@ -95,10 +111,12 @@ func main() {
 	}
 
 	// Start moira self state checker
+	selfStateConfig := config.Notifier.SelfState.getSettings()
+	selfStateConfig.RemoteClusters = metricSourceProvider.GetRemoteClusterNames()
 	selfState := &selfstate.SelfCheckWorker{
 		Logger:   logger,
 		Database: database,
-		Config:   config.Notifier.SelfState.getSettings(),
+		Config:   selfStateConfig,
 		Notifier: sender,
 	}
 	if err := selfState.Start(); err != nil {
@@ -0,0 +1,56 @@
+package redis
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// UpdateCheckerInstanceHeartbeat registers checker instance or updates time it was alive last time
+func (connector *DbConnector) UpdateCheckerInstanceHeartbeat(instanceID string) error {
+	c := connector.pool.Get()
+	defer c.Close()
+	if _, err := c.Do("ZADD", checkerInstancesKey, time.Now().Unix(), instanceID); err != nil {
+		return fmt.Errorf("failed to update checker instance %s heartbeat: %s", instanceID, err.Error())
+	}
+	return nil
+}
+
+// GetCheckerInstances removes checker instances which were not alive since given timestamp and returns IDs of others
+func (connector *DbConnector) GetCheckerInstances(aliveSince int64) ([]string, error) {
+	c := connector.pool.Get()
+	defer c.Close()
+	c.Send("MULTI")                                                                         //nolint
+	c.Send("ZREMRANGEBYSCORE", checkerInstancesKey, "-inf", fmt.Sprintf("(%d", aliveSince)) //nolint
+	c.Send("ZRANGE", checkerInstancesKey, 0, -1)                                            //nolint
+	rawResponse, err := redis.Values(c.Do("EXEC"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get checker instances: %s", err.Error())
+	}
+	instanceIDs, err := redis.Strings(rawResponse[1], nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get checker instances: %s", err.Error())
+	}
+	return instanceIDs, nil
+}
+
+// RemoveCheckerInstance removes checker instance from registry with its triggers to check including triggers
+// of given remote graphite clusters, so its shard is rebalanced between other instances
+func (connector *DbConnector) RemoveCheckerInstance(instanceID string, clusterNames []string) error {
+	c := connector.pool.Get()
+	defer c.Close()
+	c.Send("MULTI")                                                              //nolint
+	c.Send("ZREM", checkerInstancesKey, instanceID)                              //nolint
+	c.Send("DEL", shardTriggersToCheckKey(localTriggersToCheckKey, instanceID))  //nolint
+	c.Send("DEL", shardTriggersToCheckKey(remoteTriggersToCheckKey, instanceID)) //nolint
+	for _, clusterName := range clusterNames {
+		c.Send("DEL", remoteClusterTriggersToCheckKey(clusterName, instanceID)) //nolint
+	}
+	if _, err := c.Do("EXEC"); err != nil {
+		return fmt.Errorf("failed to remove checker instance %s: %s", instanceID, err.Error())
+	}
+	return nil
+}
+
+var checkerInstancesKey = "moira-checker-instances"
@@ -0,0 +1,111 @@
+package redis
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	logging "github.com/moira-alert/moira/logging/zerolog_adapter"
+)
+
+func TestCheckerInstances(t *testing.T) {
+	logger, _ := logging.ConfigureLog("stdout", "info", "test", true)
+	dataBase := newTestDatabase(logger, config)
+	dataBase.flush()
+	defer dataBase.flush()
+	Convey("Checker instances registry", t, func() {
+		instances, err := dataBase.GetCheckerInstances(0)
+		So(err, ShouldBeNil)
+		So(instances, ShouldBeEmpty)
+
+		err = dataBase.UpdateCheckerInstanceHeartbeat("checker-1")
+		So(err, ShouldBeNil)
+		err = dataBase.UpdateCheckerInstanceHeartbeat("checker-2")
+		So(err, ShouldBeNil)
+
+		instances, err = dataBase.GetCheckerInstances(time.Now().Unix() - 60)
+		So(err, ShouldBeNil)
+		So(instances, ShouldHaveLength, 2)
+		So(instances, ShouldContain, "checker-1")
+		So(instances, ShouldContain, "checker-2")
+
+		Convey("Shard triggers to check are separated", func() {
+			err = dataBase.AddLocalShardTriggersToCheck("checker-1", []string{"trigger-1", "trigger-2"})
+			So(err, ShouldBeNil)
+			err = dataBase.AddRemoteShardTriggersToCheck("checker-1", []string{"trigger-3"})
+			So(err, ShouldBeNil)
+			err = dataBase.AddRemoteClusterTriggersToCheck("eu", "checker-1", []string{"trigger-4"})
+			So(err, ShouldBeNil)
+			err = dataBase.AddRemoteClusterTriggersToCheck("eu", "", []string{"trigger-5"})
+			So(err, ShouldBeNil)
+			count, err := dataBase.GetRemoteClusterTriggersToCheckTotalCount("eu")
+			So(err, ShouldBeNil)
+			So(count, ShouldEqual, 2)
+
+			count, err = dataBase.GetLocalShardTriggersToCheckCount("checker-1")
+			So(err, ShouldBeNil)
+			So(count, ShouldEqual, 2)
+			count, err = dataBase.GetLocalShardTriggersToCheckCount("checker-2")
+			So(err, ShouldBeNil)
+			So(count, ShouldEqual, 0)
+			count, err = dataBase.GetLocalTriggersToCheckCount()
+			So(err, ShouldBeNil)
+			So(count, ShouldEqual, 0)
+			count, err = dataBase.GetLocalTriggersToCheckTotalCount()
+			So(err, ShouldBeNil)
+			So(count, ShouldEqual, 2)
+
+			triggerIDs, err := dataBase.GetRemoteShardTriggersToCheck("checker-1", 2)
+			So(err, ShouldBeNil)
+			So(triggerIDs, ShouldResemble, []string{"trigger-3"})
+			count, err = dataBase.GetRemoteShardTriggersToCheckCount("checker-1")
+			So(err, ShouldBeNil)
+			So(count, ShouldEqual, 0)
+
+			Convey("and removed with instance", func() {
+				err = dataBase.RemoveCheckerInstance("checker-1", []string{"eu"})
+				So(err, ShouldBeNil)
+
+				count, err = dataBase.GetLocalShardTriggersToCheckCount("checker-1")
+				So(err, ShouldBeNil)
+				So(count, ShouldEqual, 0)
+				count, err = dataBase.GetRemoteClusterTriggersToCheckCount("eu", "checker-1")
+				So(err, ShouldBeNil)
+				So(count, ShouldEqual, 0)
+
+				instances, err = dataBase.GetCheckerInstances(time.Now().Unix() - 60)
+				So(err, ShouldBeNil)
+				So(instances, ShouldResemble, []string{"checker-2"})
+			})
+		})
+
+		Convey("Not alive instances are removed", func() {
+			instances, err = dataBase.GetCheckerInstances(time.Now().Unix() + 60)
+			So(err, ShouldBeNil)
+			So(instances, ShouldBeEmpty)
+
+			instances, err = dataBase.GetCheckerInstances(0)
+			So(err, ShouldBeNil)
+			So(instances, ShouldBeEmpty)
+		})
+	})
+}
+
+func TestCheckerInstancesConnection(t *testing.T) {
+	logger, _ := logging.ConfigureLog("stdout", "info", "test", true)
+	dataBase := newTestDatabase(logger, emptyConfig)
+	dataBase.flush()
+	defer dataBase.flush()
+	Convey("Should throw error when no connection", t, func() {
+		err := dataBase.UpdateCheckerInstanceHeartbeat("checker-1")
+		So(err, ShouldNotBeNil)
+
+		instances, err := dataBase.GetCheckerInstances(0)
+		So(instances, ShouldBeNil)
+		So(err, ShouldNotBeNil)
+
+		err = dataBase.RemoveCheckerInstance("checker-1", nil)
+		So(err, ShouldNotBeNil)
+	})
+}
@@ -203,7 +203,7 @@ func (connector *DbConnector) manageSubscriptions(tomb *tomb.Tomb, channel strin
 				}
 			case *net.OpError:
 				connector.logger.Infof("psc.Receive() returned *net.OpError: %s. Reconnecting...", n.Err.Error())
-				newPsc, err := connector.makePubSubConnection(metricEventKey)
+				newPsc, err := connector.makePubSubConnection(channel)
 				if err != nil {
 					connector.logger.Errorf("Failed to reconnect to subscription: %v", err)
 					<-time.After(receiveErrorSleepDuration)
@@ -0,0 +1,68 @@
+package redis
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+
+	"github.com/moira-alert/moira"
+)
+
+// SaveIngestionLimitViolations saves patterns and prefixes which exceeded filter ingestion limits
+func (connector *DbConnector) SaveIngestionLimitViolations(violations []*moira.IngestionLimitViolation) error {
+	if len(violations) == 0 {
+		return nil
+	}
+	c := connector.pool.Get()
+	defer c.Close()
+
+	c.Send("MULTI") //nolint
+	for _, violation := range violations {
+		violationBytes, err := json.Marshal(violation)
+		if err != nil {
+			return fmt.Errorf("failed to marshal ingestion limit violation: %s", err.Error())
+		}
+		c.Send("HSET", ingestionLimitViolationsKey, violation.GetKey(), violationBytes) //nolint
+	}
+	c.Send("EXPIRE", ingestionLimitViolationsKey, moira.IngestionLimitViolationTTL) //nolint
+	if _, err := c.Do("EXEC"); err != nil {
+		return fmt.Errorf("failed to EXEC: %s", err.Error())
+	}
+	return nil
+}
+
+// GetIngestionLimitViolations returns patterns and prefixes which exceeded filter ingestion limits.
+// Violations which were not reported during violation TTL are removed
+func (connector *DbConnector) GetIngestionLimitViolations() ([]*moira.IngestionLimitViolation, error) {
+	c := connector.pool.Get()
+	defer c.Close()
+
+	values, err := redis.StringMap(c.Do("HGETALL", ingestionLimitViolationsKey))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ingestion limit violations: %s", err.Error())
+	}
+	expiredBefore := time.Now().Unix() - moira.IngestionLimitViolationTTL
+	violations := make([]*moira.IngestionLimitViolation, 0, len(values))
+	expiredFields := make([]interface{}, 0)
+	for field, value := range values {
+		violation := &moira.IngestionLimitViolation{}
+		if err := json.Unmarshal([]byte(value), violation); err != nil {
+			return nil, fmt.Errorf("failed to parse ingestion limit violation json %s: %s", value, err.Error())
+		}
+		if violation.Timestamp < expiredBefore {
+			expiredFields = append(expiredFields, field)
+			continue
+		}
+		violations = append(violations, violation)
+	}
+	if len(expiredFields) > 0 {
+		if _, err := c.Do("HDEL", append([]interface{}{ingestionLimitViolationsKey}, expiredFields...)...); err != nil {
+			return nil, fmt.Errorf("failed to remove expired ingestion limit violations: %s", err.Error())
+		}
+	}
+	return violations, nil
+}
+
+var ingestionLimitViolationsKey = "moira-ingestion-limit-violations"
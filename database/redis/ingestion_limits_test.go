@@ -0,0 +1,73 @@
+package redis
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/moira-alert/moira"
+	logging "github.com/moira-alert/moira/logging/zerolog_adapter"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestIngestionLimitViolations(t *testing.T) {
+	logger, _ := logging.GetLogger("dataBase")
+	dataBase := newTestDatabase(logger, config)
+	dataBase.flush()
+	defer dataBase.flush()
+
+	now := time.Now().Unix()
+
+	Convey("Ingestion limit violations manipulation", t, func() {
+		violations, err := dataBase.GetIngestionLimitViolations()
+		So(err, ShouldBeNil)
+		So(violations, ShouldBeEmpty)
+
+		err = dataBase.SaveIngestionLimitViolations([]*moira.IngestionLimitViolation{
+			{Pattern: "my.pattern.*", Limit: 100, Dropped: 1, Timestamp: now - 10},
+			{Prefix: "my", Limit: 1000, Dropped: 5, Timestamp: now - 10},
+		})
+		So(err, ShouldBeNil)
+
+		err = dataBase.SaveIngestionLimitViolations([]*moira.IngestionLimitViolation{
+			{Pattern: "my.pattern.*", Limit: 100, Dropped: 10, Timestamp: now},
+		})
+		So(err, ShouldBeNil)
+
+		violations, err = dataBase.GetIngestionLimitViolations()
+		So(err, ShouldBeNil)
+		So(violations, ShouldHaveLength, 2)
+		violationsByField := make(map[string]moira.IngestionLimitViolation)
+		for _, violation := range violations {
+			violationsByField[violation.GetKey()] = *violation
+		}
+		So(violationsByField, ShouldResemble, map[string]moira.IngestionLimitViolation{
+			"pattern:my.pattern.*": {Pattern: "my.pattern.*", Limit: 100, Dropped: 10, Timestamp: now},
+			"prefix:my":            {Prefix: "my", Limit: 1000, Dropped: 5, Timestamp: now - 10},
+		})
+	})
+
+	Convey("Expired ingestion limit violations should be removed", t, func() {
+		err := dataBase.SaveIngestionLimitViolations([]*moira.IngestionLimitViolation{
+			{Prefix: "old", Limit: 1000, Dropped: 5, Timestamp: now - moira.IngestionLimitViolationTTL - 1},
+		})
+		So(err, ShouldBeNil)
+
+		violations, err := dataBase.GetIngestionLimitViolations()
+		So(err, ShouldBeNil)
+		So(violations, ShouldHaveLength, 2)
+		for _, violation := range violations {
+			So(violation.Prefix, ShouldNotEqual, "old")
+		}
+
+		c := dataBase.pool.Get()
+		defer c.Close()
+		exists, err := redis.Bool(c.Do("HEXISTS", ingestionLimitViolationsKey, "prefix:old"))
+		So(err, ShouldBeNil)
+		So(exists, ShouldBeFalse)
+	})
+
+	Convey("Saving empty violations should do nothing", t, func() {
+		So(dataBase.SaveIngestionLimitViolations(nil), ShouldBeNil)
+	})
+}
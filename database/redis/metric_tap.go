@@ -0,0 +1,90 @@
+package redis
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	"gopkg.in/tomb.v2"
+
+	"github.com/moira-alert/moira"
+)
+
+// AddMetricTap makes filter publish samples of metrics matched by pattern until expiration timestamp
+// Expiration of already tapped pattern is overwritten
+func (connector *DbConnector) AddMetricTap(pattern string, expiration int64) error {
+	c := connector.pool.Get()
+	defer c.Close()
+
+	if _, err := c.Do("ZADD", metricTapsKey, expiration, pattern); err != nil {
+		return fmt.Errorf("failed to ZADD metric tap, pattern: %s, error: %s", pattern, err.Error())
+	}
+	return nil
+}
+
+// GetMetricTaps returns tapped patterns which are not expired yet and removes expired ones
+func (connector *DbConnector) GetMetricTaps() ([]string, error) {
+	c := connector.pool.Get()
+	defer c.Close()
+
+	now := time.Now().Unix()
+	c.Send("MULTI")                                          //nolint
+	c.Send("ZREMRANGEBYSCORE", metricTapsKey, "-inf", now-1) //nolint
+	c.Send("ZRANGEBYSCORE", metricTapsKey, now, "+inf")      //nolint
+	rawResponse, err := redis.Values(c.Do("EXEC"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to EXEC: %s", err.Error())
+	}
+	patterns, err := redis.Strings(rawResponse[1], nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get metric taps: %s", err.Error())
+	}
+	return patterns, nil
+}
+
+// PublishMetricTapSamples sends samples of tapped patterns to their subscribers
+func (connector *DbConnector) PublishMetricTapSamples(samples []*moira.MetricTapSample) error {
+	if len(samples) == 0 {
+		return nil
+	}
+	c := connector.pool.Get()
+	defer c.Close()
+
+	for _, sample := range samples {
+		sampleBytes, err := json.Marshal(sample)
+		if err != nil {
+			return fmt.Errorf("failed to marshal metric tap sample: %s", err.Error())
+		}
+		c.Send("PUBLISH", metricTapChannel(sample.Pattern), sampleBytes) //nolint
+	}
+	return c.Flush()
+}
+
+// SubscribeMetricTap creates subscription for samples of metrics matched by tapped pattern
+func (connector *DbConnector) SubscribeMetricTap(tomb *tomb.Tomb, pattern string) (<-chan *moira.MetricTapSample, error) {
+	dataChannel, err := connector.manageSubscriptions(tomb, metricTapChannel(pattern))
+	if err != nil {
+		return nil, err
+	}
+
+	samplesChannel := make(chan *moira.MetricTapSample, pubSubWorkerChannelSize)
+	go func() {
+		defer close(samplesChannel)
+		for data := range dataChannel {
+			sample := &moira.MetricTapSample{}
+			if err := json.Unmarshal(data, sample); err != nil {
+				connector.logger.Errorf("Failed to parse MetricTapSample: %s, error : %v", string(data), err)
+				continue
+			}
+			samplesChannel <- sample
+		}
+	}()
+	return samplesChannel, nil
+}
+
+func metricTapChannel(pattern string) string {
+	return fmt.Sprintf("moira-metric-tap:%s", pattern)
+}
+
+var metricTapsKey = "moira-metric-taps"
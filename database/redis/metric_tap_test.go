@@ -0,0 +1,78 @@
+package redis
+
+import (
+	"testing"
+	"time"
+
+	"github.com/moira-alert/moira"
+	logging "github.com/moira-alert/moira/logging/zerolog_adapter"
+	. "github.com/smartystreets/goconvey/convey"
+	"gopkg.in/tomb.v2"
+)
+
+func TestMetricTaps(t *testing.T) {
+	logger, _ := logging.GetLogger("dataBase")
+	dataBase := newTestDatabase(logger, config)
+	dataBase.flush()
+	defer dataBase.flush()
+
+	Convey("Metric taps manipulation", t, func() {
+		patterns, err := dataBase.GetMetricTaps()
+		So(err, ShouldBeNil)
+		So(patterns, ShouldBeEmpty)
+
+		now := time.Now().Unix()
+		So(dataBase.AddMetricTap("my.pattern.*", now+60), ShouldBeNil)
+		So(dataBase.AddMetricTap("my.expired.*", now-60), ShouldBeNil)
+
+		patterns, err = dataBase.GetMetricTaps()
+		So(err, ShouldBeNil)
+		So(patterns, ShouldResemble, []string{"my.pattern.*"})
+
+		So(dataBase.AddMetricTap("my.pattern.*", now-1), ShouldBeNil)
+		patterns, err = dataBase.GetMetricTaps()
+		So(err, ShouldBeNil)
+		So(patterns, ShouldBeEmpty)
+	})
+
+	Convey("Metric tap samples should be received by subscriber of pattern", t, func() {
+		var subscriptionTomb tomb.Tomb
+		samples, err := dataBase.SubscribeMetricTap(&subscriptionTomb, "my.pattern.*")
+		So(err, ShouldBeNil)
+
+		sample := &moira.MetricTapSample{Pattern: "my.pattern.*", Metric: "my.pattern.one", Value: 1, Timestamp: 1234567890}
+		err = dataBase.PublishMetricTapSamples([]*moira.MetricTapSample{
+			{Pattern: "my.other.*", Metric: "my.other.one", Value: 2, Timestamp: 1234567890},
+			sample,
+		})
+		So(err, ShouldBeNil)
+		So(<-samples, ShouldResemble, sample)
+
+		subscriptionTomb.Kill(nil)
+		_, ok := <-samples
+		So(ok, ShouldBeFalse)
+	})
+}
+
+func TestMetricTapsErrorConnection(t *testing.T) {
+	logger, _ := logging.GetLogger("dataBase")
+	dataBase := newTestDatabase(logger, emptyConfig)
+	dataBase.flush()
+	defer dataBase.flush()
+
+	Convey("Should throw error when no connection", t, func() {
+		So(dataBase.AddMetricTap("my.pattern.*", 1234567890), ShouldNotBeNil)
+
+		patterns, err := dataBase.GetMetricTaps()
+		So(err, ShouldNotBeNil)
+		So(patterns, ShouldBeNil)
+
+		err = dataBase.PublishMetricTapSamples([]*moira.MetricTapSample{{Pattern: "my.pattern.*"}})
+		So(err, ShouldNotBeNil)
+
+		var subscriptionTomb tomb.Tomb
+		samples, err := dataBase.SubscribeMetricTap(&subscriptionTomb, "my.pattern.*")
+		So(err, ShouldNotBeNil)
+		So(samples, ShouldBeNil)
+	})
+}
@@ -0,0 +1,68 @@
+package redis
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+
+	"github.com/moira-alert/moira"
+)
+
+// SaveRejectedTimestampSamples saves last points received by filter with timestamps out of acceptance window
+func (connector *DbConnector) SaveRejectedTimestampSamples(samples []*moira.RejectedTimestampSample) error {
+	if len(samples) == 0 {
+		return nil
+	}
+	c := connector.pool.Get()
+	defer c.Close()
+
+	c.Send("MULTI") //nolint
+	for _, sample := range samples {
+		sampleBytes, err := json.Marshal(sample)
+		if err != nil {
+			return fmt.Errorf("failed to marshal rejected timestamp sample: %s", err.Error())
+		}
+		c.Send("HSET", rejectedTimestampSamplesKey, sample.Reason, sampleBytes) //nolint
+	}
+	c.Send("EXPIRE", rejectedTimestampSamplesKey, moira.RejectedTimestampSampleTTL) //nolint
+	if _, err := c.Do("EXEC"); err != nil {
+		return fmt.Errorf("failed to EXEC: %s", err.Error())
+	}
+	return nil
+}
+
+// GetRejectedTimestampSamples returns last points received by filter with timestamps out of acceptance window.
+// Samples which were not reported during sample TTL are removed
+func (connector *DbConnector) GetRejectedTimestampSamples() ([]*moira.RejectedTimestampSample, error) {
+	c := connector.pool.Get()
+	defer c.Close()
+
+	values, err := redis.StringMap(c.Do("HGETALL", rejectedTimestampSamplesKey))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rejected timestamp samples: %s", err.Error())
+	}
+	expiredBefore := time.Now().Unix() - moira.RejectedTimestampSampleTTL
+	samples := make([]*moira.RejectedTimestampSample, 0, len(values))
+	expiredFields := make([]interface{}, 0)
+	for field, value := range values {
+		sample := &moira.RejectedTimestampSample{}
+		if err := json.Unmarshal([]byte(value), sample); err != nil {
+			return nil, fmt.Errorf("failed to parse rejected timestamp sample json %s: %s", value, err.Error())
+		}
+		if sample.Timestamp < expiredBefore {
+			expiredFields = append(expiredFields, field)
+			continue
+		}
+		samples = append(samples, sample)
+	}
+	if len(expiredFields) > 0 {
+		if _, err := c.Do("HDEL", append([]interface{}{rejectedTimestampSamplesKey}, expiredFields...)...); err != nil {
+			return nil, fmt.Errorf("failed to remove expired rejected timestamp samples: %s", err.Error())
+		}
+	}
+	return samples, nil
+}
+
+var rejectedTimestampSamplesKey = "moira-rejected-timestamp-samples"
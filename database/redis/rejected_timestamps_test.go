@@ -0,0 +1,63 @@
+package redis
+
+import (
+	"testing"
+	"time"
+
+	"github.com/moira-alert/moira"
+	logging "github.com/moira-alert/moira/logging/zerolog_adapter"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestRejectedTimestampSamples(t *testing.T) {
+	logger, _ := logging.GetLogger("dataBase")
+	dataBase := newTestDatabase(logger, config)
+	dataBase.flush()
+	defer dataBase.flush()
+
+	now := time.Now().Unix()
+
+	Convey("Rejected timestamp samples manipulation", t, func() {
+		samples, err := dataBase.GetRejectedTimestampSamples()
+		So(err, ShouldBeNil)
+		So(samples, ShouldBeEmpty)
+
+		err = dataBase.SaveRejectedTimestampSamples([]*moira.RejectedTimestampSample{
+			{Reason: "outdated", Action: "drop", Sample: "my.metric 1 100", Count: 1, Timestamp: now - 10},
+			{Reason: "future", Action: "drop", Sample: "my.metric 1 9999999999", Count: 2, Timestamp: now - 10},
+		})
+		So(err, ShouldBeNil)
+
+		err = dataBase.SaveRejectedTimestampSamples([]*moira.RejectedTimestampSample{
+			{Reason: "outdated", Action: "drop", Sample: "my.metric 2 200", Count: 5, Timestamp: now},
+		})
+		So(err, ShouldBeNil)
+
+		samples, err = dataBase.GetRejectedTimestampSamples()
+		So(err, ShouldBeNil)
+		samplesByReason := make(map[string]moira.RejectedTimestampSample)
+		for _, sample := range samples {
+			samplesByReason[sample.Reason] = *sample
+		}
+		So(samplesByReason, ShouldResemble, map[string]moira.RejectedTimestampSample{
+			"outdated": {Reason: "outdated", Action: "drop", Sample: "my.metric 2 200", Count: 5, Timestamp: now},
+			"future":   {Reason: "future", Action: "drop", Sample: "my.metric 1 9999999999", Count: 2, Timestamp: now - 10},
+		})
+	})
+
+	Convey("Expired rejected timestamp samples should be removed", t, func() {
+		err := dataBase.SaveRejectedTimestampSamples([]*moira.RejectedTimestampSample{
+			{Reason: "future", Action: "drop", Sample: "my.metric 1 9999999999", Count: 2, Timestamp: now - moira.RejectedTimestampSampleTTL - 1},
+		})
+		So(err, ShouldBeNil)
+
+		samples, err := dataBase.GetRejectedTimestampSamples()
+		So(err, ShouldBeNil)
+		So(samples, ShouldHaveLength, 1)
+		So(samples[0].Reason, ShouldEqual, "outdated")
+	})
+
+	Convey("Saving empty samples should do nothing", t, func() {
+		So(dataBase.SaveRejectedTimestampSamples(nil), ShouldBeNil)
+	})
+}
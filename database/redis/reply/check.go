@@ -26,6 +26,7 @@ type checkDataStorageElement struct {
 	LastSuccessfulCheckTimestamp int64                        `json:"last_successful_check_timestamp"`
 	Suppressed                   bool                         `json:"suppressed,omitempty"`
 	SuppressedState              moira.State                  `json:"suppressed_state,omitempty"`
+	SuppressedReason             string                       `json:"suppressed_reason,omitempty"`
 	Message                      string                       `json:"msg,omitempty"`
 }
 
@@ -52,6 +53,7 @@ func toCheckDataStorageElement(check moira.CheckData) checkDataStorageElement {
 		LastSuccessfulCheckTimestamp: check.LastSuccessfulCheckTimestamp,
 		Suppressed:                   check.Suppressed,
 		SuppressedState:              check.SuppressedState,
+		SuppressedReason:             check.SuppressedReason,
 		Message:                      check.Message,
 	}
 }
@@ -84,6 +86,7 @@ func (d checkDataStorageElement) toCheckData() moira.CheckData {
 		LastSuccessfulCheckTimestamp: d.LastSuccessfulCheckTimestamp,
 		Suppressed:                   d.Suppressed,
 		SuppressedState:              d.SuppressedState,
+		SuppressedReason:             d.SuppressedReason,
 		Message:                      d.Message,
 	}
 }
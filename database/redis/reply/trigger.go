@@ -12,23 +12,31 @@ import (
 
 // Duty hack for moira.Trigger TTL int64 and stored trigger TTL string compatibility
 type triggerStorageElement struct {
-	ID               string              `json:"id"`
-	Name             string              `json:"name"`
-	Desc             *string             `json:"desc,omitempty"`
-	Targets          []string            `json:"targets"`
-	WarnValue        *float64            `json:"warn_value"`
-	ErrorValue       *float64            `json:"error_value"`
-	TriggerType      string              `json:"trigger_type,omitempty"`
-	Tags             []string            `json:"tags"`
-	TTLState         *moira.TTLState     `json:"ttl_state,omitempty"`
-	Schedule         *moira.ScheduleData `json:"sched,omitempty"`
-	Expression       *string             `json:"expr,omitempty"`
-	PythonExpression *string             `json:"expression,omitempty"`
-	Patterns         []string            `json:"patterns"`
-	TTL              string              `json:"ttl,omitempty"`
-	IsRemote         bool                `json:"is_remote"`
-	MuteNewMetrics   bool                `json:"mute_new_metrics,omitempty"`
-	AloneMetrics     map[string]bool     `json:"alone_metrics"`
+	ID               string                    `json:"id"`
+	Name             string                    `json:"name"`
+	Desc             *string                   `json:"desc,omitempty"`
+	Targets          []string                  `json:"targets"`
+	WarnValue        *float64                  `json:"warn_value"`
+	ErrorValue       *float64                  `json:"error_value"`
+	TriggerType      string                    `json:"trigger_type,omitempty"`
+	Tags             []string                  `json:"tags"`
+	TTLState         *moira.TTLState           `json:"ttl_state,omitempty"`
+	Schedule         *moira.ScheduleData       `json:"sched,omitempty"`
+	Expression       *string                   `json:"expr,omitempty"`
+	PythonExpression *string                   `json:"expression,omitempty"`
+	Patterns         []string                  `json:"patterns"`
+	TTL              string                    `json:"ttl,omitempty"`
+	IsRemote         bool                      `json:"is_remote"`
+	TriggerSource    moira.TriggerSource       `json:"trigger_source,omitempty"`
+	ClusterName      string                    `json:"cluster_name,omitempty"`
+	MuteNewMetrics   bool                      `json:"mute_new_metrics,omitempty"`
+	AloneMetrics     map[string]bool           `json:"alone_metrics"`
+	Parents          []string                  `json:"parents,omitempty"`
+	Anomaly          *moira.AnomalyParams      `json:"anomaly,omitempty"`
+	Forecast         *moira.ForecastParams     `json:"forecast,omitempty"`
+	Pending          *moira.PendingParams      `json:"pending,omitempty"`
+	Flapping         *moira.FlappingParams     `json:"flapping,omitempty"`
+	Overrides        []moira.ThresholdOverride `json:"overrides,omitempty"`
 }
 
 func (storageElement *triggerStorageElement) toTrigger() moira.Trigger {
@@ -58,8 +66,16 @@ func (storageElement *triggerStorageElement) toTrigger() moira.Trigger {
 		Patterns:         storageElement.Patterns,
 		TTL:              getTriggerTTL(storageElement.TTL),
 		IsRemote:         storageElement.IsRemote,
+		TriggerSource:    storageElement.TriggerSource,
+		ClusterName:      storageElement.ClusterName,
 		MuteNewMetrics:   storageElement.MuteNewMetrics,
 		AloneMetrics:     storageElement.AloneMetrics,
+		Parents:          storageElement.Parents,
+		Anomaly:          storageElement.Anomaly,
+		Forecast:         storageElement.Forecast,
+		Pending:          storageElement.Pending,
+		Flapping:         storageElement.Flapping,
+		Overrides:        storageElement.Overrides,
 	}
 }
 
@@ -80,8 +96,16 @@ func toTriggerStorageElement(trigger *moira.Trigger, triggerID string) *triggerS
 		Patterns:         trigger.Patterns,
 		TTL:              getTriggerTTLString(trigger.TTL),
 		IsRemote:         trigger.IsRemote,
+		TriggerSource:    trigger.TriggerSource,
+		ClusterName:      trigger.ClusterName,
 		MuteNewMetrics:   trigger.MuteNewMetrics,
 		AloneMetrics:     trigger.AloneMetrics,
+		Parents:          trigger.Parents,
+		Anomaly:          trigger.Anomaly,
+		Forecast:         trigger.Forecast,
+		Pending:          trigger.Pending,
+		Flapping:         trigger.Flapping,
+		Overrides:        trigger.Overrides,
 	}
 }
 
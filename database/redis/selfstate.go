@@ -46,6 +46,25 @@ func (connector *DbConnector) GetRemoteChecksUpdatesCount() (int64, error) {
 	return ts, err
 }
 
+// UpdateRemoteClusterChecksHeartbeat increments redis counter of checks of remote graphite cluster triggers
+func (connector *DbConnector) UpdateRemoteClusterChecksHeartbeat(clusterName string) error {
+	c := connector.pool.Get()
+	defer c.Close()
+	err := c.Send("INCR", selfStateRemoteClusterChecksCounterKey(clusterName))
+	return err
+}
+
+// GetRemoteClusterChecksUpdatesCount return checks count of remote graphite cluster triggers by Moira-Checker
+func (connector *DbConnector) GetRemoteClusterChecksUpdatesCount(clusterName string) (int64, error) {
+	c := connector.pool.Get()
+	defer c.Close()
+	ts, err := redis.Int64(c.Do("GET", selfStateRemoteClusterChecksCounterKey(clusterName)))
+	if err == redis.ErrNil {
+		return 0, nil
+	}
+	return ts, err
+}
+
 // GetNotifierState return current notifier state: <OK|ERROR>
 func (connector *DbConnector) GetNotifierState() (string, error) {
 	c := connector.pool.Get()
@@ -72,3 +91,7 @@ var selfStateMetricsHeartbeatKey = "moira-selfstate:metrics-heartbeat"
 var selfStateChecksCounterKey = "moira-selfstate:checks-counter"
 var selfStateRemoteChecksCounterKey = "moira-selfstate:remote-checks-counter"
 var selfStateNotifierHealth = "moira-selfstate:notifier-health"
+
+func selfStateRemoteClusterChecksCounterKey(clusterName string) string {
+	return "moira-selfstate:remote-cluster-checks-counter:" + clusterName
+}
@@ -44,6 +44,17 @@ func (connector *DbConnector) GetRemoteTriggerIDs() ([]string, error) {
 	return triggerIds, nil
 }
 
+// GetRemoteClusterTriggerIDs gets moira triggerIDs bound to given remote graphite cluster
+func (connector *DbConnector) GetRemoteClusterTriggerIDs(clusterName string) ([]string, error) {
+	c := connector.pool.Get()
+	defer c.Close()
+	triggerIds, err := redis.Strings(c.Do("SMEMBERS", remoteClusterTriggersListKey(clusterName)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get remote cluster %s triggers-list: %s", clusterName, err.Error())
+	}
+	return triggerIds, nil
+}
+
 // GetTrigger gets trigger and trigger tags by given ID and return it in merged object
 func (connector *DbConnector) GetTrigger(triggerID string) (moira.Trigger, error) {
 	c := connector.pool.Get()
@@ -91,6 +102,18 @@ func (connector *DbConnector) GetTriggers(triggerIDs []string) ([]*moira.Trigger
 	return triggers, nil
 }
 
+// GetChildTriggerIDs gets ids of triggers which declare given trigger as parent
+func (connector *DbConnector) GetChildTriggerIDs(triggerID string) ([]string, error) {
+	c := connector.pool.Get()
+	defer c.Close()
+
+	triggerIds, err := redis.Strings(c.Do("SMEMBERS", triggerChildrenKey(triggerID)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve child triggers for trigger: %s, error: %s", triggerID, err.Error())
+	}
+	return triggerIds, nil
+}
+
 // GetPatternTriggerIDs gets trigger list by given pattern
 func (connector *DbConnector) GetPatternTriggerIDs(pattern string) ([]string, error) {
 	c := connector.pool.Get()
@@ -172,16 +195,26 @@ func (connector *DbConnector) updateTrigger(triggerID string, newTrigger *moira.
 		if oldTrigger.IsRemote && !newTrigger.IsRemote {
 			c.Send("SREM", remoteTriggersListKey, triggerID) //nolint
 		}
+		if oldTrigger.ClusterName != "" && oldTrigger.ClusterName != newTrigger.ClusterName {
+			c.Send("SREM", remoteClusterTriggersListKey(oldTrigger.ClusterName), triggerID) //nolint
+		}
 
 		for _, tag := range moira.GetStringListsDiff(oldTrigger.Tags, newTrigger.Tags) {
 			c.Send("SREM", triggerTagsKey(triggerID), tag) //nolint
 			c.Send("SREM", tagTriggersKey(tag), triggerID) //nolint
 		}
+
+		for _, parentID := range moira.GetStringListsDiff(oldTrigger.Parents, newTrigger.Parents) {
+			c.Send("SREM", triggerChildrenKey(parentID), triggerID) //nolint
+		}
 	}
 	c.Send("SET", triggerKey(triggerID), bytes) //nolint
 	c.Send("SADD", triggersListKey, triggerID) //nolint
 	if newTrigger.IsRemote {
 		c.Send("SADD", remoteTriggersListKey, triggerID) //nolint
+		if newTrigger.ClusterName != "" {
+			c.Send("SADD", remoteClusterTriggersListKey(newTrigger.ClusterName), triggerID) //nolint
+		}
 	} else {
 		for _, pattern := range newTrigger.Patterns {
 			c.Send("SADD", patternsListKey, pattern) //nolint
@@ -193,6 +226,9 @@ func (connector *DbConnector) updateTrigger(triggerID string, newTrigger *moira.
 		c.Send("SADD", tagTriggersKey(tag), triggerID) //nolint
 		c.Send("SADD", tagsKey, tag) //nolint
 	}
+	for _, parentID := range newTrigger.Parents {
+		c.Send("SADD", triggerChildrenKey(parentID), triggerID) //nolint
+	}
 	if connector.source != Cli {
 		c.Send("ZADD", triggersToReindexKey, time.Now().Unix(), triggerID) //nolint
 	}
@@ -231,6 +267,9 @@ func (connector *DbConnector) removeTrigger(triggerID string, trigger *moira.Tri
 	c.Send("DEL", triggerEventsKey(triggerID)) //nolint
 	c.Send("SREM", triggersListKey, triggerID) //nolint
 	c.Send("SREM", remoteTriggersListKey, triggerID) //nolint
+	if trigger.ClusterName != "" {
+		c.Send("SREM", remoteClusterTriggersListKey(trigger.ClusterName), triggerID) //nolint
+	}
 	c.Send("SREM", unusedTriggersKey, triggerID) //nolint
 	for _, tag := range trigger.Tags {
 		c.Send("SREM", tagTriggersKey(tag), triggerID) //nolint
@@ -238,6 +277,9 @@ func (connector *DbConnector) removeTrigger(triggerID string, trigger *moira.Tri
 	for _, pattern := range trigger.Patterns {
 		c.Send("SREM", patternTriggersKey(pattern), triggerID) //nolint
 	}
+	for _, parentID := range trigger.Parents {
+		c.Send("SREM", triggerChildrenKey(parentID), triggerID) //nolint
+	}
 	c.Send("ZADD", triggersToReindexKey, time.Now().Unix(), triggerID) //nolint
 
 	if _, err := c.Do("EXEC"); err != nil {
@@ -353,6 +395,10 @@ func (connector *DbConnector) triggerHasSubscriptions(trigger *moira.Trigger) (b
 var triggersListKey = "moira-triggers-list"
 var remoteTriggersListKey = "moira-remote-triggers-list"
 
+func remoteClusterTriggersListKey(clusterName string) string {
+	return "moira-remote-cluster-triggers-list:" + clusterName
+}
+
 func triggerKey(triggerID string) string {
 	return "moira-trigger:" + triggerID
 }
@@ -361,6 +407,10 @@ func triggerTagsKey(triggerID string) string {
 	return "moira-trigger-tags:" + triggerID
 }
 
+func triggerChildrenKey(triggerID string) string {
+	return "moira-trigger-children:" + triggerID
+}
+
 func patternTriggersKey(pattern string) string {
 	return "moira-pattern-triggers:" + pattern
 }
@@ -512,6 +512,42 @@ func TestTriggerStoring(t *testing.T) {
 			So(err, ShouldBeNil)
 			So(actual, ShouldResemble, []string{trigger.ID})
 		})
+
+		Convey("Test trigger manipulations update children of parent triggers", func() {
+			dataBase.flush()
+			parent := triggers[0]
+			child := triggers[1]
+			child.Parents = []string{parent.ID}
+
+			err := dataBase.SaveTrigger(parent.ID, &parent)
+			So(err, ShouldBeNil)
+			err = dataBase.SaveTrigger(child.ID, &child)
+			So(err, ShouldBeNil)
+
+			actual, err := dataBase.GetChildTriggerIDs(parent.ID)
+			So(err, ShouldBeNil)
+			So(actual, ShouldResemble, []string{child.ID})
+
+			// Remove parent from child
+			updatedChild := child
+			updatedChild.Parents = nil
+			err = dataBase.SaveTrigger(child.ID, &updatedChild)
+			So(err, ShouldBeNil)
+
+			actual, err = dataBase.GetChildTriggerIDs(parent.ID)
+			So(err, ShouldBeNil)
+			So(actual, ShouldBeEmpty)
+
+			// Remove child
+			err = dataBase.SaveTrigger(child.ID, &child)
+			So(err, ShouldBeNil)
+			err = dataBase.RemoveTrigger(child.ID)
+			So(err, ShouldBeNil)
+
+			actual, err = dataBase.GetChildTriggerIDs(parent.ID)
+			So(err, ShouldBeNil)
+			So(actual, ShouldBeEmpty)
+		})
 	})
 }
 
@@ -643,6 +679,58 @@ func TestRemoteTrigger(t *testing.T) {
 	})
 }
 
+func TestRemoteClusterTrigger(t *testing.T) {
+	logger, _ := logging.GetLogger("dataBase")
+	dataBase := newTestDatabase(logger, config)
+	trigger := &moira.Trigger{
+		ID:           "triggerID-0000000000020",
+		Name:         "remote cluster",
+		Targets:      []string{"test.target.remote2"},
+		Patterns:     []string{},
+		IsRemote:     true,
+		ClusterName:  "eu",
+		TriggerType:  moira.RisingTrigger,
+		AloneMetrics: map[string]bool{},
+	}
+	dataBase.flush()
+	defer dataBase.flush()
+
+	Convey("Saving remote cluster trigger", t, func() {
+		err := dataBase.SaveTrigger(trigger.ID, trigger)
+		So(err, ShouldBeNil)
+
+		Convey("Trigger should be added to remote and cluster triggers collections", func() {
+			ids, err := dataBase.GetRemoteTriggerIDs()
+			So(err, ShouldBeNil)
+			So(ids, ShouldResemble, []string{trigger.ID})
+			ids, err = dataBase.GetRemoteClusterTriggerIDs("eu")
+			So(err, ShouldBeNil)
+			So(ids, ShouldResemble, []string{trigger.ID})
+		})
+
+		Convey("Trigger should be moved to another cluster", func() {
+			movedTrigger := *trigger
+			movedTrigger.ClusterName = "us"
+			err := dataBase.SaveTrigger(movedTrigger.ID, &movedTrigger)
+			So(err, ShouldBeNil)
+			ids, err := dataBase.GetRemoteClusterTriggerIDs("eu")
+			So(err, ShouldBeNil)
+			So(ids, ShouldResemble, []string{})
+			ids, err = dataBase.GetRemoteClusterTriggerIDs("us")
+			So(err, ShouldBeNil)
+			So(ids, ShouldResemble, []string{trigger.ID})
+		})
+
+		Convey("Trigger should be removed from cluster triggers collection", func() {
+			err := dataBase.RemoveTrigger(trigger.ID)
+			So(err, ShouldBeNil)
+			ids, err := dataBase.GetRemoteClusterTriggerIDs("eu")
+			So(err, ShouldBeNil)
+			So(ids, ShouldResemble, []string{})
+		})
+	})
+}
+
 func TestTriggerErrorConnection(t *testing.T) {
 	logger, _ := logging.GetLogger("dataBase")
 	dataBase := newTestDatabase(logger, emptyConfig)
@@ -675,6 +763,10 @@ func TestTriggerErrorConnection(t *testing.T) {
 		So(err, ShouldNotBeNil)
 		So(actual4, ShouldBeNil)
 
+		actual5, err := dataBase.GetChildTriggerIDs("")
+		So(err, ShouldNotBeNil)
+		So(actual5, ShouldBeNil)
+
 		err = dataBase.RemovePatternTriggerIDs("")
 		So(err, ShouldNotBeNil)
 	})
@@ -37,6 +37,68 @@ func (connector *DbConnector) GetRemoteTriggersToCheckCount() (int64, error) {
 	return connector.getTriggersToCheckCount(remoteTriggersToCheckKey)
 }
 
+// AddLocalShardTriggersToCheck gets trigger IDs and save it to Redis Set of given checker shard
+func (connector *DbConnector) AddLocalShardTriggersToCheck(shardID string, triggerIDs []string) error {
+	return connector.addTriggersToCheck(shardTriggersToCheckKey(localTriggersToCheckKey, shardID), triggerIDs)
+}
+
+// AddRemoteShardTriggersToCheck gets remote trigger IDs and save it to Redis Set of given checker shard
+func (connector *DbConnector) AddRemoteShardTriggersToCheck(shardID string, triggerIDs []string) error {
+	return connector.addTriggersToCheck(shardTriggersToCheckKey(remoteTriggersToCheckKey, shardID), triggerIDs)
+}
+
+// GetLocalShardTriggersToCheck return random trigger ID from Redis Set of given checker shard
+func (connector *DbConnector) GetLocalShardTriggersToCheck(shardID string, count int) ([]string, error) {
+	return connector.getTriggersToCheck(shardTriggersToCheckKey(localTriggersToCheckKey, shardID), count)
+}
+
+// GetRemoteShardTriggersToCheck return random remote trigger ID from Redis Set of given checker shard
+func (connector *DbConnector) GetRemoteShardTriggersToCheck(shardID string, count int) ([]string, error) {
+	return connector.getTriggersToCheck(shardTriggersToCheckKey(remoteTriggersToCheckKey, shardID), count)
+}
+
+// GetLocalShardTriggersToCheckCount return number of triggers ID to check from Redis Set of given checker shard
+func (connector *DbConnector) GetLocalShardTriggersToCheckCount(shardID string) (int64, error) {
+	return connector.getTriggersToCheckCount(shardTriggersToCheckKey(localTriggersToCheckKey, shardID))
+}
+
+// GetRemoteShardTriggersToCheckCount return number of remote triggers ID to check from Redis Set of given checker shard
+func (connector *DbConnector) GetRemoteShardTriggersToCheckCount(shardID string) (int64, error) {
+	return connector.getTriggersToCheckCount(shardTriggersToCheckKey(remoteTriggersToCheckKey, shardID))
+}
+
+// AddRemoteClusterTriggersToCheck gets trigger IDs of remote graphite cluster and save it to Redis Set of given checker shard,
+// shard ID is empty if sharding is disabled
+func (connector *DbConnector) AddRemoteClusterTriggersToCheck(clusterName, shardID string, triggerIDs []string) error {
+	return connector.addTriggersToCheck(remoteClusterTriggersToCheckKey(clusterName, shardID), triggerIDs)
+}
+
+// GetRemoteClusterTriggersToCheck return random trigger IDs of remote graphite cluster from Redis Set of given checker shard
+func (connector *DbConnector) GetRemoteClusterTriggersToCheck(clusterName, shardID string, count int) ([]string, error) {
+	return connector.getTriggersToCheck(remoteClusterTriggersToCheckKey(clusterName, shardID), count)
+}
+
+// GetRemoteClusterTriggersToCheckCount return number of trigger IDs of remote graphite cluster to check from Redis Set of given checker shard
+func (connector *DbConnector) GetRemoteClusterTriggersToCheckCount(clusterName, shardID string) (int64, error) {
+	return connector.getTriggersToCheckCount(remoteClusterTriggersToCheckKey(clusterName, shardID))
+}
+
+// GetLocalTriggersToCheckTotalCount return number of triggers ID to check from Redis Set and Redis Sets of all checker shards
+func (connector *DbConnector) GetLocalTriggersToCheckTotalCount() (int64, error) {
+	return connector.getTriggersToCheckTotalCount(localTriggersToCheckKey)
+}
+
+// GetRemoteTriggersToCheckTotalCount return number of remote triggers ID to check from Redis Set and Redis Sets of all checker shards
+func (connector *DbConnector) GetRemoteTriggersToCheckTotalCount() (int64, error) {
+	return connector.getTriggersToCheckTotalCount(remoteTriggersToCheckKey)
+}
+
+// GetRemoteClusterTriggersToCheckTotalCount return number of trigger IDs of remote graphite cluster to check
+// from Redis Set and Redis Sets of all checker shards
+func (connector *DbConnector) GetRemoteClusterTriggersToCheckTotalCount(clusterName string) (int64, error) {
+	return connector.getTriggersToCheckTotalCount(remoteClusterTriggersToCheckKey(clusterName, ""))
+}
+
 func (connector *DbConnector) addTriggersToCheck(key string, triggerIDs []string) error {
 	c := connector.pool.Get()
 	defer c.Close()
@@ -78,5 +140,41 @@ func (connector *DbConnector) getTriggersToCheckCount(key string) (int64, error)
 	return triggersToCheckCount, nil
 }
 
+func (connector *DbConnector) getTriggersToCheckTotalCount(key string) (int64, error) {
+	c := connector.pool.Get()
+	defer c.Close()
+	instanceIDs, err := redis.Strings(c.Do("ZRANGE", checkerInstancesKey, 0, -1))
+	if err != nil {
+		return 0, fmt.Errorf("failed to get checker instances: %s", err.Error())
+	}
+
+	c.Send("MULTI")      //nolint
+	c.Send("SCARD", key) //nolint
+	for _, instanceID := range instanceIDs {
+		c.Send("SCARD", shardTriggersToCheckKey(key, instanceID)) //nolint
+	}
+	counts, err := redis.Int64s(c.Do("EXEC"))
+	if err != nil {
+		return 0, fmt.Errorf("failed to get trigger to check count: %s", err.Error())
+	}
+	var triggersToCheckCount int64
+	for _, count := range counts {
+		triggersToCheckCount += count
+	}
+	return triggersToCheckCount, nil
+}
+
+func shardTriggersToCheckKey(key, shardID string) string {
+	return key + ":" + shardID
+}
+
+func remoteClusterTriggersToCheckKey(clusterName, shardID string) string {
+	key := "moira-remote-cluster-triggers-to-check:" + clusterName
+	if shardID == "" {
+		return key
+	}
+	return shardTriggersToCheckKey(key, shardID)
+}
+
 var remoteTriggersToCheckKey = "moira-remote-triggers-to-check"
 var localTriggersToCheckKey = "moira-triggers-to-check"
@@ -26,8 +26,11 @@ const (
 )
 
 const (
-	format        = "15:04 02.01.2006"
-	remindMessage = "This metric has been in bad state for more than %v hours - please, fix."
+	format               = "15:04 02.01.2006"
+	remindMessage        = "This metric has been in bad state for more than %v hours - please, fix."
+	flappingStartMessage = "This metric started flapping: %v state changes in last %v seconds. Notifications are muted until it stabilises."
+	flappingStopMessage  = "This metric stopped flapping."
+	suppressedMessage    = "This metric changed its state while notifications were suppressed: %s."
 )
 
 // NotificationEvent represents trigger state changes event
@@ -50,6 +53,16 @@ type NotificationEvent struct {
 type EventInfo struct {
 	Maintenance *MaintenanceInfo `json:"maintenance,omitempty"`
 	Interval    *int64           `json:"interval,omitempty"`
+	Flapping    *FlappingInfo    `json:"flapping,omitempty"`
+	// Reason of suppression, other than maintenance or schedule, during which state was changed, e.g. bad parent trigger
+	SuppressedReason string `json:"suppressed_reason,omitempty"`
+}
+
+// FlappingInfo represents flapping status change of metric
+type FlappingInfo struct {
+	Changes int   `json:"changes,omitempty"`
+	Window  int64 `json:"window,omitempty"`
+	Stopped bool  `json:"stopped,omitempty"`
 }
 
 // CreateMessage - creates a message based on EventInfo.
@@ -63,6 +76,17 @@ func (event *NotificationEvent) CreateMessage(location *time.Location) string {
 		return ""
 	}
 
+	if event.MessageEventInfo.Flapping != nil {
+		if event.MessageEventInfo.Flapping.Stopped {
+			return flappingStopMessage
+		}
+		return fmt.Sprintf(flappingStartMessage, event.MessageEventInfo.Flapping.Changes, event.MessageEventInfo.Flapping.Window)
+	}
+
+	if event.MessageEventInfo.SuppressedReason != "" {
+		return fmt.Sprintf(suppressedMessage, event.MessageEventInfo.SuppressedReason)
+	}
+
 	if event.MessageEventInfo.Interval != nil && event.MessageEventInfo.Maintenance == nil {
 		return fmt.Sprintf(remindMessage, *event.MessageEventInfo.Interval)
 	}
@@ -128,6 +152,7 @@ func NotificationEventsToTemplatingEvents(events NotificationEvents) []templatin
 			Timestamp:      event.Timestamp,
 			State:          string(event.State),
 			Value:          event.Value,
+			Values:         event.Values,
 		})
 	}
 
@@ -241,8 +266,126 @@ const (
 	RisingTrigger = "rising"
 	// ExpressionTrigger represents trigger type with custom user expression
 	ExpressionTrigger = "expression"
+	// AnomalyTrigger represents trigger type, in which current value is compared with baseline learned from past seasons
+	AnomalyTrigger = "anomaly"
+	// ForecastTrigger represents trigger type, in which state depends on predicted time until value reaches limit
+	ForecastTrigger = "forecast"
+)
+
+// TriggerSource is metric source trigger targets are fetched from
+type TriggerSource string
+
+const (
+	// TriggerSourceNotSet means source is resolved by IsRemote flag for compatibility with triggers created before sources were declared
+	TriggerSourceNotSet TriggerSource = ""
+	// GraphiteLocal is graphite-in-redis storage of Moira
+	GraphiteLocal TriggerSource = "graphite_local"
+	// GraphiteRemote is remote graphite installation
+	GraphiteRemote TriggerSource = "graphite_remote"
+	// PrometheusRemote is Prometheus-compatible storage evaluating PromQL targets
+	PrometheusRemote TriggerSource = "prometheus_remote"
 )
 
+// ForecastValueName is name of metric state value containing predicted number of seconds until forecast trigger value reaches limit
+const ForecastValueName = "forecast"
+
+// Regression methods used by forecast trigger
+const (
+	// LinearForecastMethod fits trend using ordinary least squares
+	LinearForecastMethod = "linear"
+	// RobustForecastMethod fits trend using least squares with Huber weights, which is resistant to outliers
+	RobustForecastMethod = "robust"
+)
+
+// ForecastParams represents settings of forecast trigger
+// Trend is fitted over Lookback seconds and trigger switches state when predicted time until value reaches Limit falls below horizons
+type ForecastParams struct {
+	// Period in seconds to fit trend over
+	Lookback int64 `json:"lookback"`
+	// Value limit, e.g. disk size
+	Limit float64 `json:"limit"`
+	// Trigger switches to WARN if limit is predicted to be reached within this number of seconds
+	WarnHorizon *int64 `json:"warn_horizon"`
+	// Trigger switches to ERROR if limit is predicted to be reached within this number of seconds
+	ErrorHorizon *int64 `json:"error_horizon"`
+	// Regression method: linear or robust, linear if empty
+	Method string `json:"method,omitempty"`
+	// Direction of value reaching limit: rising or falling, rising if empty
+	Direction string `json:"direction,omitempty"`
+}
+
+// AnomalyParams represents sensitivity settings of anomaly trigger
+// Value is compared with mean and standard deviation of values at the same time of Seasons past seasons
+type AnomalyParams struct {
+	// Season length in seconds, e.g. 86400 for daily cycle
+	Season int64 `json:"season"`
+	// Number of past seasons to learn baseline from
+	Seasons int `json:"seasons"`
+	// Half-width in seconds of window around the same time in past season
+	Window int64 `json:"window"`
+	// Number of standard deviations from baseline mean to switch to WARN
+	WarnDeviation *float64 `json:"warn_deviation"`
+	// Number of standard deviations from baseline mean to switch to ERROR
+	ErrorDeviation *float64 `json:"error_deviation"`
+	// Direction of deviation to alert on: rising, falling or both if empty
+	Direction string `json:"direction,omitempty"`
+}
+
+// PendingCondition represents how long metric must stay in new state before state change fires an event
+type PendingCondition struct {
+	// Number of seconds metric must stay in new state
+	Period int64 `json:"period,omitempty"`
+	// Number of consecutive checks metric must stay in new state
+	Checks int `json:"checks,omitempty"`
+}
+
+// IsEmpty checks if condition does not delay state change
+func (condition PendingCondition) IsEmpty() bool {
+	return condition.Period <= 0 && condition.Checks <= 0
+}
+
+// IsSatisfied checks if metric stayed in new state long enough
+func (condition PendingCondition) IsSatisfied(duration int64, checks int) bool {
+	return duration >= condition.Period && checks >= condition.Checks
+}
+
+// PendingParams represents pending settings of trigger
+// States overrides default condition for changes to given metric state
+type PendingParams struct {
+	Period int64                      `json:"period,omitempty"`
+	Checks int                        `json:"checks,omitempty"`
+	States map[State]PendingCondition `json:"states,omitempty"`
+}
+
+// GetCondition returns pending condition for change to given state
+func (params *PendingParams) GetCondition(state State) PendingCondition {
+	if params == nil {
+		return PendingCondition{}
+	}
+	if condition, ok := params.States[state]; ok {
+		return condition
+	}
+	return PendingCondition{Period: params.Period, Checks: params.Checks}
+}
+
+// FlappingParams represents flapping detection settings of trigger
+// Metric starts flapping when its state changes at least Changes times within Window seconds
+// and stops flapping when it changes state no more than StopChanges times within Window seconds
+type FlappingParams struct {
+	Window      int64 `json:"window"`
+	Changes     int   `json:"changes"`
+	StopChanges int   `json:"stop_changes,omitempty"`
+}
+
+// ThresholdOverride represents thresholds used instead of trigger ones for metrics matching Pattern
+type ThresholdOverride struct {
+	// Graphite-like pattern of metric name
+	Pattern    string   `json:"pattern"`
+	WarnValue  *float64 `json:"warn_value"`
+	ErrorValue *float64 `json:"error_value"`
+	Expression string   `json:"expression,omitempty"`
+}
+
 // Trigger represents trigger data object
 type Trigger struct {
 	ID               string          `json:"id"`
@@ -260,8 +403,17 @@ type Trigger struct {
 	PythonExpression *string         `json:"python_expression,omitempty"`
 	Patterns         []string        `json:"patterns"`
 	IsRemote         bool            `json:"is_remote"`
+	TriggerSource    TriggerSource   `json:"trigger_source,omitempty"`
+	ClusterName      string          `json:"cluster_name,omitempty"`
 	MuteNewMetrics   bool            `json:"mute_new_metrics"`
 	AloneMetrics     map[string]bool `json:"alone_metrics"`
+	Parents          []string        `json:"parents,omitempty"`
+	Anomaly          *AnomalyParams  `json:"anomaly,omitempty"`
+	Forecast         *ForecastParams `json:"forecast,omitempty"`
+	Pending          *PendingParams  `json:"pending,omitempty"`
+	Flapping         *FlappingParams `json:"flapping,omitempty"`
+	// Ordered list of threshold overrides, first one matching metric name is used
+	Overrides []ThresholdOverride `json:"overrides,omitempty"`
 }
 
 // TriggerCheck represents trigger data with last check data and check timestamp
@@ -294,6 +446,7 @@ type CheckData struct {
 	LastSuccessfulCheckTimestamp int64             `json:"last_successful_check_timestamp"`
 	Suppressed                   bool              `json:"suppressed,omitempty"`
 	SuppressedState              State             `json:"suppressed_state,omitempty"`
+	SuppressedReason             string            `json:"suppressed_reason,omitempty"`
 	Message                      string            `json:"msg,omitempty"`
 }
 
@@ -309,15 +462,22 @@ func (checkData *CheckData) RemoveMetricsToTargetRelation() {
 
 // MetricState represents metric state data for given timestamp
 type MetricState struct {
-	EventTimestamp  int64              `json:"event_timestamp"`
-	State           State              `json:"state"`
-	Suppressed      bool               `json:"suppressed"`
-	SuppressedState State              `json:"suppressed_state,omitempty"`
-	Timestamp       int64              `json:"timestamp"`
-	Value           *float64           `json:"value,omitempty"`
-	Values          map[string]float64 `json:"values,omitempty"`
-	Maintenance     int64              `json:"maintenance,omitempty"`
-	MaintenanceInfo MaintenanceInfo    `json:"maintenance_info"`
+	EventTimestamp   int64              `json:"event_timestamp"`
+	State            State              `json:"state"`
+	Suppressed       bool               `json:"suppressed"`
+	SuppressedState  State              `json:"suppressed_state,omitempty"`
+	SuppressedReason string             `json:"suppressed_reason,omitempty"`
+	PendingState     State              `json:"pending_state,omitempty"`
+	PendingSince     int64              `json:"pending_since,omitempty"`
+	PendingChecks    int                `json:"pending_checks,omitempty"`
+	PendingLastCheck int64              `json:"pending_last_check,omitempty"` // Timestamp of trigger check last counted in PendingChecks
+	Flapping         bool               `json:"flapping,omitempty"`
+	StateChanges     []int64            `json:"state_changes,omitempty"`
+	Timestamp        int64              `json:"timestamp"`
+	Value            *float64           `json:"value,omitempty"`
+	Values           map[string]float64 `json:"values,omitempty"`
+	Maintenance      int64              `json:"maintenance,omitempty"`
+	MaintenanceInfo  MaintenanceInfo    `json:"maintenance_info"`
 	// AloneMetrics    map[string]string  `json:"alone_metrics"` // represents a relation between name of alone metrics and their targets
 }
 
@@ -354,6 +514,46 @@ type MetricEvent struct {
 	Pattern string `json:"pattern"`
 }
 
+// IngestionLimitViolation represents pattern or metric prefix which exceeded filter ingestion limits
+type IngestionLimitViolation struct {
+	Pattern   string `json:"pattern,omitempty"`
+	Prefix    string `json:"prefix,omitempty"`
+	Limit     int    `json:"limit"`
+	Dropped   int64  `json:"dropped"`
+	Timestamp int64  `json:"timestamp"`
+}
+
+// IngestionLimitViolationTTL is time in seconds after which violation is forgotten if filter does not report it anymore
+const IngestionLimitViolationTTL = 3600
+
+// GetKey returns key identifying violated limit of pattern or prefix
+func (violation *IngestionLimitViolation) GetKey() string {
+	if violation.Pattern != "" {
+		return "pattern:" + violation.Pattern
+	}
+	return "prefix:" + violation.Prefix
+}
+
+// RejectedTimestampSample represents last point received by filter with timestamp out of acceptance window
+type RejectedTimestampSample struct {
+	Reason    string `json:"reason"`
+	Action    string `json:"action"`
+	Sample    string `json:"sample"`
+	Count     int    `json:"count"`
+	Timestamp int64  `json:"timestamp"`
+}
+
+// RejectedTimestampSampleTTL is time in seconds after which sample is forgotten if filter does not report it anymore
+const RejectedTimestampSampleTTL = 3600
+
+// MetricTapSample represents metric point matched by pattern tapped for debugging
+type MetricTapSample struct {
+	Pattern   string  `json:"pattern"`
+	Metric    string  `json:"metric"`
+	Value     float64 `json:"value"`
+	Timestamp int64   `json:"timestamp"`
+}
+
 // SearchHighlight represents highlight
 type SearchHighlight struct {
 	Field string
@@ -450,14 +650,14 @@ func (event NotificationEvent) GetMetricsValues() string {
 		return "—"
 	}
 	if len(targetNames) == 1 {
-		return strconv.FormatFloat(event.Values[targetNames[0]], 'f', -1, 64)
+		return formatMetricValue(targetNames[0], event.Values[targetNames[0]])
 	}
 	var builder strings.Builder
 	sort.Strings(targetNames)
 	for i, targetName := range targetNames {
 		builder.WriteString(targetName)
 		builder.WriteString(": ")
-		value := strconv.FormatFloat(event.Values[targetName], 'f', -1, 64)
+		value := formatMetricValue(targetName, event.Values[targetName])
 		builder.WriteString(value)
 		if i < len(targetNames)-1 {
 			builder.WriteString(", ")
@@ -466,6 +666,14 @@ func (event NotificationEvent) GetMetricsValues() string {
 	return builder.String()
 }
 
+// formatMetricValue formats forecast as approximate time left and other values as numbers
+func formatMetricValue(valueName string, value float64) string {
+	if valueName == ForecastValueName {
+		return templating.FormatTimeLeft(int64(value))
+	}
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
 // FormatTimestamp gets event timestamp and format it using given location to human readable presentation
 func (event NotificationEvent) FormatTimestamp(location *time.Location) string {
 	return time.Unix(event.Timestamp, 0).In(location).Format("15:04")
@@ -530,6 +738,17 @@ func (checkData CheckData) GetEventTimestamp() int64 {
 	return checkData.EventTimestamp
 }
 
+// GetTriggerSource returns metric source of trigger, source of trigger without declared one is resolved by IsRemote flag
+func (trigger *Trigger) GetTriggerSource() TriggerSource {
+	if trigger.TriggerSource != TriggerSourceNotSet {
+		return trigger.TriggerSource
+	}
+	if trigger.IsRemote {
+		return GraphiteRemote
+	}
+	return GraphiteLocal
+}
+
 // IsSimple checks triggers patterns
 // If patterns more than one or it contains standard graphite wildcard symbols,
 // when this target can contain more then one metrics, and is it not simple trigger
@@ -151,6 +151,19 @@ func TestNotificationEvent_CreateMessage(t *testing.T) {
 			event := NotificationEvent{MessageEventInfo: &EventInfo{Interval: &interval}}
 			So(event.CreateMessage(nil), ShouldEqual, message)
 		})
+		Convey("Test: metric started flapping", func() {
+			expected := "This metric started flapping: 5 state changes in last 600 seconds. Notifications are muted until it stabilises."
+			event := NotificationEvent{MessageEventInfo: &EventInfo{Flapping: &FlappingInfo{Changes: 5, Window: 600}}}
+			So(event.CreateMessage(nil), ShouldEqual, expected)
+		})
+		Convey("Test: metric stopped flapping", func() {
+			event := NotificationEvent{MessageEventInfo: &EventInfo{Flapping: &FlappingInfo{Stopped: true}}}
+			So(event.CreateMessage(nil), ShouldEqual, "This metric stopped flapping.")
+		})
+		Convey("Test: metric changed state while suppressed by parent trigger", func() {
+			event := NotificationEvent{MessageEventInfo: &EventInfo{SuppressedReason: "parent trigger parent-id is in ERROR state"}}
+			So(event.CreateMessage(nil), ShouldEqual, "This metric changed its state while notifications were suppressed: parent trigger parent-id is in ERROR state.")
+		})
 		Convey("Test: check for void MaintenanceInfo", func() {
 			event := NotificationEvent{MessageEventInfo: &EventInfo{}}
 			So(event.CreateMessage(nil), ShouldEqual, "")
@@ -222,6 +235,11 @@ func TestNotificationEvent_GetValue(t *testing.T) {
 			event.Values["t1"] = 2.3222222
 			So(event.GetMetricsValues(), ShouldResemble, "t1: 2.3222222, t2: 0.12")
 		})
+		Convey("Target with forecast", func() {
+			event.Values["t1"] = 20
+			event.Values[ForecastValueName] = 10810
+			So(event.GetMetricsValues(), ShouldResemble, "forecast: ~3h, t1: 20")
+		})
 	})
 }
 
@@ -334,6 +352,20 @@ func TestTrigger_IsSimple(t *testing.T) {
 	})
 }
 
+func TestTrigger_GetTriggerSource(t *testing.T) {
+	Convey("Declared source is returned", t, func() {
+		trigger := Trigger{TriggerSource: PrometheusRemote, IsRemote: true}
+		So(trigger.GetTriggerSource(), ShouldEqual, PrometheusRemote)
+	})
+
+	Convey("Not set source is resolved by is_remote", t, func() {
+		trigger := Trigger{IsRemote: true}
+		So(trigger.GetTriggerSource(), ShouldEqual, GraphiteRemote)
+		trigger.IsRemote = false
+		So(trigger.GetTriggerSource(), ShouldEqual, GraphiteLocal)
+	})
+}
+
 func TestCheckData_GetEventTimestamp(t *testing.T) {
 	Convey("Get event timestamp", t, func() {
 		checkData := CheckData{Timestamp: 800, EventTimestamp: 0}
@@ -0,0 +1,103 @@
+package expression
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/moira-alert/moira"
+)
+
+// MinBaselineValues is minimal number of baseline values required to judge value as anomaly.
+// Value with shorter baseline is considered OK
+const MinBaselineValues = 3
+
+// GetAnomalyDeviation returns number of standard deviations value differs from baseline mean (z-score)
+// Deviation is positive if value is above mean and negative otherwise.
+// Any change of constant baseline is anomalous, so deviation from it is infinite
+func GetAnomalyDeviation(value float64, baseline []float64) float64 {
+	var sum float64
+	for _, baselineValue := range baseline {
+		sum += baselineValue
+	}
+	mean := sum / float64(len(baseline))
+
+	var squares float64
+	for _, baselineValue := range baseline {
+		squares += (baselineValue - mean) * (baselineValue - mean)
+	}
+	stdDev := math.Sqrt(squares / float64(len(baseline)))
+
+	if stdDev == 0 {
+		switch {
+		case value > mean:
+			return math.Inf(1)
+		case value < mean:
+			return math.Inf(-1)
+		default:
+			return 0
+		}
+	}
+	return (value - mean) / stdDev
+}
+
+// ValidateAnomalyParams checks anomaly trigger sensitivity settings
+func ValidateAnomalyParams(params *moira.AnomalyParams) error {
+	if params == nil {
+		return fmt.Errorf("trigger_type set to anomaly, but no anomaly params provided")
+	}
+	if params.Season <= 0 {
+		return fmt.Errorf("anomaly season must be positive")
+	}
+	if params.Seasons <= 0 {
+		return fmt.Errorf("anomaly seasons must be positive")
+	}
+	if params.Window < 0 || params.Window*2 >= params.Season {
+		return fmt.Errorf("anomaly window must not be negative and must be less than half of season")
+	}
+	if params.WarnDeviation == nil && params.ErrorDeviation == nil {
+		return fmt.Errorf("at least one of anomaly warn_deviation or error_deviation is required")
+	}
+	if params.WarnDeviation != nil && *params.WarnDeviation <= 0 {
+		return fmt.Errorf("anomaly warn_deviation must be positive")
+	}
+	if params.ErrorDeviation != nil && *params.ErrorDeviation <= 0 {
+		return fmt.Errorf("anomaly error_deviation must be positive")
+	}
+	if params.WarnDeviation != nil && params.ErrorDeviation != nil && *params.WarnDeviation >= *params.ErrorDeviation {
+		return fmt.Errorf("anomaly error_deviation should be greater than warn_deviation")
+	}
+	switch params.Direction {
+	case "", moira.RisingTrigger, moira.FallingTrigger:
+	default:
+		return fmt.Errorf("wrong anomaly direction: %v, allowable values: '%v', '%v' or empty for both",
+			params.Direction, moira.RisingTrigger, moira.FallingTrigger)
+	}
+	return nil
+}
+
+func (triggerExpression *TriggerExpression) evaluateAnomaly() (moira.State, error) {
+	params := triggerExpression.Anomaly
+	if err := ValidateAnomalyParams(params); err != nil {
+		return "", err
+	}
+	if len(triggerExpression.BaselineValues) < MinBaselineValues {
+		return moira.StateOK, nil
+	}
+
+	deviation := GetAnomalyDeviation(triggerExpression.MainTargetValue, triggerExpression.BaselineValues)
+	switch params.Direction {
+	case moira.RisingTrigger:
+	case moira.FallingTrigger:
+		deviation = -deviation
+	default:
+		deviation = math.Abs(deviation)
+	}
+
+	if params.ErrorDeviation != nil && deviation >= *params.ErrorDeviation {
+		return moira.StateERROR, nil
+	}
+	if params.WarnDeviation != nil && deviation >= *params.WarnDeviation {
+		return moira.StateWARN, nil
+	}
+	return moira.StateOK, nil
+}
@@ -0,0 +1,103 @@
+package expression
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/moira-alert/moira"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestGetAnomalyDeviation(t *testing.T) {
+	Convey("Test anomaly deviation", t, func() {
+		baseline := []float64{8, 10, 12}
+		So(GetAnomalyDeviation(10, baseline), ShouldEqual, 0)
+		So(GetAnomalyDeviation(10+math.Sqrt(8.0/3)*2, baseline), ShouldAlmostEqual, 2)
+		So(GetAnomalyDeviation(10-math.Sqrt(8.0/3)*3, baseline), ShouldAlmostEqual, -3)
+	})
+
+	Convey("Test constant baseline", t, func() {
+		baseline := []float64{5, 5, 5}
+		So(GetAnomalyDeviation(5, baseline), ShouldEqual, 0)
+		So(math.IsInf(GetAnomalyDeviation(6, baseline), 1), ShouldBeTrue)
+		So(math.IsInf(GetAnomalyDeviation(4, baseline), -1), ShouldBeTrue)
+	})
+}
+
+func TestAnomalyExpression(t *testing.T) {
+	warnDeviation := 2.0
+	errorDeviation := 3.0
+	params := &moira.AnomalyParams{Season: 86400, Seasons: 3, WarnDeviation: &warnDeviation, ErrorDeviation: &errorDeviation}
+	baseline := []float64{8, 10, 12}
+	stdDev := math.Sqrt(8.0 / 3)
+
+	Convey("Test both directions", t, func() {
+		result, err := (&TriggerExpression{MainTargetValue: 10, TriggerType: moira.AnomalyTrigger, Anomaly: params, BaselineValues: baseline}).Evaluate()
+		So(err, ShouldBeNil)
+		So(result, ShouldResemble, moira.StateOK)
+
+		result, err = (&TriggerExpression{MainTargetValue: 10 + stdDev*2.5, TriggerType: moira.AnomalyTrigger, Anomaly: params, BaselineValues: baseline}).Evaluate()
+		So(err, ShouldBeNil)
+		So(result, ShouldResemble, moira.StateWARN)
+
+		result, err = (&TriggerExpression{MainTargetValue: 10 - stdDev*3.5, TriggerType: moira.AnomalyTrigger, Anomaly: params, BaselineValues: baseline}).Evaluate()
+		So(err, ShouldBeNil)
+		So(result, ShouldResemble, moira.StateERROR)
+	})
+
+	Convey("Test single direction", t, func() {
+		rising := *params
+		rising.Direction = moira.RisingTrigger
+		result, err := (&TriggerExpression{MainTargetValue: 10 - stdDev*3.5, TriggerType: moira.AnomalyTrigger, Anomaly: &rising, BaselineValues: baseline}).Evaluate()
+		So(err, ShouldBeNil)
+		So(result, ShouldResemble, moira.StateOK)
+
+		falling := *params
+		falling.Direction = moira.FallingTrigger
+		result, err = (&TriggerExpression{MainTargetValue: 10 - stdDev*3.5, TriggerType: moira.AnomalyTrigger, Anomaly: &falling, BaselineValues: baseline}).Evaluate()
+		So(err, ShouldBeNil)
+		So(result, ShouldResemble, moira.StateERROR)
+
+		result, err = (&TriggerExpression{MainTargetValue: 10 + stdDev*3.5, TriggerType: moira.AnomalyTrigger, Anomaly: &falling, BaselineValues: baseline}).Evaluate()
+		So(err, ShouldBeNil)
+		So(result, ShouldResemble, moira.StateOK)
+	})
+
+	Convey("Test constant baseline", t, func() {
+		result, err := (&TriggerExpression{MainTargetValue: 100, TriggerType: moira.AnomalyTrigger, Anomaly: params, BaselineValues: []float64{0, 0, 0}}).Evaluate()
+		So(err, ShouldBeNil)
+		So(result, ShouldResemble, moira.StateERROR)
+
+		result, err = (&TriggerExpression{MainTargetValue: 0, TriggerType: moira.AnomalyTrigger, Anomaly: params, BaselineValues: []float64{0, 0, 0}}).Evaluate()
+		So(err, ShouldBeNil)
+		So(result, ShouldResemble, moira.StateOK)
+	})
+
+	Convey("Test short baseline", t, func() {
+		result, err := (&TriggerExpression{MainTargetValue: 1000, TriggerType: moira.AnomalyTrigger, Anomaly: params, BaselineValues: []float64{10, 11}}).Evaluate()
+		So(err, ShouldBeNil)
+		So(result, ShouldResemble, moira.StateOK)
+	})
+
+	Convey("Test invalid params", t, func() {
+		result, err := (&TriggerExpression{MainTargetValue: 10, TriggerType: moira.AnomalyTrigger}).Evaluate()
+		So(err, ShouldResemble, ErrInvalidExpression{fmt.Errorf("trigger_type set to anomaly, but no anomaly params provided")})
+		So(result, ShouldBeEmpty)
+
+		inverted := *params
+		inverted.WarnDeviation, inverted.ErrorDeviation = &errorDeviation, &warnDeviation
+		_, err = (&TriggerExpression{MainTargetValue: 10, TriggerType: moira.AnomalyTrigger, Anomaly: &inverted}).Evaluate()
+		So(err, ShouldResemble, ErrInvalidExpression{fmt.Errorf("anomaly error_deviation should be greater than warn_deviation")})
+
+		wideWindow := *params
+		wideWindow.Window = wideWindow.Season
+		_, err = (&TriggerExpression{MainTargetValue: 10, TriggerType: moira.AnomalyTrigger, Anomaly: &wideWindow}).Evaluate()
+		So(err, ShouldNotBeNil)
+
+		wrongDirection := *params
+		wrongDirection.Direction = "sideways"
+		_, err = (&TriggerExpression{MainTargetValue: 10, TriggerType: moira.AnomalyTrigger, Anomaly: &wrongDirection}).Evaluate()
+		So(err, ShouldNotBeNil)
+	})
+}
@@ -39,12 +39,34 @@ type TriggerExpression struct {
 	MainTargetValue         float64
 	AdditionalTargetsValues map[string]float64
 	PreviousState           moira.State
+
+	// Anomaly trigger settings and values of main target at the same time in past seasons
+	Anomaly        *moira.AnomalyParams
+	BaselineValues []float64
+
+	// Forecast trigger settings and predicted number of seconds until value reaches limit, nil if limit is never reached
+	Forecast         *moira.ForecastParams
+	ForecastTimeLeft *int64
+
+	// Fetched values of targets by target name and evaluated timestamp, used by window functions
+	Series    map[string]Series
+	Timestamp int64
+}
+
+// userExpression represents parsed user expression and number of seconds its window functions read
+type userExpression struct {
+	expr   *govaluate.EvaluableExpression
+	window int64
 }
 
 // Get realizing govaluate.Parameters interface used in evaluable expression
 func (triggerExpression TriggerExpression) Get(name string) (interface{}, error) {
 	name = strings.ToLower(name)
 
+	if strings.HasPrefix(name, seriesVariablePrefix) {
+		return triggerExpression.getSeriesWindow(strings.TrimPrefix(name, seriesVariablePrefix))
+	}
+
 	switch name {
 	case "ok":
 		return moira.StateOK, nil
@@ -77,8 +99,29 @@ func (triggerExpression TriggerExpression) Get(name string) (interface{}, error)
 	}
 }
 
+func (triggerExpression TriggerExpression) getSeriesWindow(targetName string) (interface{}, error) {
+	if _, err := triggerExpression.Get(targetName); err != nil {
+		return nil, err
+	}
+	return seriesWindow{Series: triggerExpression.Series[targetName], timestamp: triggerExpression.Timestamp}, nil
+}
+
 // Evaluate gets trigger expression and evaluates it for given parameters using govaluate
 func (triggerExpression *TriggerExpression) Evaluate() (moira.State, error) {
+	switch triggerExpression.TriggerType {
+	case moira.AnomalyTrigger:
+		state, err := triggerExpression.evaluateAnomaly()
+		if err != nil {
+			return "", ErrInvalidExpression{internalError: err}
+		}
+		return state, nil
+	case moira.ForecastTrigger:
+		state, err := triggerExpression.evaluateForecast()
+		if err != nil {
+			return "", ErrInvalidExpression{internalError: err}
+		}
+		return state, nil
+	}
 	expr, err := getExpression(triggerExpression)
 	if err != nil {
 		return "", ErrInvalidExpression{internalError: err}
@@ -134,18 +177,58 @@ func getSimpleExpression(triggerExpression *TriggerExpression) (*govaluate.Evalu
 }
 
 func getUserExpression(triggerExpression string) (*govaluate.EvaluableExpression, error) {
-	if expr, found := exprCache.Get(triggerExpression); found {
-		return expr.(*govaluate.EvaluableExpression), nil
+	parsed, err := getParsedUserExpression(triggerExpression)
+	if err != nil {
+		return nil, err
 	}
+	return parsed.expr, nil
+}
 
-	expr, err := govaluate.NewEvaluableExpression(triggerExpression)
+// GetWindow returns maximum number of seconds preceding evaluated timestamp which window functions of expression read
+func GetWindow(triggerExpression string) (int64, error) {
+	parsed, err := getParsedUserExpression(triggerExpression)
 	if err != nil {
-		if strings.Contains(err.Error(), "Undefined function") {
-			return nil, fmt.Errorf("functions is forbidden")
+		return 0, err
+	}
+	return parsed.window, nil
+}
+
+// GetTriggerWindow returns maximum window of expressions of trigger and its threshold overrides
+func GetTriggerWindow(trigger *moira.Trigger) (int64, error) {
+	if trigger.TriggerType != moira.ExpressionTrigger {
+		return 0, nil
+	}
+	expressions := []string{moira.UseString(trigger.Expression)}
+	for _, override := range trigger.Overrides {
+		expressions = append(expressions, override.Expression)
+	}
+	var window int64
+	for _, triggerExpression := range expressions {
+		if triggerExpression == "" {
+			continue
 		}
-		return nil, err
+		expressionWindow, err := GetWindow(triggerExpression)
+		if err != nil {
+			return 0, ErrInvalidExpression{internalError: err}
+		}
+		if expressionWindow > window {
+			window = expressionWindow
+		}
+	}
+	return window, nil
+}
+
+func getParsedUserExpression(triggerExpression string) (userExpression, error) {
+	if parsed, found := exprCache.Get(triggerExpression); found {
+		return parsed.(userExpression), nil
+	}
+
+	expr, window, err := parseUserExpression(triggerExpression)
+	if err != nil {
+		return userExpression{}, err
 	}
 
-	exprCache.Add(triggerExpression, expr, cache.NoExpiration) //nolint
-	return expr, nil
+	parsed := userExpression{expr: expr, window: window}
+	exprCache.Add(triggerExpression, parsed, cache.NoExpiration) //nolint
+	return parsed, nil
 }
@@ -79,7 +79,12 @@ func TestExpression(t *testing.T) {
 
 		expression = "min(t1, t2) > 10 ? ERROR : OK"
 		result, err = (&TriggerExpression{Expression: &expression, MainTargetValue: 11.0, AdditionalTargetsValues: map[string]float64{"t2": 4.0}, TriggerType: moira.ExpressionTrigger}).Evaluate()
-		So(err, ShouldResemble, ErrInvalidExpression{fmt.Errorf("functions is forbidden")})
+		So(err, ShouldBeNil)
+		So(result, ShouldResemble, moira.StateOK)
+
+		expression = "median(t1, t2) > 10 ? ERROR : OK"
+		result, err = (&TriggerExpression{Expression: &expression, MainTargetValue: 11.0, AdditionalTargetsValues: map[string]float64{"t2": 4.0}, TriggerType: moira.ExpressionTrigger}).Evaluate()
+		So(err, ShouldResemble, ErrInvalidExpression{fmt.Errorf("unknown function median")})
 		So(result, ShouldBeEmpty)
 
 		expression = "PREV_STATE"
@@ -89,6 +94,73 @@ func TestExpression(t *testing.T) {
 	})
 }
 
+func TestFunctions(t *testing.T) {
+	evaluate := func(expression string, t1, t2 float64) (moira.State, error) {
+		return (&TriggerExpression{
+			Expression:              &expression,
+			MainTargetValue:         t1,
+			AdditionalTargetsValues: map[string]float64{"t2": t2},
+			TriggerType:             moira.ExpressionTrigger,
+		}).Evaluate()
+	}
+
+	Convey("Test math functions", t, func() {
+		cases := []struct {
+			expression string
+			t1         float64
+			t2         float64
+			expected   moira.State
+		}{
+			{"abs(t1 - t2) > 5 ? ERROR : OK", 1, 10, moira.StateERROR},
+			{"abs(t1 - t2) > 5 ? ERROR : OK", 8, 10, moira.StateOK},
+			{"ceil(t1) == 2 && floor(t1) == 1 ? WARN : OK", 1.5, 0, moira.StateWARN},
+			{"round(t1) == 2 && round(t1, 2) == 1.57 ? WARN : OK", 1.5678, 0, moira.StateWARN},
+			{"sqrt(t1) == 3 && pow(t1, 2) == 81 ? WARN : OK", 9, 0, moira.StateWARN},
+			{"log(exp(t1)) == t1 && log10(t2) == 2 ? WARN : OK", 3, 100, moira.StateWARN},
+			{"min(t1, t2, 5) == 5 && max(t1, t2, 5) == t2 ? WARN : OK", 7, 9, moira.StateWARN},
+			{"isNaN(log(t1 - t2)) ? ERROR : OK", 1, 10, moira.StateERROR},
+			{"isInf(log(t1)) ? ERROR : OK", 0, 0, moira.StateERROR},
+			{"max(abs(t1), abs(t2)) > 9 ? ERROR : (round(t1 / t2, 1) < -0.5 ? WARN : OK)", -6, 9, moira.StateWARN},
+		}
+		for _, testCase := range cases {
+			result, err := evaluate(testCase.expression, testCase.t1, testCase.t2)
+			So(err, ShouldBeNil)
+			So(result, ShouldEqual, testCase.expected)
+		}
+	})
+
+	Convey("Test functions arguments validation", t, func() {
+		result, err := evaluate("abs(t1, t2) > 1 ? ERROR : OK", 1, 2)
+		So(err, ShouldResemble, ErrInvalidExpression{fmt.Errorf("function abs expects 1 arguments, got 2")})
+		So(result, ShouldBeEmpty)
+
+		result, err = evaluate("t1 > 1 ? ERROR : (pow(t1) > 1 ? WARN : OK)", 2, 2)
+		So(err, ShouldResemble, ErrInvalidExpression{fmt.Errorf("function pow expects 2 arguments, got 1")})
+		So(result, ShouldBeEmpty)
+
+		result, err = evaluate("max() > 1 ? ERROR : OK", 1, 2)
+		So(err, ShouldResemble, ErrInvalidExpression{fmt.Errorf("function max expects at least 1 arguments, got 0")})
+		So(result, ShouldBeEmpty)
+
+		result, err = evaluate("round(t1, 1, 2) > 1 ? ERROR : OK", 1, 2)
+		So(err, ShouldResemble, ErrInvalidExpression{fmt.Errorf("function round expects at most 2 arguments, got 3")})
+		So(result, ShouldBeEmpty)
+
+		result, err = evaluate("abs(OK) > 1 ? ERROR : OK", 1, 2)
+		So(err, ShouldResemble, ErrInvalidExpression{fmt.Errorf("function abs expects number arguments")})
+		So(result, ShouldBeEmpty)
+	})
+
+	Convey("Test function descriptions", t, func() {
+		descriptions := GetFunctionDescriptions()
+		So(descriptions, ShouldHaveLength, len(functions))
+		So(descriptions[0], ShouldResemble, FunctionDescription{Name: "abs", Signature: "abs(x)", Description: "Absolute value of x"})
+		for _, description := range descriptions {
+			So(description.Signature, ShouldStartWith, description.Name+"(")
+		}
+	})
+}
+
 func TestGetExpressionValue(t *testing.T) {
 	floatVal := 10.0
 	Convey("Test basic strings", t, func() {
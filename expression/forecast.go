@@ -0,0 +1,181 @@
+package expression
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/moira-alert/moira"
+)
+
+const (
+	// MinForecastValues is minimal number of values required to fit trend
+	MinForecastValues = 3
+	// maxForecastPeriod is max number of seconds to predict, limit reached later is considered unreachable
+	maxForecastPeriod = 100 * 365 * 24 * 60 * 60
+	// robustIterations is number of reweighting iterations of robust regression
+	robustIterations = 10
+	// huberConstant is Huber loss threshold in robust standard deviations
+	huberConstant = 1.345
+)
+
+// GetForecastTimestamp fits trend to values and returns timestamp when it reaches limit
+// Returns now if trend at now has already reached limit and false if there are too few values or trend never reaches limit
+func GetForecastTimestamp(timestamps []int64, values []float64, now int64, params *moira.ForecastParams) (int64, bool) {
+	if len(values) < MinForecastValues || len(timestamps) != len(values) {
+		return 0, false
+	}
+
+	offsets := make([]float64, len(timestamps))
+	for i, timestamp := range timestamps {
+		offsets[i] = float64(timestamp - now)
+	}
+	var slope, intercept float64
+	if params.Method == moira.RobustForecastMethod {
+		slope, intercept = fitRobustTrend(offsets, values)
+	} else {
+		slope, intercept = fitTrend(offsets, values, nil)
+	}
+
+	distance := params.Limit - intercept
+	if params.Direction == moira.FallingTrigger {
+		distance, slope = -distance, -slope
+	}
+	if distance <= 0 {
+		return now, true
+	}
+	if slope <= 0 {
+		return 0, false
+	}
+	secondsLeft := math.Ceil(distance / slope)
+	if secondsLeft > maxForecastPeriod {
+		return 0, false
+	}
+	return now + int64(secondsLeft), true
+}
+
+// fitTrend returns slope and intercept of line fitted using weighted least squares, all weights are 1 if weights is nil
+func fitTrend(xs, ys, weights []float64) (float64, float64) {
+	var sumWeights, sumX, sumY float64
+	for i := range xs {
+		weight := 1.0
+		if weights != nil {
+			weight = weights[i]
+		}
+		sumWeights += weight
+		sumX += weight * xs[i]
+		sumY += weight * ys[i]
+	}
+	meanX, meanY := sumX/sumWeights, sumY/sumWeights
+
+	var sumXX, sumXY float64
+	for i := range xs {
+		weight := 1.0
+		if weights != nil {
+			weight = weights[i]
+		}
+		sumXX += weight * (xs[i] - meanX) * (xs[i] - meanX)
+		sumXY += weight * (xs[i] - meanX) * (ys[i] - meanY)
+	}
+	if sumXX == 0 {
+		return 0, meanY
+	}
+	slope := sumXY / sumXX
+	return slope, meanY - slope*meanX
+}
+
+// fitRobustTrend returns slope and intercept of line fitted using iteratively reweighted least squares with Huber weights
+func fitRobustTrend(xs, ys []float64) (float64, float64) {
+	weights := make([]float64, len(xs))
+	for i := range weights {
+		weights[i] = 1
+	}
+	residuals := make([]float64, len(xs))
+
+	slope, intercept := fitTrend(xs, ys, weights)
+	for iteration := 0; iteration < robustIterations; iteration++ {
+		for i := range xs {
+			residuals[i] = math.Abs(ys[i] - slope*xs[i] - intercept)
+		}
+		// Median absolute deviation scaled to be consistent with standard deviation of normal distribution
+		scale := median(residuals) / 0.6745
+		if scale == 0 {
+			break
+		}
+		threshold := huberConstant * scale
+		for i := range xs {
+			if residual := math.Abs(ys[i] - slope*xs[i] - intercept); residual > threshold {
+				weights[i] = threshold / residual
+			} else {
+				weights[i] = 1
+			}
+		}
+		slope, intercept = fitTrend(xs, ys, weights)
+	}
+	return slope, intercept
+}
+
+func median(values []float64) float64 {
+	sorted := make([]float64, len(values))
+	copy(sorted, values)
+	sort.Float64s(sorted)
+	middle := len(sorted) / 2 //nolint
+	if len(sorted)%2 == 0 {
+		return (sorted[middle-1] + sorted[middle]) / 2 //nolint
+	}
+	return sorted[middle]
+}
+
+// ValidateForecastParams checks forecast trigger settings
+func ValidateForecastParams(params *moira.ForecastParams) error {
+	if params == nil {
+		return fmt.Errorf("trigger_type set to forecast, but no forecast params provided")
+	}
+	if params.Lookback <= 0 {
+		return fmt.Errorf("forecast lookback must be positive")
+	}
+	if params.WarnHorizon == nil && params.ErrorHorizon == nil {
+		return fmt.Errorf("at least one of forecast warn_horizon or error_horizon is required")
+	}
+	if params.WarnHorizon != nil && *params.WarnHorizon <= 0 {
+		return fmt.Errorf("forecast warn_horizon must be positive")
+	}
+	if params.ErrorHorizon != nil && *params.ErrorHorizon <= 0 {
+		return fmt.Errorf("forecast error_horizon must be positive")
+	}
+	if params.WarnHorizon != nil && params.ErrorHorizon != nil && *params.WarnHorizon <= *params.ErrorHorizon {
+		return fmt.Errorf("forecast warn_horizon should be greater than error_horizon")
+	}
+	switch params.Method {
+	case "", moira.LinearForecastMethod, moira.RobustForecastMethod:
+	default:
+		return fmt.Errorf("wrong forecast method: %v, allowable values: '%v', '%v'",
+			params.Method, moira.LinearForecastMethod, moira.RobustForecastMethod)
+	}
+	switch params.Direction {
+	case "", moira.RisingTrigger, moira.FallingTrigger:
+	default:
+		return fmt.Errorf("wrong forecast direction: %v, allowable values: '%v', '%v'",
+			params.Direction, moira.RisingTrigger, moira.FallingTrigger)
+	}
+	return nil
+}
+
+func (triggerExpression *TriggerExpression) evaluateForecast() (moira.State, error) {
+	params := triggerExpression.Forecast
+	if err := ValidateForecastParams(params); err != nil {
+		return "", err
+	}
+	if triggerExpression.ForecastTimeLeft == nil {
+		return moira.StateOK, nil
+	}
+
+	timeLeft := *triggerExpression.ForecastTimeLeft
+	if params.ErrorHorizon != nil && timeLeft <= *params.ErrorHorizon {
+		return moira.StateERROR, nil
+	}
+	if params.WarnHorizon != nil && timeLeft <= *params.WarnHorizon {
+		return moira.StateWARN, nil
+	}
+	return moira.StateOK, nil
+}
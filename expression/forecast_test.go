@@ -0,0 +1,95 @@
+package expression
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/moira-alert/moira"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestGetForecastTimestamp(t *testing.T) {
+	timestamps := []int64{0, 60, 120, 180, 240}
+
+	Convey("Test linear trend", t, func() {
+		params := &moira.ForecastParams{Limit: 100}
+		values := []float64{10, 20, 30, 40, 50}
+		forecast, ok := GetForecastTimestamp(timestamps, values, 240, params)
+		So(ok, ShouldBeTrue)
+		So(forecast, ShouldEqual, 240+300)
+
+		params.Direction = moira.FallingTrigger
+		params.Limit = 0
+		_, ok = GetForecastTimestamp(timestamps, values, 240, params)
+		So(ok, ShouldBeFalse)
+
+		params.Limit = 60
+		forecast, ok = GetForecastTimestamp(timestamps, values, 240, params)
+		So(ok, ShouldBeTrue)
+		So(forecast, ShouldEqual, 240)
+	})
+
+	Convey("Test robust trend ignores outlier", t, func() {
+		values := []float64{10, 20, 1000, 40, 50, 60, 70}
+		outlierTimestamps := []int64{0, 60, 120, 180, 240, 300, 360}
+		forecast, ok := GetForecastTimestamp(outlierTimestamps, values, 360, &moira.ForecastParams{Limit: 100, Method: moira.RobustForecastMethod})
+		So(ok, ShouldBeTrue)
+		So(forecast, ShouldAlmostEqual, 360+180, 10)
+
+		linearForecast, _ := GetForecastTimestamp(outlierTimestamps, values, 360, &moira.ForecastParams{Limit: 100, Method: moira.LinearForecastMethod})
+		So(linearForecast, ShouldNotAlmostEqual, 360+180, 60)
+	})
+
+	Convey("Test too few values", t, func() {
+		_, ok := GetForecastTimestamp([]int64{0, 60}, []float64{1, 2}, 60, &moira.ForecastParams{Limit: 100})
+		So(ok, ShouldBeFalse)
+	})
+
+	Convey("Test constant values", t, func() {
+		_, ok := GetForecastTimestamp(timestamps, []float64{5, 5, 5, 5, 5}, 240, &moira.ForecastParams{Limit: 100})
+		So(ok, ShouldBeFalse)
+	})
+}
+
+func TestForecastExpression(t *testing.T) {
+	var warnHorizon int64 = 3600
+	var errorHorizon int64 = 600
+	params := &moira.ForecastParams{Lookback: 3600, Limit: 100, WarnHorizon: &warnHorizon, ErrorHorizon: &errorHorizon}
+
+	Convey("Test horizons", t, func() {
+		result, err := (&TriggerExpression{MainTargetValue: 10, TriggerType: moira.ForecastTrigger, Forecast: params}).Evaluate()
+		So(err, ShouldBeNil)
+		So(result, ShouldResemble, moira.StateOK)
+
+		var timeLeft int64 = 7200
+		result, err = (&TriggerExpression{MainTargetValue: 10, TriggerType: moira.ForecastTrigger, Forecast: params, ForecastTimeLeft: &timeLeft}).Evaluate()
+		So(err, ShouldBeNil)
+		So(result, ShouldResemble, moira.StateOK)
+
+		timeLeft = 3600
+		result, err = (&TriggerExpression{MainTargetValue: 10, TriggerType: moira.ForecastTrigger, Forecast: params, ForecastTimeLeft: &timeLeft}).Evaluate()
+		So(err, ShouldBeNil)
+		So(result, ShouldResemble, moira.StateWARN)
+
+		timeLeft = 0
+		result, err = (&TriggerExpression{MainTargetValue: 10, TriggerType: moira.ForecastTrigger, Forecast: params, ForecastTimeLeft: &timeLeft}).Evaluate()
+		So(err, ShouldBeNil)
+		So(result, ShouldResemble, moira.StateERROR)
+	})
+
+	Convey("Test invalid params", t, func() {
+		result, err := (&TriggerExpression{MainTargetValue: 10, TriggerType: moira.ForecastTrigger}).Evaluate()
+		So(err, ShouldResemble, ErrInvalidExpression{fmt.Errorf("trigger_type set to forecast, but no forecast params provided")})
+		So(result, ShouldBeEmpty)
+
+		inverted := *params
+		inverted.WarnHorizon, inverted.ErrorHorizon = &errorHorizon, &warnHorizon
+		_, err = (&TriggerExpression{MainTargetValue: 10, TriggerType: moira.ForecastTrigger, Forecast: &inverted}).Evaluate()
+		So(err, ShouldResemble, ErrInvalidExpression{fmt.Errorf("forecast warn_horizon should be greater than error_horizon")})
+
+		wrongMethod := *params
+		wrongMethod.Method = "magic"
+		_, err = (&TriggerExpression{MainTargetValue: 10, TriggerType: moira.ForecastTrigger, Forecast: &wrongMethod}).Evaluate()
+		So(err, ShouldNotBeNil)
+	})
+}
@@ -0,0 +1,193 @@
+package expression
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/Knetic/govaluate"
+)
+
+// FunctionDescription describes function available in user expressions
+type FunctionDescription struct {
+	Name        string
+	Signature   string
+	Description string
+}
+
+// expressionFunction represents function available in user expressions
+type expressionFunction struct {
+	call govaluate.ExpressionFunction
+	// Allowed number of arguments, maxArgs is -1 if number of arguments is not limited
+	minArgs int
+	maxArgs int
+	// Window function gets fetched values of target passed as first argument
+	window      bool
+	signature   string
+	description string
+}
+
+var functions = map[string]expressionFunction{
+	"abs":   mathFunction("abs", 1, 1, "abs(x)", "Absolute value of x", func(x []float64) interface{} { return math.Abs(x[0]) }),
+	"ceil":  mathFunction("ceil", 1, 1, "ceil(x)", "Least integer value greater than or equal to x", func(x []float64) interface{} { return math.Ceil(x[0]) }),
+	"floor": mathFunction("floor", 1, 1, "floor(x)", "Greatest integer value less than or equal to x", func(x []float64) interface{} { return math.Floor(x[0]) }),
+	"round": mathFunction("round", 1, 2, "round(x, digits)", "x rounded to given number of decimal digits, to integer if digits are omitted", round),
+	"sqrt":  mathFunction("sqrt", 1, 1, "sqrt(x)", "Square root of x", func(x []float64) interface{} { return math.Sqrt(x[0]) }),
+	"pow":   mathFunction("pow", 2, 2, "pow(x, y)", "x raised to the power of y", func(x []float64) interface{} { return math.Pow(x[0], x[1]) }),
+	"exp":   mathFunction("exp", 1, 1, "exp(x)", "e raised to the power of x", func(x []float64) interface{} { return math.Exp(x[0]) }),
+	"log":   mathFunction("log", 1, 1, "log(x)", "Natural logarithm of x", func(x []float64) interface{} { return math.Log(x[0]) }),
+	"log10": mathFunction("log10", 1, 1, "log10(x)", "Decimal logarithm of x", func(x []float64) interface{} { return math.Log10(x[0]) }),
+	"min":   mathFunction("min", 1, -1, "min(x, y, ...)", "Least of arguments", func(x []float64) interface{} { return minimum(x) }),
+	"max":   mathFunction("max", 1, -1, "max(x, y, ...)", "Greatest of arguments", func(x []float64) interface{} { return maximum(x) }),
+	"isNaN": mathFunction("isNaN", 1, 1, "isNaN(x)", "True if x is not a number, e.g. value_ago of missing point", func(x []float64) interface{} { return math.IsNaN(x[0]) }),
+	"isInf": mathFunction("isInf", 1, 1, "isInf(x)", "True if x is positive or negative infinity", func(x []float64) interface{} { return math.IsInf(x[0], 0) }),
+
+	"avg_over_time": {call: aggregateOverTime("avg_over_time", average), minArgs: 2, maxArgs: 2, window: true,
+		signature: "avg_over_time(t1, seconds)", description: "Average of target values within last seconds"},
+	"min_over_time": {call: aggregateOverTime("min_over_time", minimum), minArgs: 2, maxArgs: 2, window: true,
+		signature: "min_over_time(t1, seconds)", description: "Least of target values within last seconds"},
+	"max_over_time": {call: aggregateOverTime("max_over_time", maximum), minArgs: 2, maxArgs: 2, window: true,
+		signature: "max_over_time(t1, seconds)", description: "Greatest of target values within last seconds"},
+	"count_over_time": {call: aggregateOverTime("count_over_time", count), minArgs: 2, maxArgs: 2, window: true,
+		signature: "count_over_time(t1, seconds)", description: "Number of not empty target values within last seconds"},
+	"percentile_over_time": {call: percentileOverTime, minArgs: 3, maxArgs: 3, window: true,
+		signature: "percentile_over_time(t1, seconds, percentile)", description: "Percentile from 0 to 100 of target values within last seconds"},
+	"value_ago": {call: valueAgo, minArgs: 2, maxArgs: 2, window: true,
+		signature: "value_ago(t1, seconds)", description: "Target value given number of seconds ago"},
+}
+
+// GetFunctionDescriptions returns descriptions of functions available in user expressions sorted by name
+func GetFunctionDescriptions() []FunctionDescription {
+	descriptions := make([]FunctionDescription, 0, len(functions))
+	for name, function := range functions {
+		descriptions = append(descriptions, FunctionDescription{
+			Name:        name,
+			Signature:   function.signature,
+			Description: function.description,
+		})
+	}
+	sort.Slice(descriptions, func(i, j int) bool {
+		return descriptions[i].Name < descriptions[j].Name
+	})
+	return descriptions
+}
+
+func mathFunction(name string, minArgs, maxArgs int, signature, description string, calculate func([]float64) interface{}) expressionFunction {
+	return expressionFunction{
+		call: func(args ...interface{}) (interface{}, error) {
+			values := make([]float64, 0, len(args))
+			for _, arg := range args {
+				value, ok := arg.(float64)
+				if !ok {
+					return nil, fmt.Errorf("function %s expects number arguments", name)
+				}
+				values = append(values, value)
+			}
+			return calculate(values), nil
+		},
+		minArgs:     minArgs,
+		maxArgs:     maxArgs,
+		signature:   signature,
+		description: description,
+	}
+}
+
+func round(x []float64) interface{} {
+	if len(x) == 1 {
+		return math.Round(x[0])
+	}
+	scale := math.Pow(10, math.Trunc(x[1])) //nolint
+	return math.Round(x[0]*scale) / scale
+}
+
+// functionNameStub returns function which returns its name, it identifies function tokens of parsed expression
+func functionNameStub(name string) govaluate.ExpressionFunction {
+	return func(args ...interface{}) (interface{}, error) {
+		return name, nil
+	}
+}
+
+// parseUserExpression parses expression with functions and returns it with
+// maximum number of seconds preceding evaluated timestamp read by window functions.
+// Target passed to window function is replaced with variable resolved to its fetched values
+func parseUserExpression(expression string) (*govaluate.EvaluableExpression, int64, error) {
+	stubs := make(map[string]govaluate.ExpressionFunction, len(functions))
+	for name := range functions {
+		stubs[name] = functionNameStub(name)
+	}
+	parsed, err := govaluate.NewEvaluableExpressionWithFunctions(expression, stubs)
+	if err != nil {
+		if strings.HasPrefix(err.Error(), "Undefined function ") {
+			return nil, 0, fmt.Errorf("unknown function %s", strings.TrimPrefix(err.Error(), "Undefined function "))
+		}
+		return nil, 0, err
+	}
+
+	tokens := parsed.Tokens()
+	var window int64
+	for i, token := range tokens {
+		if token.Kind != govaluate.FUNCTION {
+			continue
+		}
+		name, _ := token.Value.(govaluate.ExpressionFunction)()
+		functionName := name.(string)
+		function := functions[functionName]
+		if err := checkArgumentsCount(functionName, function, countArguments(tokens[i+1:])); err != nil {
+			return nil, 0, err
+		}
+		tokens[i].Value = function.call
+		if !function.window {
+			continue
+		}
+		if !isWindowFunctionCall(tokens[i:]) {
+			return nil, 0, fmt.Errorf("function %s expects target and number of seconds as first arguments, e.g. %s(t1, 600)", functionName, functionName)
+		}
+		tokens[i+2].Value = seriesVariablePrefix + strings.ToLower(tokens[i+2].Value.(string))
+		if seconds := int64(tokens[i+4].Value.(float64)); seconds > window {
+			window = seconds
+		}
+	}
+
+	expr, err := govaluate.NewEvaluableExpressionFromTokens(tokens)
+	if err != nil {
+		return nil, 0, err
+	}
+	return expr, window, nil
+}
+
+// countArguments returns number of arguments of function call, tokens start with clause opening arguments
+func countArguments(tokens []govaluate.ExpressionToken) int {
+	if len(tokens) < 2 || tokens[1].Kind == govaluate.CLAUSE_CLOSE { //nolint
+		return 0
+	}
+	arguments, depth := 1, 0
+	for _, token := range tokens {
+		switch token.Kind {
+		case govaluate.CLAUSE:
+			depth++
+		case govaluate.CLAUSE_CLOSE:
+			depth--
+		case govaluate.SEPARATOR:
+			if depth == 1 {
+				arguments++
+			}
+		}
+		if depth == 0 {
+			break
+		}
+	}
+	return arguments
+}
+
+func checkArgumentsCount(name string, function expressionFunction, arguments int) error {
+	switch {
+	case function.minArgs == function.maxArgs && arguments != function.minArgs:
+		return fmt.Errorf("function %s expects %d arguments, got %d", name, function.minArgs, arguments)
+	case arguments < function.minArgs:
+		return fmt.Errorf("function %s expects at least %d arguments, got %d", name, function.minArgs, arguments)
+	case function.maxArgs >= 0 && arguments > function.maxArgs:
+		return fmt.Errorf("function %s expects at most %d arguments, got %d", name, function.maxArgs, arguments)
+	}
+	return nil
+}
@@ -0,0 +1,162 @@
+package expression
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/Knetic/govaluate"
+)
+
+// seriesVariablePrefix marks variables resolved to fetched values of target, they are passed to window functions
+const seriesVariablePrefix = "series:"
+
+// Series represents fetched values of trigger target read by window functions
+type Series struct {
+	StartTime int64
+	StepTime  int64
+	Values    []float64
+}
+
+// seriesWindow represents target values preceding evaluated timestamp
+type seriesWindow struct {
+	Series
+	timestamp int64
+}
+
+// valuesOver returns not empty values within period seconds until evaluated timestamp
+func (window seriesWindow) valuesOver(period int64) []float64 {
+	values := make([]float64, 0)
+	if window.StepTime <= 0 {
+		return values
+	}
+	for i, value := range window.Values {
+		timestamp := window.StartTime + int64(i)*window.StepTime
+		if timestamp <= window.timestamp-period || timestamp > window.timestamp {
+			continue
+		}
+		if !math.IsNaN(value) {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// valueAgo returns value of point containing timestamp seconds seconds before evaluated timestamp
+func (window seriesWindow) valueAgo(seconds int64) float64 {
+	timestamp := window.timestamp - seconds
+	if window.StepTime <= 0 || timestamp < window.StartTime {
+		return math.NaN()
+	}
+	index := (timestamp - window.StartTime) / window.StepTime
+	if index >= int64(len(window.Values)) {
+		return math.NaN()
+	}
+	return window.Values[index]
+}
+
+func aggregateOverTime(name string, aggregate func([]float64) float64) govaluate.ExpressionFunction {
+	return func(args ...interface{}) (interface{}, error) {
+		window, period, err := getWindowArguments(name, args)
+		if err != nil {
+			return nil, err
+		}
+		return aggregate(window.valuesOver(period)), nil
+	}
+}
+
+func percentileOverTime(args ...interface{}) (interface{}, error) {
+	const name = "percentile_over_time"
+	window, period, err := getWindowArguments(name, args)
+	if err != nil {
+		return nil, err
+	}
+	rank, ok := args[2].(float64)
+	if !ok || rank < 0 || rank > 100 {
+		return nil, fmt.Errorf("function %s expects percentile from 0 to 100 as third argument", name)
+	}
+	return percentile(window.valuesOver(period), rank), nil
+}
+
+func valueAgo(args ...interface{}) (interface{}, error) {
+	const name = "value_ago"
+	window, seconds, err := getWindowArguments(name, args)
+	if err != nil {
+		return nil, err
+	}
+	return window.valueAgo(seconds), nil
+}
+
+func getWindowArguments(name string, args []interface{}) (seriesWindow, int64, error) {
+	window, ok := args[0].(seriesWindow)
+	if !ok {
+		return seriesWindow{}, 0, fmt.Errorf("function %s expects target as first argument", name)
+	}
+	seconds, ok := args[1].(float64)
+	if !ok {
+		return seriesWindow{}, 0, fmt.Errorf("function %s expects number of seconds as second argument", name)
+	}
+	return window, int64(seconds), nil
+}
+
+func average(values []float64) float64 {
+	if len(values) == 0 {
+		return math.NaN()
+	}
+	var sum float64
+	for _, value := range values {
+		sum += value
+	}
+	return sum / float64(len(values))
+}
+
+func minimum(values []float64) float64 {
+	if len(values) == 0 {
+		return math.NaN()
+	}
+	result := values[0]
+	for _, value := range values[1:] {
+		result = math.Min(result, value)
+	}
+	return result
+}
+
+func maximum(values []float64) float64 {
+	if len(values) == 0 {
+		return math.NaN()
+	}
+	result := values[0]
+	for _, value := range values[1:] {
+		result = math.Max(result, value)
+	}
+	return result
+}
+
+func count(values []float64) float64 {
+	return float64(len(values))
+}
+
+// percentile returns rank percentile of values using linear interpolation between closest ranks
+func percentile(values []float64, rank float64) float64 {
+	if len(values) == 0 {
+		return math.NaN()
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	position := rank / 100 * float64(len(sorted)-1) //nolint
+	lower := int(math.Floor(position))
+	upper := int(math.Ceil(position))
+	return sorted[lower] + (sorted[upper]-sorted[lower])*(position-float64(lower))
+}
+
+// isWindowFunctionCall checks that function call tokens start with target variable and number literal arguments
+func isWindowFunctionCall(tokens []govaluate.ExpressionToken) bool {
+	if len(tokens) < 6 { //nolint
+		return false
+	}
+	return tokens[1].Kind == govaluate.CLAUSE &&
+		tokens[2].Kind == govaluate.VARIABLE &&
+		tokens[3].Kind == govaluate.SEPARATOR &&
+		tokens[4].Kind == govaluate.NUMERIC &&
+		(tokens[5].Kind == govaluate.CLAUSE_CLOSE || tokens[5].Kind == govaluate.SEPARATOR)
+}
@@ -0,0 +1,155 @@
+package expression
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/moira-alert/moira"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestSeriesWindow(t *testing.T) {
+	Convey("Test series window", t, func() {
+		window := seriesWindow{
+			Series:    Series{StartTime: 0, StepTime: 60, Values: []float64{10, 20, math.NaN(), 40, 50, 60, 70, 80, 90, 100}},
+			timestamp: 540,
+		}
+
+		So(window.valuesOver(300), ShouldResemble, []float64{60, 70, 80, 90, 100})
+		So(window.valuesOver(600), ShouldResemble, []float64{10, 20, 40, 50, 60, 70, 80, 90, 100})
+		So(window.valueAgo(0), ShouldEqual, 100)
+		So(window.valueAgo(90), ShouldEqual, 80)
+		So(math.IsNaN(window.valueAgo(420)), ShouldBeTrue)
+		So(math.IsNaN(window.valueAgo(600)), ShouldBeTrue)
+
+		window.timestamp = 900
+		So(window.valuesOver(300), ShouldBeEmpty)
+		So(math.IsNaN(window.valueAgo(0)), ShouldBeTrue)
+	})
+
+	Convey("Test aggregations", t, func() {
+		So(average([]float64{1, 2, 6}), ShouldEqual, 3)
+		So(minimum([]float64{4, 2, 6}), ShouldEqual, 2)
+		So(maximum([]float64{4, 2, 6}), ShouldEqual, 6)
+		So(count([]float64{4, 2, 6}), ShouldEqual, 3)
+		So(percentile([]float64{100, 60, 80, 70, 90}, 50), ShouldEqual, 80)
+		So(percentile([]float64{100, 60, 80, 70, 90}, 90), ShouldAlmostEqual, 96)
+		So(percentile([]float64{100, 60, 80, 70, 90}, 100), ShouldEqual, 100)
+		So(math.IsNaN(average(nil)), ShouldBeTrue)
+		So(math.IsNaN(minimum(nil)), ShouldBeTrue)
+		So(math.IsNaN(maximum(nil)), ShouldBeTrue)
+		So(math.IsNaN(percentile(nil, 50)), ShouldBeTrue)
+		So(count(nil), ShouldEqual, 0)
+	})
+}
+
+func TestWindowFunctions(t *testing.T) {
+	series := map[string]Series{
+		"t1": {StartTime: 0, StepTime: 60, Values: []float64{10, 20, math.NaN(), 40, 50, 60, 70, 80, 90, 100}},
+		"t2": {StartTime: 0, StepTime: 60, Values: []float64{0, 0, 0, 0, 0, 0, 0, 0, 200, 0}},
+	}
+	evaluate := func(expression string) (moira.State, error) {
+		return (&TriggerExpression{
+			Expression:              &expression,
+			MainTargetValue:         100,
+			AdditionalTargetsValues: map[string]float64{"t2": 0},
+			TriggerType:             moira.ExpressionTrigger,
+			Series:                  series,
+			Timestamp:               540,
+		}).Evaluate()
+	}
+
+	Convey("Test window functions", t, func() {
+		result, err := evaluate("avg_over_time(t1, 300) > 75 ? ERROR : OK")
+		So(err, ShouldBeNil)
+		So(result, ShouldEqual, moira.StateERROR)
+
+		result, err = evaluate("min_over_time(t1, 600) < 15 && max_over_time(t1, 600) > 95 ? WARN : OK")
+		So(err, ShouldBeNil)
+		So(result, ShouldEqual, moira.StateWARN)
+
+		result, err = evaluate("count_over_time(t1, 600) == 9 ? ERROR : OK")
+		So(err, ShouldBeNil)
+		So(result, ShouldEqual, moira.StateERROR)
+
+		result, err = evaluate("percentile_over_time(t1, 300, 90) > 95 ? ERROR : OK")
+		So(err, ShouldBeNil)
+		So(result, ShouldEqual, moira.StateERROR)
+
+		result, err = evaluate("t1 - value_ago(t1, 60) > 5 ? WARN : OK")
+		So(err, ShouldBeNil)
+		So(result, ShouldEqual, moira.StateWARN)
+
+		result, err = evaluate("max_over_time(T2, 300) > t1 ? ERROR : OK")
+		So(err, ShouldBeNil)
+		So(result, ShouldEqual, moira.StateERROR)
+
+		result, err = evaluate("value_ago(t1, 3600) > 0 ? ERROR : OK")
+		So(err, ShouldBeNil)
+		So(result, ShouldEqual, moira.StateOK)
+	})
+
+	Convey("Test window functions errors", t, func() {
+		result, err := evaluate("avg_over_time(t1 * 2, 300) > 1 ? ERROR : OK")
+		So(err, ShouldResemble, ErrInvalidExpression{fmt.Errorf("function avg_over_time expects target and number of seconds as first arguments, e.g. avg_over_time(t1, 600)")})
+		So(result, ShouldBeEmpty)
+
+		result, err = evaluate("avg_over_time(t1, 100 + 200) > 1 ? ERROR : OK")
+		So(err, ShouldResemble, ErrInvalidExpression{fmt.Errorf("function avg_over_time expects target and number of seconds as first arguments, e.g. avg_over_time(t1, 600)")})
+		So(result, ShouldBeEmpty)
+
+		result, err = evaluate("avg_over_time(t3, 300) > 1 ? ERROR : OK")
+		So(err, ShouldResemble, ErrInvalidExpression{fmt.Errorf("no value with name t3")})
+		So(result, ShouldBeEmpty)
+
+		result, err = evaluate("avg_over_time(t1, 300, 10) > 1 ? ERROR : OK")
+		So(err, ShouldResemble, ErrInvalidExpression{fmt.Errorf("function avg_over_time expects 2 arguments, got 3")})
+		So(result, ShouldBeEmpty)
+
+		result, err = evaluate("percentile_over_time(t1, 300, 150) > 1 ? ERROR : OK")
+		So(err, ShouldResemble, ErrInvalidExpression{fmt.Errorf("function percentile_over_time expects percentile from 0 to 100 as third argument")})
+		So(result, ShouldBeEmpty)
+	})
+}
+
+func TestGetWindow(t *testing.T) {
+	Convey("Test get window", t, func() {
+		window, err := GetWindow("t1 > 10 ? ERROR : OK")
+		So(err, ShouldBeNil)
+		So(window, ShouldEqual, 0)
+
+		window, err = GetWindow("avg_over_time(t1, 300) > 10 && value_ago(t2, 900) > 10 ? ERROR : OK")
+		So(err, ShouldBeNil)
+		So(window, ShouldEqual, 900)
+
+		_, err = GetWindow("t1 > ")
+		So(err, ShouldNotBeNil)
+	})
+}
+
+func TestGetTriggerWindow(t *testing.T) {
+	Convey("Test get trigger window", t, func() {
+		triggerExpression := "avg_over_time(t1, 600) > 90 ? ERROR : OK"
+
+		window, err := GetTriggerWindow(&moira.Trigger{TriggerType: moira.RisingTrigger})
+		So(err, ShouldBeNil)
+		So(window, ShouldEqual, 0)
+
+		window, err = GetTriggerWindow(&moira.Trigger{TriggerType: moira.ExpressionTrigger, Expression: &triggerExpression})
+		So(err, ShouldBeNil)
+		So(window, ShouldEqual, 600)
+
+		window, err = GetTriggerWindow(&moira.Trigger{
+			TriggerType: moira.ExpressionTrigger,
+			Expression:  &triggerExpression,
+			Overrides:   []moira.ThresholdOverride{{Pattern: "big.*", Expression: "value_ago(t1, 1800) > t1 ? WARN : OK"}},
+		})
+		So(err, ShouldBeNil)
+		So(window, ShouldEqual, 1800)
+
+		invalidExpression := "avg_over_time(t1) > "
+		_, err = GetTriggerWindow(&moira.Trigger{TriggerType: moira.ExpressionTrigger, Expression: &invalidExpression})
+		So(err, ShouldHaveSameTypeAs, ErrInvalidExpression{})
+	})
+}
@@ -5,4 +5,24 @@ type Config struct {
 	Enabled         bool
 	Listen          string
 	RetentionConfig string
+	TLS             TLSConfig
+	// Rules to rewrite metrics before pattern matching, applied in order of declaration
+	RewriteRules []RewriteRule
+	// Limits of distinct metrics per pattern and points rate per metric prefix
+	Limits LimitsConfig
+	// Acceptance window for point timestamps and action for points out of it
+	TimestampPolicy TimestampPolicyConfig
+}
+
+// TLSConfig is metrics listener TLS settings
+type TLSConfig struct {
+	Enabled bool
+	// Server certificate and private key in PEM format
+	CertFile string
+	KeyFile  string
+	// CA certificates to verify client certificates with. Client certificates are not required if empty
+	ClientCAFile string
+	// Metric prefixes allowed for clients by certificate common name, requires ClientCAFile.
+	// Prefix matches whole metric name nodes. Clients which are not listed can't send any metrics
+	AllowedPrefixes map[string][]string
 }
@@ -2,28 +2,79 @@ package connection
 
 import (
 	"bufio"
+	"crypto/tls"
 	"io"
 	"net"
 	"sync"
 
 	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/filter"
+	"github.com/moira-alert/moira/metrics"
 )
 
+// readLinesFunc reads next portion of metric lines from connection buffer
+type readLinesFunc func(buffer *bufio.Reader) ([][]byte, error)
+
+// readParsedMetricsFunc reads next portion of metrics from connection buffer and parses them
+type readParsedMetricsFunc func(buffer *bufio.Reader) ([]*filter.ParsedMetric, error)
+
+// invalidLineError is returned by readLinesFunc if read data can not be converted to metric lines,
+// but connection can still be used to read next lines
+type invalidLineError struct {
+	err error
+}
+
+func (err invalidLineError) Error() string {
+	return err.err.Error()
+}
+
 // Handler handling connection data and shift it to lineChan channel
 type Handler struct {
 	logger    moira.Logger
 	wg        sync.WaitGroup
 	terminate chan struct{}
+	received  metrics.Counter
+	readLines readLinesFunc
+	// readParsedMetrics is set instead of readLines by handlers of protocols which are parsed while reading
+	readParsedMetrics readParsedMetricsFunc
+	// Metric prefixes allowed for TLS clients by certificate common name
+	allowedPrefixes map[string][]string
+	forbidden       metrics.Counter
+}
+
+// NewConnectionsHandler creates new Handler for graphite plaintext protocol connections
+func NewConnectionsHandler(logger moira.Logger, received metrics.Counter) *Handler {
+	return newConnectionsHandler(logger, received, readPlaintextLines)
+}
+
+// NewInfluxConnectionsHandler creates new Handler for InfluxDB line protocol connections,
+// received metrics are sent already parsed, so connections must be handled by HandleParsedConnection
+func NewInfluxConnectionsHandler(logger moira.Logger, received metrics.Counter) *Handler {
+	handler := newConnectionsHandler(logger, received, nil)
+	handler.readParsedMetrics = readInfluxMetrics
+	return handler
+}
+
+// NewPickleConnectionsHandler creates new Handler for graphite pickle protocol connections
+func NewPickleConnectionsHandler(logger moira.Logger, received metrics.Counter) *Handler {
+	return newConnectionsHandler(logger, received, readPickleLines)
 }
 
-// NewConnectionsHandler creates new Handler
-func NewConnectionsHandler(logger moira.Logger) *Handler {
+func newConnectionsHandler(logger moira.Logger, received metrics.Counter, readLines readLinesFunc) *Handler {
 	return &Handler{
 		logger:    logger,
 		terminate: make(chan struct{}, 1),
+		received:  received,
+		readLines: readLines,
 	}
 }
 
+// restrictPrefixes makes handler drop metrics outside of allowed prefixes of TLS client certificate
+func (handler *Handler) restrictPrefixes(allowedPrefixes map[string][]string, forbidden metrics.Counter) {
+	handler.allowedPrefixes = allowedPrefixes
+	handler.forbidden = forbidden
+}
+
 // HandleConnection convert every line from connection to metric and send it to lineChan channel
 func (handler *Handler) HandleConnection(connection net.Conn, lineChan chan<- []byte) {
 	handler.wg.Add(1)
@@ -33,7 +84,53 @@ func (handler *Handler) HandleConnection(connection net.Conn, lineChan chan<- []
 	}()
 }
 
+// HandleParsedConnection parses every line from connection to metrics and sends them to parsedMetricsChan channel
+func (handler *Handler) HandleParsedConnection(connection net.Conn, parsedMetricsChan chan<- *filter.ParsedMetric) {
+	handler.wg.Add(1)
+	go func() {
+		defer handler.wg.Done()
+		handler.handleParsed(connection, parsedMetricsChan)
+	}()
+}
+
 func (handler *Handler) handle(connection net.Conn, lineChan chan<- []byte) {
+	handler.handleWith(connection, func(buffer *bufio.Reader, allowed prefixFilter) error {
+		lines, err := handler.readLines(buffer)
+		if err != nil {
+			return err
+		}
+		for _, line := range lines {
+			handler.received.Inc()
+			if !allowed.isAllowed(line) {
+				handler.forbidden.Inc()
+				continue
+			}
+			lineChan <- line
+		}
+		return nil
+	})
+}
+
+func (handler *Handler) handleParsed(connection net.Conn, parsedMetricsChan chan<- *filter.ParsedMetric) {
+	handler.handleWith(connection, func(buffer *bufio.Reader, allowed prefixFilter) error {
+		parsedMetrics, err := handler.readParsedMetrics(buffer)
+		if err != nil {
+			return err
+		}
+		for _, parsedMetric := range parsedMetrics {
+			handler.received.Inc()
+			if !allowed.isAllowed([]byte(parsedMetric.Metric)) {
+				handler.forbidden.Inc()
+				continue
+			}
+			parsedMetricsChan <- parsedMetric
+		}
+		return nil
+	})
+}
+
+// handleWith reads connection by given function until connection is closed or can't be read
+func (handler *Handler) handleWith(connection net.Conn, read func(buffer *bufio.Reader, allowed prefixFilter) error) {
 	buffer := bufio.NewReader(connection)
 	closeConnection := make(chan struct{})
 	go func(conn net.Conn) {
@@ -44,8 +141,21 @@ func (handler *Handler) handle(connection net.Conn, lineChan chan<- []byte) {
 		}
 	}(connection)
 
+	allowed, err := handler.getPrefixFilter(connection)
+	if err != nil {
+		connection.Close()
+		handler.logger.Errorf("Fail to establish metric connection with %s: %s", connection.RemoteAddr(), err)
+		close(closeConnection)
+		return
+	}
+
 	for {
-		bytes, err := buffer.ReadBytes('\n')
+		err := read(buffer, allowed)
+		if invalidLine, ok := err.(invalidLineError); ok {
+			handler.received.Inc()
+			handler.logger.Infof("Cannot parse metric line: %s", invalidLine)
+			continue
+		}
 		if err != nil {
 			connection.Close()
 			if err != io.EOF {
@@ -54,19 +164,47 @@ func (handler *Handler) handle(connection net.Conn, lineChan chan<- []byte) {
 			close(closeConnection)
 			return
 		}
-		bytesWithoutCRLF := dropCRLF(bytes)
-		if len(bytesWithoutCRLF) > 0 {
-			lineChan <- bytesWithoutCRLF
-		}
 	}
 }
 
+// getPrefixFilter completes TLS handshake and returns metric prefixes allowed for client certificate.
+// Clients without certificate or with certificate which is not listed in allowed prefixes can't send any metrics
+func (handler *Handler) getPrefixFilter(connection net.Conn) (prefixFilter, error) {
+	tlsConnection, ok := connection.(*tls.Conn)
+	if !ok {
+		return nil, nil
+	}
+	if err := tlsConnection.Handshake(); err != nil {
+		return nil, err
+	}
+	if handler.allowedPrefixes == nil {
+		return nil, nil
+	}
+	certificates := tlsConnection.ConnectionState().PeerCertificates
+	if len(certificates) == 0 {
+		return newPrefixFilter(nil), nil
+	}
+	return newPrefixFilter(handler.allowedPrefixes[certificates[0].Subject.CommonName]), nil
+}
+
 // StopHandlingConnections closes all open connections and wait for handling remaining metrics
 func (handler *Handler) StopHandlingConnections() {
 	close(handler.terminate)
 	handler.wg.Wait()
 }
 
+func readPlaintextLines(buffer *bufio.Reader) ([][]byte, error) {
+	bytes, err := buffer.ReadBytes('\n')
+	if err != nil {
+		return nil, err
+	}
+	bytesWithoutCRLF := dropCRLF(bytes)
+	if len(bytesWithoutCRLF) == 0 {
+		return nil, nil
+	}
+	return [][]byte{bytesWithoutCRLF}, nil
+}
+
 func dropCRLF(bytes []byte) []byte {
 	bytesLength := len(bytes)
 	if bytesLength > 0 && bytes[bytesLength-1] == '\n' {
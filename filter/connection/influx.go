@@ -0,0 +1,27 @@
+package connection
+
+import (
+	"bufio"
+
+	"github.com/moira-alert/moira/filter"
+)
+
+// readInfluxMetrics reads one InfluxDB line protocol line and parses every its field to separate metric
+func readInfluxMetrics(buffer *bufio.Reader) ([]*filter.ParsedMetric, error) {
+	bytes, err := buffer.ReadBytes('\n')
+	if err != nil {
+		return nil, err
+	}
+	return parseInfluxLine(dropCRLF(bytes))
+}
+
+func parseInfluxLine(line []byte) ([]*filter.ParsedMetric, error) {
+	if len(line) == 0 || line[0] == '#' {
+		return nil, nil
+	}
+	parsedMetrics, err := filter.ParseInfluxLine(line)
+	if err != nil {
+		return nil, invalidLineError{err: err}
+	}
+	return parsedMetrics, nil
+}
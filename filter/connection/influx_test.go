@@ -0,0 +1,63 @@
+package connection
+
+import (
+	"bufio"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/moira-alert/moira/filter"
+	logging "github.com/moira-alert/moira/logging/zerolog_adapter"
+	"github.com/moira-alert/moira/metrics"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestReadInfluxMetrics(t *testing.T) {
+	Convey("Should parse every field of InfluxDB line to separate metric", t, func() {
+		buffer := bufio.NewReader(strings.NewReader("cpu,host=a idle=10,busy=2i 1465839830100400200\r\n"))
+		parsedMetrics, err := readInfluxMetrics(buffer)
+		So(err, ShouldBeNil)
+		So(parsedMetrics, ShouldResemble, []*filter.ParsedMetric{
+			{Metric: "cpu.idle;host=a", Name: "cpu.idle", Labels: map[string]string{"host": "a"}, Value: 10, Timestamp: 1465839830},
+			{Metric: "cpu.busy;host=a", Name: "cpu.busy", Labels: map[string]string{"host": "a"}, Value: 2, Timestamp: 1465839830},
+		})
+	})
+
+	Convey("Should skip empty and comment lines", t, func() {
+		buffer := bufio.NewReader(strings.NewReader("\n# comment\n"))
+		for i := 0; i < 2; i++ {
+			parsedMetrics, err := readInfluxMetrics(buffer)
+			So(err, ShouldBeNil)
+			So(parsedMetrics, ShouldBeEmpty)
+		}
+	})
+
+	Convey("Should return invalid line error and keep reading next lines", t, func() {
+		buffer := bufio.NewReader(strings.NewReader("cpu idle\ncpu idle=1 1465839830000000000\n"))
+		_, err := readInfluxMetrics(buffer)
+		So(err, ShouldHaveSameTypeAs, invalidLineError{})
+		parsedMetrics, err := readInfluxMetrics(buffer)
+		So(err, ShouldBeNil)
+		So(parsedMetrics, ShouldResemble, []*filter.ParsedMetric{
+			{Metric: "cpu.idle", Name: "cpu.idle", Labels: map[string]string{}, Value: 1, Timestamp: 1465839830},
+		})
+	})
+}
+
+func TestInfluxConnectionsHandler(t *testing.T) {
+	logger, _ := logging.GetLogger("Test")
+	filterMetrics := metrics.ConfigureFilterMetrics(metrics.NewDummyRegistry())
+
+	Convey("Parsed metrics of connection are sent to channel", t, func() {
+		handler := NewInfluxConnectionsHandler(logger, filterMetrics.InfluxMetricsReceived)
+		parsedMetricsChan := make(chan *filter.ParsedMetric, 2)
+		server, client := net.Pipe()
+		handler.HandleParsedConnection(server, parsedMetricsChan)
+		client.Write([]byte("cpu idle=10,busy=2 1465839830000000000\n")) //nolint
+		client.Close()
+		handler.StopHandlingConnections()
+		So(len(parsedMetricsChan), ShouldEqual, 2)
+		So((<-parsedMetricsChan).Metric, ShouldEqual, "cpu.idle")
+		So((<-parsedMetricsChan).Metric, ShouldEqual, "cpu.busy")
+	})
+}
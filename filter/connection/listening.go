@@ -1,6 +1,7 @@
 package connection
 
 import (
+	"crypto/tls"
 	"fmt"
 	"net"
 	"time"
@@ -8,20 +9,56 @@ import (
 	"gopkg.in/tomb.v2"
 
 	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/filter"
 	"github.com/moira-alert/moira/metrics"
 )
 
 // MetricsListener is facade for standard net.MetricsListener and accept connection for handling it
 type MetricsListener struct {
-	listener *net.TCPListener
-	handler  *Handler
-	logger   moira.Logger
-	tomb     tomb.Tomb
-	metrics  *metrics.FilterMetrics
+	listener  *net.TCPListener
+	tlsConfig *tls.Config
+	handler   *Handler
+	logger    moira.Logger
+	tomb      tomb.Tomb
+	metrics   *metrics.FilterMetrics
 }
 
-// NewListener creates new listener
+// NewListener creates new listener for graphite plaintext protocol
 func NewListener(port string, logger moira.Logger, metrics *metrics.FilterMetrics) (*MetricsListener, error) {
+	return newListener(port, logger, metrics, NewConnectionsHandler(logger, metrics.TCPMetricsReceived))
+}
+
+// NewTLSListener creates new listener for graphite plaintext protocol over TLS
+// Metrics outside of prefixes allowed for client certificate are dropped
+func NewTLSListener(port string, config filter.TLSConfig, logger moira.Logger, metrics *metrics.FilterMetrics) (*MetricsListener, error) {
+	tlsConfig, err := NewServerTLSConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	handler := NewConnectionsHandler(logger, metrics.TCPMetricsReceived)
+	if len(config.AllowedPrefixes) > 0 {
+		handler.restrictPrefixes(config.AllowedPrefixes, metrics.ForbiddenMetricsReceived)
+	}
+	listener, err := newListener(port, logger, metrics, handler)
+	if err != nil {
+		return nil, err
+	}
+	listener.tlsConfig = tlsConfig
+	return listener, nil
+}
+
+// NewPickleListener creates new listener for graphite pickle protocol
+func NewPickleListener(port string, logger moira.Logger, metrics *metrics.FilterMetrics) (*MetricsListener, error) {
+	return newListener(port, logger, metrics, NewPickleConnectionsHandler(logger, metrics.PickleMetricsReceived))
+}
+
+// NewInfluxListener creates new listener for InfluxDB line protocol, it must be started by ListenParsed
+// Every field of received line is sent further as separate parsed metric "<measurement>.<field>" tagged with line tags
+func NewInfluxListener(port string, logger moira.Logger, metrics *metrics.FilterMetrics) (*MetricsListener, error) {
+	return newListener(port, logger, metrics, NewInfluxConnectionsHandler(logger, metrics.InfluxMetricsReceived))
+}
+
+func newListener(port string, logger moira.Logger, metrics *metrics.FilterMetrics, handler *Handler) (*MetricsListener, error) {
 	address, err := net.ResolveTCPAddr("tcp", port)
 	if nil != err {
 		return nil, fmt.Errorf("failed to resolve tcp address [%s]: %s", port, err.Error())
@@ -33,7 +70,7 @@ func NewListener(port string, logger moira.Logger, metrics *metrics.FilterMetric
 	listener := MetricsListener{
 		listener: newListener,
 		logger:   logger,
-		handler:  NewConnectionsHandler(logger),
+		handler:  handler,
 		metrics:  metrics,
 	}
 	return &listener, nil
@@ -43,6 +80,27 @@ func NewListener(port string, logger moira.Logger, metrics *metrics.FilterMetric
 // All handled data sets to lineChan
 func (listener *MetricsListener) Listen() chan []byte {
 	lineChan := make(chan []byte, 16384) //nolint
+	listener.serve(func(conn net.Conn) { listener.handler.HandleConnection(conn, lineChan) }, func() { close(lineChan) })
+	listener.tomb.Go(func() error { return listener.checkNewLinesChannelLen(lineChan) })
+	return lineChan
+}
+
+// ListenParsed waits for new data in connection and sends metrics parsed by ConnectionHandler to returned channel.
+// Channel is closed on Stop
+func (listener *MetricsListener) ListenParsed() <-chan *filter.ParsedMetric {
+	parsedMetricsChan := make(chan *filter.ParsedMetric, 16384) //nolint
+	listener.serve(func(conn net.Conn) { listener.handler.HandleParsedConnection(conn, parsedMetricsChan) }, func() { close(parsedMetricsChan) })
+	return parsedMetricsChan
+}
+
+// ListenTo waits for new data in connection and sends all handled data to lineChan of another listener
+// lineChan is not closed on Stop, so this listener must be stopped before the one which owns lineChan
+func (listener *MetricsListener) ListenTo(lineChan chan<- []byte) {
+	listener.serve(func(conn net.Conn) { listener.handler.HandleConnection(conn, lineChan) }, func() {})
+}
+
+// serve accepts connections and passes them to handleConnection, onStop is called after all connections are handled
+func (listener *MetricsListener) serve(handleConnection func(conn net.Conn), onStop func()) {
 	listener.tomb.Go(func() error {
 		for {
 			select {
@@ -51,7 +109,7 @@ func (listener *MetricsListener) Listen() chan []byte {
 					listener.logger.Info("Stopping listener...")
 					listener.listener.Close()
 					listener.handler.StopHandlingConnections()
-					close(lineChan)
+					onStop()
 					listener.logger.Info("Moira Filter Listener stopped")
 					return nil
 				}
@@ -67,12 +125,13 @@ func (listener *MetricsListener) Listen() chan []byte {
 				continue
 			}
 			listener.logger.Infof("%s connected", conn.RemoteAddr())
-			listener.handler.HandleConnection(conn, lineChan)
+			if listener.tlsConfig != nil {
+				conn = tls.Server(conn, listener.tlsConfig)
+			}
+			handleConnection(conn)
 		}
 	})
-	listener.tomb.Go(func() error { return listener.checkNewLinesChannelLen(lineChan) })
-	listener.logger.Info("Moira Filter Listener Started")
-	return lineChan
+	listener.logger.Infof("Moira Filter Listener Started on %s", listener.listener.Addr())
 }
 
 func (listener *MetricsListener) checkNewLinesChannelLen(channel <-chan []byte) error {
@@ -0,0 +1,96 @@
+package connection
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math/big"
+	"strconv"
+
+	ogorek "github.com/lomik/og-rek"
+)
+
+// maxPickleMessageSize is the same limit carbon uses for pickle protocol messages
+const maxPickleMessageSize = 1 << 20
+
+// readPickleLines reads one length-prefixed pickle message and converts it to graphite plaintext lines
+// Message must contain list of (metric, (timestamp, value)) tuples
+func readPickleLines(buffer *bufio.Reader) ([][]byte, error) {
+	var messageSize uint32
+	if err := binary.Read(buffer, binary.BigEndian, &messageSize); err != nil {
+		return nil, err
+	}
+	if messageSize > maxPickleMessageSize {
+		return nil, fmt.Errorf("pickle message is too big: %d bytes", messageSize)
+	}
+	message := make([]byte, messageSize)
+	if _, err := io.ReadFull(buffer, message); err != nil {
+		return nil, err
+	}
+	return parsePickleMessage(message)
+}
+
+func parsePickleMessage(message []byte) ([][]byte, error) {
+	decoded, err := ogorek.NewDecoder(bytes.NewReader(message)).Decode()
+	if err != nil {
+		return nil, fmt.Errorf("cannot unpickle message: %s", err)
+	}
+	items, ok := pickleSequence(decoded)
+	if !ok {
+		return nil, fmt.Errorf("pickle message is not a list: %T", decoded)
+	}
+
+	lines := make([][]byte, 0, len(items))
+	for _, item := range items {
+		metricTuple, ok := pickleSequence(item)
+		if !ok || len(metricTuple) != 2 { //nolint
+			return nil, fmt.Errorf("unexpected pickle metric item: %v", item)
+		}
+		metric, ok := metricTuple[0].(string)
+		if !ok {
+			return nil, fmt.Errorf("unexpected pickle metric name: %v", metricTuple[0])
+		}
+		point, ok := pickleSequence(metricTuple[1])
+		if !ok || len(point) != 2 { //nolint
+			return nil, fmt.Errorf("unexpected pickle metric point: %v", metricTuple[1])
+		}
+		timestamp, err := formatPickleNumber(point[0])
+		if err != nil {
+			return nil, fmt.Errorf("unexpected timestamp of metric %s: %s", metric, err)
+		}
+		value, err := formatPickleNumber(point[1])
+		if err != nil {
+			return nil, fmt.Errorf("unexpected value of metric %s: %s", metric, err)
+		}
+		lines = append(lines, []byte(metric+" "+value+" "+timestamp))
+	}
+	return lines, nil
+}
+
+func pickleSequence(item interface{}) ([]interface{}, bool) {
+	switch sequence := item.(type) {
+	case []interface{}:
+		return sequence, true
+	case ogorek.Tuple:
+		return sequence, true
+	default:
+		return nil, false
+	}
+}
+
+func formatPickleNumber(number interface{}) (string, error) {
+	switch value := number.(type) {
+	case int64:
+		return strconv.FormatInt(value, 10), nil //nolint
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64), nil //nolint
+	case *big.Int:
+		return value.String(), nil
+	case string:
+		return value, nil
+	default:
+		return "", fmt.Errorf("not a number: %v", number)
+	}
+}
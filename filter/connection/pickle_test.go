@@ -0,0 +1,77 @@
+package connection
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"io"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestParsePickleMessage(t *testing.T) {
+	expectedLines := [][]byte{
+		[]byte("One.two.three 1.5 1234567890"),
+		[]byte("Four.five;tag=value 42 1234567890.5"),
+	}
+
+	Convey("Should parse pickle protocol 0 message", t, func() {
+		message := []byte("(lp0\n(VOne.two.three\np1\n(I1234567890\nF1.5\ntp2\ntp3\na(VFour.five;tag=value\np4\n(F1234567890.5\nI42\ntp5\ntp6\na.")
+		lines, err := parsePickleMessage(message)
+		So(err, ShouldBeNil)
+		So(lines, ShouldResemble, expectedLines)
+	})
+
+	Convey("Should parse pickle protocol 2 message", t, func() {
+		message := []byte("\x80\x02\x5d\x71\x00\x28\x58\x0d\x00\x00\x00One.two.three\x71\x01\x4a\xd2\x02\x96\x49\x47\x3f\xf8" +
+			"\x00\x00\x00\x00\x00\x00\x86\x71\x02\x86\x71\x03\x58\x13\x00\x00\x00Four.five;tag=value\x71\x04\x47\x41\xd2\x65" +
+			"\x80\xb4\xa0\x00\x00\x4b\x2a\x86\x71\x05\x86\x71\x06\x65\x2e")
+		lines, err := parsePickleMessage(message)
+		So(err, ShouldBeNil)
+		So(lines, ShouldResemble, expectedLines)
+	})
+
+	Convey("Should return error on unexpected message structure", t, func() {
+		invalidMessages := []string{
+			"",
+			"garbage",
+			"I42\n.",
+			"(lp0\nI42\na.",
+			"(lp0\n(VOne.two.three\nI1\ntp1\na.",
+			"(lp0\n(VOne.two.three\n(VNaN\nNtp1\ntp2\na.",
+		}
+		for _, message := range invalidMessages {
+			_, err := parsePickleMessage([]byte(message))
+			So(err, ShouldBeError)
+		}
+	})
+}
+
+func TestReadPickleLines(t *testing.T) {
+	message := []byte("(lp0\n(VOne.two.three\np1\n(I1234567890\nF1.5\ntp2\ntp3\na.")
+
+	Convey("Should read length-prefixed messages one by one", t, func() {
+		stream := &bytes.Buffer{}
+		for i := 0; i < 2; i++ {
+			binary.Write(stream, binary.BigEndian, uint32(len(message))) //nolint
+			stream.Write(message)
+		}
+		buffer := bufio.NewReader(stream)
+
+		for i := 0; i < 2; i++ {
+			lines, err := readPickleLines(buffer)
+			So(err, ShouldBeNil)
+			So(lines, ShouldResemble, [][]byte{[]byte("One.two.three 1.5 1234567890")})
+		}
+		_, err := readPickleLines(buffer)
+		So(err, ShouldEqual, io.EOF)
+	})
+
+	Convey("Should reject too big messages", t, func() {
+		stream := &bytes.Buffer{}
+		binary.Write(stream, binary.BigEndian, uint32(maxPickleMessageSize+1)) //nolint
+		_, err := readPickleLines(bufio.NewReader(stream))
+		So(err, ShouldBeError)
+	})
+}
@@ -0,0 +1,125 @@
+package connection
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"gopkg.in/tomb.v2"
+
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/filter"
+	"github.com/moira-alert/moira/metrics"
+)
+
+const (
+	remoteWriteShutdownTimeout = 10 * time.Second
+	// remoteWriteMaxRequestSize limits size of compressed remote write request body
+	remoteWriteMaxRequestSize = 32 << 20
+)
+
+// RemoteWriteListener receives metrics via Prometheus remote write HTTP protocol
+type RemoteWriteListener struct {
+	listener    net.Listener
+	server      *http.Server
+	path        string
+	logger      moira.Logger
+	tomb        tomb.Tomb
+	metrics     *metrics.FilterMetrics
+	metricsChan chan *filter.ParsedMetric
+	// sendLock is held by handlers while they send metrics, channel is closed only after all of them returned
+	sendLock sync.RWMutex
+	closed   bool
+}
+
+// NewRemoteWriteListener creates new Prometheus remote write listener
+func NewRemoteWriteListener(listen string, path string, logger moira.Logger, metrics *metrics.FilterMetrics) (*RemoteWriteListener, error) {
+	newListener, err := net.Listen("tcp", listen)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on [%s]: %s", listen, err.Error())
+	}
+	listener := &RemoteWriteListener{
+		listener: newListener,
+		path:     path,
+		logger:   logger,
+		metrics:  metrics,
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc(path, listener.handle)
+	listener.server = &http.Server{Handler: mux}
+	return listener, nil
+}
+
+// Listen serves remote write requests and sends every received sample to returned channel as parsed metric.
+// Channel is closed on Stop
+func (listener *RemoteWriteListener) Listen() <-chan *filter.ParsedMetric {
+	listener.metricsChan = make(chan *filter.ParsedMetric, 16384) //nolint
+	listener.tomb.Go(func() error {
+		err := listener.server.Serve(listener.listener)
+		if err != http.ErrServerClosed {
+			return err
+		}
+		return nil
+	})
+	listener.tomb.Go(func() error {
+		<-listener.tomb.Dying()
+		listener.logger.Info("Stopping remote write listener...")
+		ctx, cancel := context.WithTimeout(context.Background(), remoteWriteShutdownTimeout)
+		defer cancel()
+		err := listener.server.Shutdown(ctx)
+		listener.sendLock.Lock()
+		listener.closed = true
+		close(listener.metricsChan)
+		listener.sendLock.Unlock()
+		listener.logger.Info("Moira Filter Remote Write Listener stopped")
+		return err
+	})
+	listener.logger.Infof("Moira Filter Remote Write Listener Started on %s%s", listener.listener.Addr(), listener.path)
+	return listener.metricsChan
+}
+
+// Stop stops serving remote write requests and waits for handling of all accepted requests
+func (listener *RemoteWriteListener) Stop() error {
+	listener.tomb.Kill(nil)
+	return listener.tomb.Wait()
+}
+
+func (listener *RemoteWriteListener) handle(writer http.ResponseWriter, request *http.Request) {
+	if request.Method != http.MethodPost {
+		http.Error(writer, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	body, err := ioutil.ReadAll(http.MaxBytesReader(writer, request.Body, remoteWriteMaxRequestSize))
+	if err != nil {
+		listener.logger.Errorf("Fail to read remote write request: %s", err)
+		http.Error(writer, err.Error(), http.StatusBadRequest)
+		return
+	}
+	parsedMetrics, err := filter.ParseRemoteWriteRequest(body)
+	if err != nil {
+		listener.logger.Infof("cannot parse remote write request from %s: %v", request.RemoteAddr, err)
+		http.Error(writer, err.Error(), http.StatusBadRequest)
+		return
+	}
+	listener.sendLock.RLock()
+	defer listener.sendLock.RUnlock()
+	if listener.closed {
+		http.Error(writer, "listener is stopped", http.StatusServiceUnavailable)
+		return
+	}
+	for _, parsedMetric := range parsedMetrics {
+		listener.metrics.RemoteWriteSamplesReceived.Inc()
+		select {
+		case listener.metricsChan <- parsedMetric:
+		case <-listener.tomb.Dying():
+			// Handler which is still blocked after shutdown timeout must not keep channel from being closed
+			http.Error(writer, "listener is stopping", http.StatusServiceUnavailable)
+			return
+		}
+	}
+	writer.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,82 @@
+package connection
+
+import (
+	"bytes"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/snappy"
+	"github.com/moira-alert/moira/filter"
+	logging "github.com/moira-alert/moira/logging/zerolog_adapter"
+	"github.com/moira-alert/moira/metrics"
+	. "github.com/smartystreets/goconvey/convey"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// buildTestRemoteWriteRequest returns remote write request with single sample of time series with given name
+func buildTestRemoteWriteRequest(name string, value float64, timestamp int64) []byte {
+	labelBytes := protowire.AppendTag(nil, 1, protowire.BytesType)
+	labelBytes = protowire.AppendString(labelBytes, "__name__")
+	labelBytes = protowire.AppendTag(labelBytes, 2, protowire.BytesType) //nolint
+	labelBytes = protowire.AppendString(labelBytes, name)
+	sampleBytes := protowire.AppendTag(nil, 1, protowire.Fixed64Type)
+	sampleBytes = protowire.AppendFixed64(sampleBytes, math.Float64bits(value))
+	sampleBytes = protowire.AppendTag(sampleBytes, 2, protowire.VarintType) //nolint
+	sampleBytes = protowire.AppendVarint(sampleBytes, uint64(timestamp))
+	timeSeriesBytes := protowire.AppendTag(nil, 1, protowire.BytesType)
+	timeSeriesBytes = protowire.AppendBytes(timeSeriesBytes, labelBytes)
+	timeSeriesBytes = protowire.AppendTag(timeSeriesBytes, 2, protowire.BytesType) //nolint
+	timeSeriesBytes = protowire.AppendBytes(timeSeriesBytes, sampleBytes)
+	request := protowire.AppendTag(nil, 1, protowire.BytesType)
+	request = protowire.AppendBytes(request, timeSeriesBytes)
+	return snappy.Encode(nil, request)
+}
+
+func TestRemoteWriteListener_handle(t *testing.T) {
+	logger, _ := logging.GetLogger("Test")
+	filterMetrics := metrics.ConfigureFilterMetrics(metrics.NewDummyRegistry())
+
+	Convey("Samples are sent as parsed metrics", t, func() {
+		listener := &RemoteWriteListener{logger: logger, metrics: filterMetrics, metricsChan: make(chan *filter.ParsedMetric, 1)}
+		request := httptest.NewRequest(http.MethodPost, "/api/v1/write", bytes.NewReader(buildTestRemoteWriteRequest("up", 1, 1234567890000)))
+		recorder := httptest.NewRecorder()
+		listener.handle(recorder, request)
+		So(recorder.Code, ShouldEqual, http.StatusNoContent)
+		So(<-listener.metricsChan, ShouldResemble, &filter.ParsedMetric{
+			Metric:    "up",
+			Name:      "up",
+			Labels:    map[string]string{},
+			Value:     1,
+			Timestamp: 1234567890,
+		})
+	})
+
+	Convey("Too large request is rejected", t, func() {
+		listener := &RemoteWriteListener{logger: logger, metrics: filterMetrics, metricsChan: make(chan *filter.ParsedMetric, 1)}
+		request := httptest.NewRequest(http.MethodPost, "/api/v1/write", bytes.NewReader(make([]byte, remoteWriteMaxRequestSize+1)))
+		recorder := httptest.NewRecorder()
+		listener.handle(recorder, request)
+		So(recorder.Code, ShouldEqual, http.StatusBadRequest)
+		So(recorder.Body.String(), ShouldContainSubstring, "too large")
+		So(listener.metricsChan, ShouldBeEmpty)
+	})
+	Convey("Blocked handler returns when listener is stopping", t, func() {
+		listener := &RemoteWriteListener{logger: logger, metrics: filterMetrics, metricsChan: make(chan *filter.ParsedMetric)}
+		listener.tomb.Kill(nil)
+		request := httptest.NewRequest(http.MethodPost, "/api/v1/write", bytes.NewReader(buildTestRemoteWriteRequest("up", 1, 1234567890000)))
+		recorder := httptest.NewRecorder()
+		listener.handle(recorder, request)
+		So(recorder.Code, ShouldEqual, http.StatusServiceUnavailable)
+	})
+
+	Convey("Request is rejected after channel is closed", t, func() {
+		listener := &RemoteWriteListener{logger: logger, metrics: filterMetrics, metricsChan: make(chan *filter.ParsedMetric, 1), closed: true}
+		request := httptest.NewRequest(http.MethodPost, "/api/v1/write", bytes.NewReader(buildTestRemoteWriteRequest("up", 1, 1234567890000)))
+		recorder := httptest.NewRecorder()
+		listener.handle(recorder, request)
+		So(recorder.Code, ShouldEqual, http.StatusServiceUnavailable)
+		So(listener.metricsChan, ShouldBeEmpty)
+	})
+}
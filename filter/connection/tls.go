@@ -0,0 +1,75 @@
+package connection
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/moira-alert/moira/filter"
+)
+
+// NewServerTLSConfig creates listener TLS settings with optional client certificates verification.
+// Allowed prefixes require client certificates verification, because prefixes are bound to certificate common name
+func NewServerTLSConfig(config filter.TLSConfig) (*tls.Config, error) {
+	if len(config.AllowedPrefixes) > 0 && config.ClientCAFile == "" {
+		return nil, fmt.Errorf("client CA file must be set to restrict metric prefixes of clients")
+	}
+	certificate, err := tls.LoadX509KeyPair(config.CertFile, config.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load certificate [%s]: %s", config.CertFile, err.Error())
+	}
+	tlsConfig := &tls.Config{
+		Certificates: []tls.Certificate{certificate},
+		MinVersion:   tls.VersionTLS12,
+	}
+	if config.ClientCAFile == "" {
+		return tlsConfig, nil
+	}
+
+	caCertificates, err := ioutil.ReadFile(config.ClientCAFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read client CA file [%s]: %s", config.ClientCAFile, err.Error())
+	}
+	clientCAs := x509.NewCertPool()
+	if !clientCAs.AppendCertsFromPEM(caCertificates) {
+		return nil, fmt.Errorf("no certificates found in client CA file [%s]", config.ClientCAFile)
+	}
+	tlsConfig.ClientCAs = clientCAs
+	tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	return tlsConfig, nil
+}
+
+// prefixFilter drops lines with metrics outside of allowed prefixes, nil filter has no restrictions
+type prefixFilter [][]byte
+
+// newPrefixFilter creates filter allowing only given prefixes, filter without prefixes forbids everything
+func newPrefixFilter(prefixes []string) prefixFilter {
+	allowed := make(prefixFilter, 0, len(prefixes))
+	for _, prefix := range prefixes {
+		allowed = append(allowed, []byte(prefix))
+	}
+	return allowed
+}
+
+// isAllowed returns true if filter has no restrictions or metric of line starts with one of allowed prefixes.
+// Prefix matches whole name nodes only: it must be followed by dot or be the full metric name
+func (allowed prefixFilter) isAllowed(line []byte) bool {
+	if allowed == nil {
+		return true
+	}
+	for _, prefix := range allowed {
+		if !bytes.HasPrefix(line, prefix) {
+			continue
+		}
+		if len(line) == len(prefix) || prefix[len(prefix)-1] == '.' {
+			return true
+		}
+		switch line[len(prefix)] {
+		case '.', ' ', ';':
+			return true
+		}
+	}
+	return false
+}
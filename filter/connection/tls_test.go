@@ -0,0 +1,193 @@
+package connection
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/moira-alert/moira/filter"
+	logging "github.com/moira-alert/moira/logging/zerolog_adapter"
+	"github.com/moira-alert/moira/metrics"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+type testCertificate struct {
+	certificate *x509.Certificate
+	key         *ecdsa.PrivateKey
+	certPEM     []byte
+	keyPEM      []byte
+}
+
+func newTestCertificate(t *testing.T, commonName string, parent *testCertificate) *testCertificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	parentCertificate, parentKey := template, key
+	if parent == nil {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+	} else {
+		parentCertificate, parentKey = parent.certificate, parent.key
+	}
+	certificateBytes, err := x509.CreateCertificate(rand.Reader, template, parentCertificate, &key.PublicKey, parentKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	certificate, _ := x509.ParseCertificate(certificateBytes)
+	keyBytes, _ := x509.MarshalECPrivateKey(key)
+	return &testCertificate{
+		certificate: certificate,
+		key:         key,
+		certPEM:     pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certificateBytes}),
+		keyPEM:      pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyBytes}),
+	}
+}
+
+func writeTestFile(t *testing.T, path string, data []byte) string {
+	if err := ioutil.WriteFile(path, data, 0600); err != nil { //nolint
+		t.Fatal(err)
+	}
+	return path
+}
+
+func sendTLSLines(address string, ca *testCertificate, client *testCertificate, lines ...string) error {
+	clientCertificate, err := tls.X509KeyPair(client.certPEM, client.keyPEM)
+	if err != nil {
+		return err
+	}
+	rootCAs := x509.NewCertPool()
+	rootCAs.AddCert(ca.certificate)
+	conn, err := tls.Dial("tcp", address, &tls.Config{
+		Certificates: []tls.Certificate{clientCertificate},
+		RootCAs:      rootCAs,
+		MinVersion:   tls.VersionTLS12,
+	})
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	for _, line := range lines {
+		if _, err := conn.Write([]byte(line + "\n")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestTLSListener(t *testing.T) {
+	logger, _ := logging.GetLogger("Filter")
+	dir := t.TempDir()
+	ca := newTestCertificate(t, "Test CA", nil)
+	server := newTestCertificate(t, "127.0.0.1", ca)
+	restrictedClient := newTestCertificate(t, "restricted", ca)
+	unlistedClient := newTestCertificate(t, "unlisted", ca)
+	config := filter.TLSConfig{
+		Enabled:         true,
+		CertFile:        writeTestFile(t, filepath.Join(dir, "server.crt"), server.certPEM),
+		KeyFile:         writeTestFile(t, filepath.Join(dir, "server.key"), server.keyPEM),
+		ClientCAFile:    writeTestFile(t, filepath.Join(dir, "ca.crt"), ca.certPEM),
+		AllowedPrefixes: map[string][]string{"restricted": {"Allowed.", "Other.allowed."}},
+	}
+
+	Convey("Given invalid TLS settings, should return error", t, func() {
+		invalidConfig := config
+		invalidConfig.ClientCAFile = writeTestFile(t, filepath.Join(dir, "invalid.crt"), []byte("not a certificate"))
+		_, err := NewTLSListener("127.0.0.1:0", invalidConfig, logger, metrics.ConfigureFilterMetrics(metrics.NewDummyRegistry()))
+		So(err, ShouldBeError)
+	})
+
+	Convey("Given allowed prefixes without client CA, should return error", t, func() {
+		invalidConfig := config
+		invalidConfig.ClientCAFile = ""
+		_, err := NewTLSListener("127.0.0.1:0", invalidConfig, logger, metrics.ConfigureFilterMetrics(metrics.NewDummyRegistry()))
+		So(err, ShouldBeError)
+	})
+
+	Convey("Given TLS listener with allowed prefixes", t, func() {
+		filterMetrics := metrics.ConfigureFilterMetrics(metrics.NewDummyRegistry())
+		listener, err := NewTLSListener("127.0.0.1:0", config, logger, filterMetrics)
+		So(err, ShouldBeNil)
+		address := listener.listener.Addr().String()
+		lineChan := listener.Listen()
+
+		Convey("Restricted client metrics outside of allowed prefixes should be dropped and counted", func() {
+			err := sendTLSLines(address, ca, restrictedClient,
+				"Allowed.metric 1 1234567890",
+				"Forbidden.metric 2 1234567890",
+				"Other.allowed.metric 3 1234567890")
+			So(err, ShouldBeNil)
+			So(string(<-lineChan), ShouldEqual, "Allowed.metric 1 1234567890")
+			So(string(<-lineChan), ShouldEqual, "Other.allowed.metric 3 1234567890")
+			So(listener.Stop(), ShouldBeNil)
+			So(filterMetrics.TCPMetricsReceived.Count(), ShouldEqual, 3)
+			So(filterMetrics.ForbiddenMetricsReceived.Count(), ShouldEqual, 1)
+		})
+
+		Convey("Not listed client can't send any metrics", func() {
+			err := sendTLSLines(address, ca, unlistedClient, "Allowed.metric 1 1234567890")
+			So(err, ShouldBeNil)
+			time.Sleep(100 * time.Millisecond) //nolint
+			So(listener.Stop(), ShouldBeNil)
+			So(lineChan, ShouldBeEmpty)
+			So(filterMetrics.ForbiddenMetricsReceived.Count(), ShouldEqual, 1)
+		})
+
+		Convey("Client without certificate signed by CA should not be able to send metrics", func() {
+			stranger := newTestCertificate(t, "restricted", newTestCertificate(t, "Other CA", nil))
+			err := sendTLSLines(address, ca, stranger, "Allowed.metric 1 1234567890")
+			if err == nil {
+				time.Sleep(100 * time.Millisecond) //nolint
+			}
+			So(listener.Stop(), ShouldBeNil)
+			So(filterMetrics.TCPMetricsReceived.Count(), ShouldEqual, 0)
+		})
+	})
+}
+
+func TestPrefixFilter(t *testing.T) {
+	Convey("Nil filter should allow everything", t, func() {
+		So(prefixFilter(nil).isAllowed([]byte("Any.metric 1 2")), ShouldBeTrue)
+	})
+
+	Convey("Filter without prefixes should forbid everything", t, func() {
+		So(newPrefixFilter(nil).isAllowed([]byte("Any.metric 1 2")), ShouldBeFalse)
+		So(newPrefixFilter([]string{}).isAllowed([]byte("Any.metric 1 2")), ShouldBeFalse)
+	})
+
+	Convey("Filter should allow only metrics with given prefixes", t, func() {
+		allowed := newPrefixFilter([]string{"One.", "Two.three"})
+		So(allowed.isAllowed([]byte("One.metric 1 2")), ShouldBeTrue)
+		So(allowed.isAllowed([]byte("Two.three.four 1 2")), ShouldBeTrue)
+		So(allowed.isAllowed([]byte("Two.four 1 2")), ShouldBeFalse)
+		So(allowed.isAllowed([]byte("One 1 2")), ShouldBeFalse)
+	})
+
+	Convey("Prefix should match whole name nodes only", t, func() {
+		allowed := newPrefixFilter([]string{"a.b"})
+		So(allowed.isAllowed([]byte("a.b.c 1 2")), ShouldBeTrue)
+		So(allowed.isAllowed([]byte("a.b 1 2")), ShouldBeTrue)
+		So(allowed.isAllowed([]byte("a.b;tag=value 1 2")), ShouldBeTrue)
+		So(allowed.isAllowed([]byte("a.b")), ShouldBeTrue)
+		So(allowed.isAllowed([]byte("a.bc.d 1 2")), ShouldBeFalse)
+	})
+}
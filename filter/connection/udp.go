@@ -0,0 +1,98 @@
+package connection
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"time"
+
+	"gopkg.in/tomb.v2"
+
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/metrics"
+)
+
+const maxUDPPacketSize = 65536
+
+// UDPListener receives graphite plaintext protocol lines from UDP datagrams
+type UDPListener struct {
+	conn    *net.UDPConn
+	logger  moira.Logger
+	tomb    tomb.Tomb
+	metrics *metrics.FilterMetrics
+}
+
+// NewUDPListener creates new UDP listener
+func NewUDPListener(port string, logger moira.Logger, metrics *metrics.FilterMetrics) (*UDPListener, error) {
+	address, err := net.ResolveUDPAddr("udp", port)
+	if nil != err {
+		return nil, fmt.Errorf("failed to resolve udp address [%s]: %s", port, err.Error())
+	}
+	conn, err := net.ListenUDP("udp", address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on [%s]: %s", port, err.Error())
+	}
+	return &UDPListener{
+		conn:    conn,
+		logger:  logger,
+		metrics: metrics,
+	}, nil
+}
+
+// Listen reads datagrams and sends every line from them to lineChan
+// lineChan is not closed on Stop, so this listener must be stopped before the one which owns lineChan
+func (listener *UDPListener) Listen(lineChan chan<- []byte) {
+	listener.ListenFunc(func(line []byte) {
+		listener.metrics.UDPMetricsReceived.Inc()
+		lineChan <- line
+	})
+}
+
+// ListenFunc reads datagrams and passes every line from them to handleLine
+func (listener *UDPListener) ListenFunc(handleLine func(line []byte)) {
+	listener.tomb.Go(func() error {
+		buffer := make([]byte, maxUDPPacketSize)
+		for {
+			select {
+			case <-listener.tomb.Dying():
+				listener.logger.Info("Stopping UDP listener...")
+				listener.conn.Close()
+				listener.logger.Info("Moira Filter UDP Listener stopped")
+				return nil
+			default:
+			}
+			listener.conn.SetReadDeadline(time.Now().Add(1e9)) //nolint
+			size, err := listener.conn.Read(buffer)
+			if err != nil {
+				if opErr, ok := err.(*net.OpError); ok && opErr.Timeout() {
+					continue
+				}
+				listener.logger.Infof("Failed to read UDP datagram: %s", err.Error())
+				continue
+			}
+			for _, line := range splitDatagram(buffer[:size]) {
+				handleLine(line)
+			}
+		}
+	})
+	listener.logger.Infof("Moira Filter UDP Listener Started on %s", listener.conn.LocalAddr())
+}
+
+// Stop stops reading datagrams
+func (listener *UDPListener) Stop() error {
+	listener.tomb.Kill(nil)
+	return listener.tomb.Wait()
+}
+
+// splitDatagram returns copies of non-empty lines from datagram, so read buffer can be reused
+func splitDatagram(datagram []byte) [][]byte {
+	lines := make([][]byte, 0)
+	for _, line := range bytes.Split(datagram, []byte{'\n'}) {
+		line = dropCRLF(line)
+		if len(line) == 0 {
+			continue
+		}
+		lines = append(lines, append([]byte(nil), line...))
+	}
+	return lines
+}
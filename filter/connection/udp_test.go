@@ -0,0 +1,28 @@
+package connection
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestSplitDatagram(t *testing.T) {
+	Convey("Should split datagram into non-empty lines", t, func() {
+		datagram := []byte("One.two.three 1 1234567890\r\n\nFour.five 2 1234567890\nSix.seven 3 1234567890")
+		lines := splitDatagram(datagram)
+		So(lines, ShouldResemble, [][]byte{
+			[]byte("One.two.three 1 1234567890"),
+			[]byte("Four.five 2 1234567890"),
+			[]byte("Six.seven 3 1234567890"),
+		})
+
+		Convey("Lines should not share memory with datagram", func() {
+			datagram[0] = 'X'
+			So(string(lines[0]), ShouldEqual, "One.two.three 1 1234567890")
+		})
+	})
+
+	Convey("Should return no lines for empty datagram", t, func() {
+		So(splitDatagram([]byte("\n\r\n")), ShouldBeEmpty)
+	})
+}
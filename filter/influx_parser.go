@@ -0,0 +1,135 @@
+package filter
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ParseInfluxLine parses metrics from InfluxDB line protocol string
+// supported format: "<measurement>[,<tag>=<value>...] <field>=<value>[,<field>=<value>...] [<timestampNanoseconds>]"
+// Every numeric or boolean field becomes separate ParsedMetric with name "<measurement>.<field>" and labels from tags.
+// String fields are skipped
+func ParseInfluxLine(input []byte) ([]*ParsedMetric, error) {
+	line := string(input)
+	sections := splitInfluxLine(line, ' ')
+	if len(sections) < 2 { //nolint
+		return nil, fmt.Errorf("too few space-separated sections: '%s'", line)
+	}
+	if len(sections) > 3 { //nolint
+		return nil, fmt.Errorf("too many space-separated sections: '%s'", line)
+	}
+
+	keys := splitInfluxLine(sections[0], ',')
+	measurement := sanitizeGraphiteString(unescapeInflux(keys[0]))
+	if measurement == "" {
+		return nil, fmt.Errorf("empty measurement: '%s'", line)
+	}
+	labels := make(map[string]string, len(keys)-1)
+	for _, tag := range keys[1:] {
+		tagName, tagValue, err := splitInfluxPair(tag)
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse tag: '%s' (%s)", line, err)
+		}
+		// Graphite tag name ends at first equal sign
+		labels[strings.Replace(sanitizeGraphiteString(tagName), "=", "_", -1)] = sanitizeGraphiteString(tagValue)
+	}
+
+	timestamp := time.Now().Unix()
+	if len(sections) == 3 { //nolint
+		nanoseconds, err := strconv.ParseInt(sections[2], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse timestamp: '%s' (%s)", line, err)
+		}
+		timestamp = nanoseconds / int64(time.Second)
+	}
+
+	fields := splitInfluxLine(sections[1], ',')
+	parsedMetrics := make([]*ParsedMetric, 0, len(fields))
+	for _, field := range fields {
+		fieldName, fieldValue, err := splitInfluxPair(field)
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse field: '%s' (%s)", line, err)
+		}
+		if strings.HasPrefix(fieldValue, "\"") {
+			continue
+		}
+		value, err := parseInfluxFieldValue(fieldValue)
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse field value: '%s' (%s)", line, err)
+		}
+		name := measurement + "." + sanitizeGraphiteString(fieldName)
+		parsedMetrics = append(parsedMetrics, &ParsedMetric{
+			Metric:    BuildTaggedMetric(name, labels),
+			Name:      name,
+			Labels:    labels,
+			Value:     value,
+			Timestamp: timestamp,
+		})
+	}
+	return parsedMetrics, nil
+}
+
+func parseInfluxFieldValue(value string) (float64, error) {
+	switch value {
+	case "t", "T", "true", "True", "TRUE":
+		return 1, nil
+	case "f", "F", "false", "False", "FALSE":
+		return 0, nil
+	}
+	if strings.HasSuffix(value, "i") || strings.HasSuffix(value, "u") {
+		return strconv.ParseFloat(value[:len(value)-1], 64)
+	}
+	return strconv.ParseFloat(value, 64)
+}
+
+// splitInfluxPair splits "<key>=<value>" by first unescaped equal sign and unescapes key and value
+func splitInfluxPair(pair string) (string, string, error) {
+	parts := splitInfluxLine(pair, '=')
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" { //nolint
+		return "", "", fmt.Errorf("invalid key-value pair '%s'", pair)
+	}
+	if strings.HasPrefix(parts[1], "\"") {
+		return unescapeInflux(parts[0]), parts[1], nil
+	}
+	return unescapeInflux(parts[0]), unescapeInflux(parts[1]), nil
+}
+
+// splitInfluxLine splits string by separator which is not escaped with backslash and is not inside quoted field value
+func splitInfluxLine(line string, separator byte) []string {
+	parts := make([]string, 0)
+	start, quoted := 0, false
+	for i := 0; i < len(line); i++ {
+		switch line[i] {
+		case '\\':
+			i++
+		case '"':
+			if quoted {
+				quoted = false
+			} else if i > 0 && line[i-1] == '=' {
+				quoted = true
+			}
+		case separator:
+			if !quoted {
+				parts = append(parts, line[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(parts, line[start:])
+}
+
+func unescapeInflux(input string) string {
+	if !strings.Contains(input, "\\") {
+		return input
+	}
+	var unescaped strings.Builder
+	for i := 0; i < len(input); i++ {
+		if input[i] == '\\' && i+1 < len(input) {
+			i++
+		}
+		unescaped.WriteByte(input[i])
+	}
+	return unescaped.String()
+}
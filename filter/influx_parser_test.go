@@ -0,0 +1,65 @@
+package filter
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestParseInfluxLine(t *testing.T) {
+	Convey("Given invalid lines, should return errors", t, func() {
+		invalidLines := []string{
+			"cpu",
+			"cpu,host=a",
+			"cpu value=1 1234567890000000000 extra",
+			",host=a value=1",
+			"cpu,host value=1",
+			"cpu,host= value=1",
+			"cpu value",
+			"cpu value=",
+			"cpu value=abc",
+			"cpu value=1 timestamp",
+		}
+		for _, line := range invalidLines {
+			parsedMetrics, err := ParseInfluxLine([]byte(line))
+			So(err, ShouldBeError)
+			So(parsedMetrics, ShouldBeNil)
+		}
+	})
+
+	Convey("Given line with one field and no tags, should return metric", t, func() {
+		parsedMetrics, err := ParseInfluxLine([]byte("cpu value=0.5 1465839830100400200"))
+		So(err, ShouldBeNil)
+		So(parsedMetrics, ShouldResemble, []*ParsedMetric{
+			{Metric: "cpu.value", Name: "cpu.value", Labels: map[string]string{}, Value: 0.5, Timestamp: 1465839830},
+		})
+	})
+
+	Convey("Given line with tags and several fields, should return metric for every numeric field", t, func() {
+		parsedMetrics, err := ParseInfluxLine([]byte(`cpu,host=server01,region=us-west idle=10i,busy=2u,up=t,down=false,comment="user=1, sys=2" 1465839830100400200`))
+		So(err, ShouldBeNil)
+		labels := map[string]string{"host": "server01", "region": "us-west"}
+		So(parsedMetrics, ShouldResemble, []*ParsedMetric{
+			{Metric: "cpu.idle;host=server01;region=us-west", Name: "cpu.idle", Labels: labels, Value: 10, Timestamp: 1465839830},
+			{Metric: "cpu.busy;host=server01;region=us-west", Name: "cpu.busy", Labels: labels, Value: 2, Timestamp: 1465839830},
+			{Metric: "cpu.up;host=server01;region=us-west", Name: "cpu.up", Labels: labels, Value: 1, Timestamp: 1465839830},
+			{Metric: "cpu.down;host=server01;region=us-west", Name: "cpu.down", Labels: labels, Value: 0, Timestamp: 1465839830},
+		})
+	})
+
+	Convey("Given line with escaped characters, should unescape and sanitize them", t, func() {
+		parsedMetrics, err := ParseInfluxLine([]byte(`disk\ io,path=/var\,log,mode\=x=rw read\ ops=5 1465839830000000000`))
+		So(err, ShouldBeNil)
+		labels := map[string]string{"path": "/var,log", "mode_x": "rw"}
+		So(parsedMetrics, ShouldResemble, []*ParsedMetric{
+			{Metric: "disk_io.read_ops;mode_x=rw;path=/var,log", Name: "disk_io.read_ops", Labels: labels, Value: 5, Timestamp: 1465839830},
+		})
+	})
+
+	Convey("Given line without timestamp, should use current time", t, func() {
+		parsedMetrics, err := ParseInfluxLine([]byte("cpu value=1"))
+		So(err, ShouldBeNil)
+		So(parsedMetrics, ShouldHaveLength, 1)
+		So(parsedMetrics[0].Timestamp, ShouldBeGreaterThan, 0)
+	})
+}
@@ -0,0 +1,196 @@
+package filter
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/moira-alert/moira"
+)
+
+// LimitsConfig is filter ingestion limits settings.
+// Limits are applied by every filter instance to metrics it received since start, so with N filter instances
+// pattern may get up to N times more distinct metrics, and metrics already stored for pattern are not counted
+type LimitsConfig struct {
+	// Max number of distinct metrics matched by one pattern and received by this filter instance.
+	// Metrics over the limit are not saved for the pattern. Unlimited if 0
+	MaxPatternMetrics int
+	// Metric stops counting towards pattern limit if it was not received during this period
+	PatternMetricsTTL time.Duration
+	// Number of leading metric name nodes which form rate limited prefix
+	PrefixDepth int
+	// Max number of points per second for metrics with the same prefix received by this filter instance.
+	// Points over the limit are dropped. Unlimited if 0
+	MaxPrefixPointsPerSecond int
+}
+
+const patternMetricsCleanupPeriod = 60
+
+// ingestionLimiter tracks number of distinct metrics per pattern and points rate per prefix
+type ingestionLimiter struct {
+	config LimitsConfig
+
+	patternsMutex  sync.Mutex
+	patternMetrics map[string]map[string]int64
+	lastCleanup    int64
+
+	prefixesMutex sync.Mutex
+	prefixPoints  map[string]int
+	currentSecond int64
+
+	violationsMutex   sync.Mutex
+	violations        map[string]*moira.IngestionLimitViolation
+	changedViolations map[string]bool
+}
+
+func newIngestionLimiter(config LimitsConfig) *ingestionLimiter {
+	if config.PrefixDepth <= 0 {
+		config.PrefixDepth = 1
+	}
+	return &ingestionLimiter{
+		config:            config,
+		patternMetrics:    make(map[string]map[string]int64),
+		prefixPoints:      make(map[string]int),
+		violations:        make(map[string]*moira.IngestionLimitViolation),
+		changedViolations: make(map[string]bool),
+	}
+}
+
+// allowPoint returns false if prefix of metric name exceeded points per second limit
+func (limiter *ingestionLimiter) allowPoint(name string, now int64) bool {
+	if limiter.config.MaxPrefixPointsPerSecond <= 0 {
+		return true
+	}
+	prefix := getMetricPrefix(name, limiter.config.PrefixDepth)
+
+	limiter.prefixesMutex.Lock()
+	if limiter.currentSecond != now {
+		limiter.currentSecond = now
+		limiter.prefixPoints = make(map[string]int)
+	}
+	limiter.prefixPoints[prefix]++
+	allowed := limiter.prefixPoints[prefix] <= limiter.config.MaxPrefixPointsPerSecond
+	limiter.prefixesMutex.Unlock()
+
+	if !allowed {
+		limiter.addViolation(&moira.IngestionLimitViolation{Prefix: prefix, Limit: limiter.config.MaxPrefixPointsPerSecond}, now)
+	}
+	return allowed
+}
+
+// filterPatterns returns patterns which have not reached distinct metrics limit or already contain given metric
+func (limiter *ingestionLimiter) filterPatterns(metric string, patterns []string, now int64) []string {
+	if limiter.config.MaxPatternMetrics <= 0 || len(patterns) == 0 {
+		return patterns
+	}
+	allowedPatterns := make([]string, 0, len(patterns))
+	limitedPatterns := make([]string, 0)
+
+	limiter.patternsMutex.Lock()
+	for _, pattern := range patterns {
+		metrics, ok := limiter.patternMetrics[pattern]
+		if !ok {
+			metrics = make(map[string]int64)
+			limiter.patternMetrics[pattern] = metrics
+		}
+		if _, ok := metrics[metric]; !ok && len(metrics) >= limiter.config.MaxPatternMetrics {
+			limitedPatterns = append(limitedPatterns, pattern)
+			continue
+		}
+		metrics[metric] = now
+		allowedPatterns = append(allowedPatterns, pattern)
+	}
+	limiter.patternsMutex.Unlock()
+
+	for _, pattern := range limitedPatterns {
+		limiter.addViolation(&moira.IngestionLimitViolation{Pattern: pattern, Limit: limiter.config.MaxPatternMetrics}, now)
+	}
+	return allowedPatterns
+}
+
+// cleanup forgets removed patterns, metrics which were not received during PatternMetricsTTL and old violations
+func (limiter *ingestionLimiter) cleanup(patterns []string, now int64) {
+	limiter.patternsMutex.Lock()
+	defer limiter.patternsMutex.Unlock()
+
+	if now-limiter.lastCleanup < patternMetricsCleanupPeriod {
+		return
+	}
+	limiter.lastCleanup = now
+
+	existingPatterns := make(map[string]bool, len(patterns))
+	for _, pattern := range patterns {
+		existingPatterns[pattern] = true
+	}
+	ttl := int64(limiter.config.PatternMetricsTTL.Seconds())
+	for pattern, metrics := range limiter.patternMetrics {
+		if !existingPatterns[pattern] {
+			delete(limiter.patternMetrics, pattern)
+			continue
+		}
+		if ttl <= 0 {
+			continue
+		}
+		for metric, lastSeen := range metrics {
+			if now-lastSeen > ttl {
+				delete(metrics, metric)
+			}
+		}
+	}
+	limiter.pruneViolations(now)
+}
+
+// pruneViolations forgets violations which were not repeated during violation TTL
+func (limiter *ingestionLimiter) pruneViolations(now int64) {
+	limiter.violationsMutex.Lock()
+	defer limiter.violationsMutex.Unlock()
+
+	for key, violation := range limiter.violations {
+		if now-violation.Timestamp > moira.IngestionLimitViolationTTL && !limiter.changedViolations[key] {
+			delete(limiter.violations, key)
+		}
+	}
+}
+
+func (limiter *ingestionLimiter) addViolation(violation *moira.IngestionLimitViolation, now int64) {
+	key := violation.GetKey()
+
+	limiter.violationsMutex.Lock()
+	defer limiter.violationsMutex.Unlock()
+
+	if existingViolation, ok := limiter.violations[key]; ok {
+		violation = existingViolation
+	} else {
+		limiter.violations[key] = violation
+	}
+	violation.Dropped++
+	violation.Timestamp = now
+	limiter.changedViolations[key] = true
+}
+
+// getChangedViolations returns copies of violations changed since previous call
+func (limiter *ingestionLimiter) getChangedViolations() []*moira.IngestionLimitViolation {
+	limiter.violationsMutex.Lock()
+	defer limiter.violationsMutex.Unlock()
+
+	violations := make([]*moira.IngestionLimitViolation, 0, len(limiter.changedViolations))
+	for key := range limiter.changedViolations {
+		violation := *limiter.violations[key]
+		violations = append(violations, &violation)
+	}
+	limiter.changedViolations = make(map[string]bool)
+	return violations
+}
+
+// getMetricPrefix returns first depth nodes of metric name
+func getMetricPrefix(name string, depth int) string {
+	index := 0
+	for i := 0; i < depth; i++ {
+		nextDot := strings.IndexByte(name[index:], '.')
+		if nextDot < 0 {
+			return name
+		}
+		index += nextDot + 1
+	}
+	return name[:index-1]
+}
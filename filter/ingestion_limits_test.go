@@ -0,0 +1,89 @@
+package filter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/moira-alert/moira"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestIngestionLimiter(t *testing.T) {
+	Convey("Limiter without limits should allow everything", t, func() {
+		limiter := newIngestionLimiter(LimitsConfig{})
+		for i := 0; i < 100; i++ {
+			So(limiter.allowPoint("One.two.three", 1234567890), ShouldBeTrue)
+		}
+		patterns := []string{"One.*.three"}
+		So(limiter.filterPatterns("One.two.three", patterns, 1234567890), ShouldResemble, patterns)
+		So(limiter.getChangedViolations(), ShouldBeEmpty)
+	})
+
+	Convey("Given points per second limit", t, func() {
+		limiter := newIngestionLimiter(LimitsConfig{PrefixDepth: 2, MaxPrefixPointsPerSecond: 2})
+
+		Convey("Points over the limit should be dropped within one second", func() {
+			So(limiter.allowPoint("One.two.three", 1234567890), ShouldBeTrue)
+			So(limiter.allowPoint("One.two.four", 1234567890), ShouldBeTrue)
+			So(limiter.allowPoint("One.two.five", 1234567890), ShouldBeFalse)
+			So(limiter.allowPoint("One.three.four", 1234567890), ShouldBeTrue)
+			So(limiter.allowPoint("One.two.three", 1234567891), ShouldBeTrue)
+			So(limiter.allowPoint("One.two.three", 1234567891), ShouldBeTrue)
+			So(limiter.allowPoint("One.two.three", 1234567891), ShouldBeFalse)
+			So(limiter.getChangedViolations(), ShouldResemble, []*moira.IngestionLimitViolation{
+				{Prefix: "One.two", Limit: 2, Dropped: 2, Timestamp: 1234567891},
+			})
+			So(limiter.getChangedViolations(), ShouldBeEmpty)
+		})
+
+		Convey("Violations which were not repeated during violation ttl should be forgotten on cleanup", func() {
+			limiter.allowPoint("One.two.three", 1234567890)
+			limiter.allowPoint("One.two.three", 1234567890)
+			limiter.allowPoint("One.two.three", 1234567890)
+			limiter.getChangedViolations()
+			limiter.cleanup(nil, 1234567890+moira.IngestionLimitViolationTTL)
+			So(limiter.violations, ShouldContainKey, "prefix:One.two")
+			limiter.cleanup(nil, 1234567890+moira.IngestionLimitViolationTTL+patternMetricsCleanupPeriod)
+			So(limiter.violations, ShouldBeEmpty)
+		})
+	})
+
+	Convey("Given distinct metrics per pattern limit", t, func() {
+		limiter := newIngestionLimiter(LimitsConfig{MaxPatternMetrics: 2, PatternMetricsTTL: time.Hour})
+		patterns := []string{"One.*", "*.two"}
+
+		So(limiter.filterPatterns("One.one", patterns[:1], 1234567890), ShouldResemble, patterns[:1])
+		So(limiter.filterPatterns("One.two", patterns, 1234567890), ShouldResemble, patterns)
+
+		Convey("New metric should be dropped only for patterns over the limit", func() {
+			So(limiter.filterPatterns("One.three", patterns[:1], 1234567890), ShouldBeEmpty)
+			So(limiter.filterPatterns("Two.two", patterns[1:], 1234567890), ShouldResemble, patterns[1:])
+			So(limiter.filterPatterns("One.two", patterns, 1234567890), ShouldResemble, patterns)
+			So(limiter.getChangedViolations(), ShouldResemble, []*moira.IngestionLimitViolation{
+				{Pattern: "One.*", Limit: 2, Dropped: 1, Timestamp: 1234567890},
+			})
+		})
+
+		Convey("Metrics which were not received during ttl should be forgotten on cleanup", func() {
+			limiter.filterPatterns("One.two", patterns, 1234567890+3000)
+			limiter.cleanup(patterns, 1234567890+3700)
+			So(limiter.filterPatterns("One.three", patterns[:1], 1234567890+3700), ShouldResemble, patterns[:1])
+		})
+
+		Convey("Removed patterns should be forgotten on cleanup", func() {
+			limiter.cleanup(patterns[1:], 1234567890)
+			So(limiter.patternMetrics, ShouldNotContainKey, "One.*")
+			So(limiter.patternMetrics, ShouldContainKey, "*.two")
+		})
+	})
+}
+
+func TestGetMetricPrefix(t *testing.T) {
+	Convey("Should return first nodes of metric name", t, func() {
+		So(getMetricPrefix("One.two.three", 1), ShouldEqual, "One")
+		So(getMetricPrefix("One.two.three", 2), ShouldEqual, "One.two")
+		So(getMetricPrefix("One.two.three", 3), ShouldEqual, "One.two.three")
+		So(getMetricPrefix("One.two.three", 4), ShouldEqual, "One.two.three")
+		So(getMetricPrefix("One", 1), ShouldEqual, "One")
+	})
+}
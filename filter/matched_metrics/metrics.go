@@ -6,6 +6,7 @@ import (
 
 	"github.com/moira-alert/moira"
 	"github.com/moira-alert/moira/filter"
+	metrictap "github.com/moira-alert/moira/filter/metric_tap"
 	"github.com/moira-alert/moira/metrics"
 )
 
@@ -15,18 +16,21 @@ type MetricsMatcher struct {
 	metrics       *metrics.FilterMetrics
 	database      moira.Database
 	cacheStorage  *filter.Storage
+	tap           *metrictap.Tap
 	cacheCapacity int
 	waitGroup     *sync.WaitGroup
 	closeRequest  chan struct{}
 }
 
 // NewMetricsMatcher creates new MetricsMatcher
-func NewMetricsMatcher(metrics *metrics.FilterMetrics, logger moira.Logger, database moira.Database, cacheStorage *filter.Storage, cacheCapacity int) *MetricsMatcher {
+// Saved metrics matched by tapped patterns are published to tap
+func NewMetricsMatcher(metrics *metrics.FilterMetrics, logger moira.Logger, database moira.Database, cacheStorage *filter.Storage, tap *metrictap.Tap, cacheCapacity int) *MetricsMatcher {
 	return &MetricsMatcher{
 		metrics:       metrics,
 		logger:        logger,
 		database:      database,
 		cacheStorage:  cacheStorage,
+		tap:           tap,
 		cacheCapacity: cacheCapacity,
 		waitGroup:     &sync.WaitGroup{},
 		closeRequest:  make(chan struct{}),
@@ -91,5 +95,7 @@ func (matcher *MetricsMatcher) Wait() {
 func (matcher *MetricsMatcher) save(buffer map[string]*moira.MatchedMetric) {
 	if err := matcher.database.SaveMetrics(buffer); err != nil {
 		matcher.logger.Errorf("Failed to save matched metrics: %s", err.Error())
+		return
 	}
+	matcher.tap.Publish(buffer)
 }
@@ -0,0 +1,127 @@
+package metrictap
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"gopkg.in/tomb.v2"
+
+	"github.com/moira-alert/moira"
+)
+
+const refreshPeriod = time.Second
+
+// Tap publishes samples of metrics matched by patterns which are tapped via API
+type Tap struct {
+	database            moira.Database
+	logger              moira.Logger
+	maxSamplesPerSecond int
+	tomb                tomb.Tomb
+	patterns            atomic.Value
+
+	mutex          sync.Mutex
+	currentSecond  int64
+	patternSamples map[string]int
+}
+
+// NewTap creates new Tap which publishes at most maxSamplesPerSecond samples for every tapped pattern
+// Tapping is disabled if maxSamplesPerSecond is not positive
+func NewTap(database moira.Database, logger moira.Logger, maxSamplesPerSecond int) *Tap {
+	tap := &Tap{
+		database:            database,
+		logger:              logger,
+		maxSamplesPerSecond: maxSamplesPerSecond,
+		patternSamples:      make(map[string]int),
+	}
+	tap.patterns.Store(make(map[string]bool))
+	return tap
+}
+
+// Start refreshes tapped patterns every second
+func (tap *Tap) Start() {
+	if tap.maxSamplesPerSecond <= 0 {
+		tap.logger.Info("Moira Filter Metric Tap is disabled")
+		return
+	}
+	tap.tomb.Go(func() error {
+		refreshTicker := time.NewTicker(refreshPeriod)
+		defer refreshTicker.Stop()
+		for {
+			select {
+			case <-tap.tomb.Dying():
+				tap.logger.Info("Moira Filter Metric Tap stopped")
+				return nil
+			case <-refreshTicker.C:
+				if err := tap.refresh(); err != nil {
+					tap.logger.Errorf("Failed to refresh tapped patterns: %s", err.Error())
+				}
+			}
+		}
+	})
+	tap.logger.Info("Moira Filter Metric Tap started")
+}
+
+// Stop stops refreshing tapped patterns
+func (tap *Tap) Stop() error {
+	if tap.maxSamplesPerSecond <= 0 {
+		return nil
+	}
+	tap.tomb.Kill(nil)
+	return tap.tomb.Wait()
+}
+
+func (tap *Tap) refresh() error {
+	tappedPatterns, err := tap.database.GetMetricTaps()
+	if err != nil {
+		return err
+	}
+	patterns := make(map[string]bool, len(tappedPatterns))
+	for _, pattern := range tappedPatterns {
+		patterns[pattern] = true
+	}
+	tap.patterns.Store(patterns)
+	return nil
+}
+
+// Publish sends samples of given metrics matched by tapped patterns
+func (tap *Tap) Publish(metrics map[string]*moira.MatchedMetric) {
+	samples := tap.getSamples(metrics, time.Now().Unix())
+	if len(samples) == 0 {
+		return
+	}
+	if err := tap.database.PublishMetricTapSamples(samples); err != nil {
+		tap.logger.Errorf("Failed to publish metric tap samples: %s", err.Error())
+	}
+}
+
+func (tap *Tap) getSamples(metrics map[string]*moira.MatchedMetric, now int64) []*moira.MetricTapSample {
+	patterns := tap.patterns.Load().(map[string]bool)
+	if len(patterns) == 0 {
+		return nil
+	}
+
+	tap.mutex.Lock()
+	defer tap.mutex.Unlock()
+
+	if tap.currentSecond != now {
+		tap.currentSecond = now
+		tap.patternSamples = make(map[string]int)
+	}
+	samples := make([]*moira.MetricTapSample, 0)
+	for _, metric := range metrics {
+		for _, pattern := range metric.Patterns {
+			if !patterns[pattern] || tap.patternSamples[pattern] >= tap.maxSamplesPerSecond {
+				continue
+			}
+			tap.patternSamples[pattern]++
+			samples = append(samples, &moira.MetricTapSample{
+				Pattern:   pattern,
+				Metric:    metric.Metric,
+				Value:     metric.Value,
+				Timestamp: metric.Timestamp,
+			})
+		}
+	}
+	return samples
+}
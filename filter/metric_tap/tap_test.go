@@ -0,0 +1,60 @@
+package metrictap
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/moira-alert/moira"
+	logging "github.com/moira-alert/moira/logging/zerolog_adapter"
+	mock_moira_alert "github.com/moira-alert/moira/mock/moira-alert"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestTap(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	database := mock_moira_alert.NewMockDatabase(mockCtrl)
+	logger, _ := logging.GetLogger("MetricTap")
+
+	metrics := map[string]*moira.MatchedMetric{
+		"One.two": {Metric: "One.two", Patterns: []string{"One.*", "*.two"}, Value: 1, Timestamp: 1234567890},
+	}
+
+	Convey("Disabled tap should be started and stopped", t, func() {
+		tap := NewTap(database, logger, 0)
+		tap.Start()
+		So(tap.Stop(), ShouldBeNil)
+	})
+
+	Convey("Without tapped patterns, should not publish samples", t, func() {
+		tap := NewTap(database, logger, 1)
+		tap.Publish(metrics)
+		So(tap.getSamples(metrics, 1234567890), ShouldBeEmpty)
+	})
+
+	Convey("Given tapped pattern", t, func() {
+		tap := NewTap(database, logger, 1)
+		database.EXPECT().GetMetricTaps().Return([]string{"One.*"}, nil)
+		So(tap.refresh(), ShouldBeNil)
+
+		Convey("Should publish samples of tapped pattern only", func() {
+			database.EXPECT().PublishMetricTapSamples([]*moira.MetricTapSample{
+				{Pattern: "One.*", Metric: "One.two", Value: 1, Timestamp: 1234567890},
+			}).Return(nil)
+			tap.Publish(metrics)
+		})
+
+		Convey("Should limit number of samples per second", func() {
+			So(tap.getSamples(metrics, 1234567890), ShouldHaveLength, 1)
+			So(tap.getSamples(metrics, 1234567890), ShouldBeEmpty)
+			So(tap.getSamples(metrics, 1234567891), ShouldHaveLength, 1)
+		})
+
+		Convey("Should keep tapped patterns if refresh fails", func() {
+			database.EXPECT().GetMetricTaps().Return(nil, fmt.Errorf("some error"))
+			So(tap.refresh(), ShouldBeError)
+			So(tap.getSamples(metrics, 1234567890), ShouldHaveLength, 1)
+		})
+	})
+}
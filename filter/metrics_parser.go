@@ -18,6 +18,17 @@ type ParsedMetric struct {
 	Timestamp int64
 }
 
+// Bytes returns metric in graphite plaintext format: "<metric> <value> <timestamp>"
+func (metric *ParsedMetric) Bytes() []byte {
+	line := make([]byte, 0, len(metric.Metric)+32) //nolint
+	line = append(line, metric.Metric...)
+	line = append(line, ' ')
+	line = strconv.AppendFloat(line, metric.Value, 'g', -1, 64)
+	line = append(line, ' ')
+	line = strconv.AppendInt(line, metric.Timestamp, 10)
+	return line
+}
+
 // ParseMetric parses metric from string
 // supported format: "<metricString> <valueFloat64> <timestampInt64>"
 func ParseMetric(input []byte) (*ParsedMetric, error) {
@@ -15,6 +15,8 @@ type Matcher struct {
 	tomb           tomb.Tomb
 	metrics        *metrics.FilterMetrics
 	patternStorage *filter.PatternStorage
+
+	matchedMetricsChan chan *moira.MatchedMetric
 }
 
 // NewMatcher creates pattern matcher
@@ -43,9 +45,19 @@ func (m *Matcher) Start(matchersCount int, lineChan <-chan []byte) chan *moira.M
 	}()
 
 	m.tomb.Go(func() error { return m.checkNewMetricsChannelLen(matchedMetricsChan) })
+	m.matchedMetricsChan = matchedMetricsChan
 	return matchedMetricsChan
 }
 
+// StartParsed spawns pattern matcher workers of metrics parsed by listeners, Start must be called before
+func (m *Matcher) StartParsed(matchersCount int, parsedMetricsChan <-chan *filter.ParsedMetric) {
+	for i := 0; i < matchersCount; i++ {
+		m.tomb.Go(func() error {
+			return m.parsedMetricsWorker(parsedMetricsChan, m.matchedMetricsChan)
+		})
+	}
+}
+
 func (m *Matcher) worker(metricsChan <-chan []byte, matchedMetricsChan chan<- *moira.MatchedMetric) error {
 	for line := range metricsChan {
 		if metric := m.patternStorage.ProcessIncomingMetric(line); metric != nil {
@@ -55,6 +67,15 @@ func (m *Matcher) worker(metricsChan <-chan []byte, matchedMetricsChan chan<- *m
 	return nil
 }
 
+func (m *Matcher) parsedMetricsWorker(parsedMetricsChan <-chan *filter.ParsedMetric, matchedMetricsChan chan<- *moira.MatchedMetric) error {
+	for parsedMetric := range parsedMetricsChan {
+		if metric := m.patternStorage.ProcessParsedMetric(parsedMetric); metric != nil {
+			matchedMetricsChan <- metric
+		}
+	}
+	return nil
+}
+
 func (m *Matcher) checkNewMetricsChannelLen(channel <-chan *moira.MatchedMetric) error {
 	checkTicker := time.NewTicker(time.Millisecond * 100) //nolint
 	for {
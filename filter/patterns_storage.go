@@ -6,6 +6,7 @@ import (
 
 	"github.com/moira-alert/moira"
 	"github.com/moira-alert/moira/metrics"
+	patternindex "github.com/moira-alert/moira/pattern_index"
 )
 
 // PatternStorage contains pattern tree
@@ -13,18 +14,32 @@ type PatternStorage struct {
 	database                moira.Database
 	metrics                 *metrics.FilterMetrics
 	logger                  moira.Logger
+	rewriter                *MetricRewriter
+	limiter                 *ingestionLimiter
+	timestampPolicy         *timestampPolicy
 	PatternIndex            atomic.Value
 	SeriesByTagPatternIndex atomic.Value
 }
 
 // NewPatternStorage creates new PatternStorage struct
-func NewPatternStorage(database moira.Database, metrics *metrics.FilterMetrics, logger moira.Logger) (*PatternStorage, error) {
+func NewPatternStorage(config *Config, database moira.Database, metrics *metrics.FilterMetrics, logger moira.Logger) (*PatternStorage, error) {
+	rewriter, err := NewMetricRewriter(config.RewriteRules)
+	if err != nil {
+		return nil, err
+	}
+	timestampPolicy, err := newTimestampPolicy(config.TimestampPolicy)
+	if err != nil {
+		return nil, err
+	}
 	storage := &PatternStorage{
-		database: database,
-		metrics:  metrics,
-		logger:   logger,
+		database:        database,
+		metrics:         metrics,
+		logger:          logger,
+		rewriter:        rewriter,
+		limiter:         newIngestionLimiter(config.Limits),
+		timestampPolicy: timestampPolicy,
 	}
-	err := storage.Refresh()
+	err = storage.Refresh()
 	return storage, err
 }
 
@@ -46,29 +61,81 @@ func (storage *PatternStorage) Refresh() error {
 		}
 	}
 
-	storage.PatternIndex.Store(NewPatternIndex(storage.logger, patterns))
+	storage.PatternIndex.Store(patternindex.NewPatternIndex(storage.logger, patterns))
 	storage.SeriesByTagPatternIndex.Store(NewSeriesByTagPatternIndex(seriesByTagPatterns))
+
+	now := time.Now().Unix()
+	if samples := storage.timestampPolicy.reportSamples(storage.logger, now); len(samples) > 0 {
+		if err := storage.database.SaveRejectedTimestampSamples(samples); err != nil {
+			storage.logger.Errorf("Failed to save rejected timestamp samples: %s", err.Error())
+		}
+	}
+	storage.limiter.cleanup(newPatterns, now)
+	if violations := storage.limiter.getChangedViolations(); len(violations) > 0 {
+		if err := storage.database.SaveIngestionLimitViolations(violations); err != nil {
+			storage.logger.Errorf("Failed to save ingestion limit violations: %s", err.Error())
+		}
+	}
 	return nil
 }
 
 // ProcessIncomingMetric validates, parses and matches incoming raw string
 func (storage *PatternStorage) ProcessIncomingMetric(lineBytes []byte) *moira.MatchedMetric {
 	storage.metrics.TotalMetricsReceived.Inc()
-	count := storage.metrics.TotalMetricsReceived.Count()
 
 	parsedMetric, err := ParseMetric(lineBytes)
 	if err != nil {
 		storage.logger.Infof("cannot parse input: %v", err)
 		return nil
 	}
+	return storage.processParsedMetric(parsedMetric)
+}
+
+// ProcessParsedMetric matches metric which is already parsed by listener, e.g. remote write sample
+func (storage *PatternStorage) ProcessParsedMetric(parsedMetric *ParsedMetric) *moira.MatchedMetric {
+	storage.metrics.TotalMetricsReceived.Inc()
+	return storage.processParsedMetric(parsedMetric)
+}
 
+func (storage *PatternStorage) processParsedMetric(parsedMetric *ParsedMetric) *moira.MatchedMetric {
+	count := storage.metrics.TotalMetricsReceived.Count()
 	storage.metrics.ValidMetricsReceived.Inc()
 
+	parsedMetric, reason := storage.timestampPolicy.apply(parsedMetric, time.Now().Unix())
+	switch reason {
+	case outdatedTimestampReason:
+		storage.metrics.OutdatedMetricsReceived.Inc()
+	case futureTimestampReason:
+		storage.metrics.FutureMetricsReceived.Inc()
+	}
+	if parsedMetric == nil {
+		return nil
+	}
+
+	rewrittenMetric := storage.rewriter.Rewrite(parsedMetric)
+	if rewrittenMetric == nil {
+		storage.metrics.DroppedMetricsReceived.Inc()
+		return nil
+	}
+	if rewrittenMetric != parsedMetric {
+		storage.metrics.RewrittenMetricsReceived.Inc()
+		parsedMetric = rewrittenMetric
+	}
+
 	matchingStart := time.Now()
+	if !storage.limiter.allowPoint(parsedMetric.Name, matchingStart.Unix()) {
+		storage.metrics.RateLimitedMetricsReceived.Inc()
+		return nil
+	}
 	matchedPatterns := storage.matchPatterns(parsedMetric)
 	if count%10 == 0 {
 		storage.metrics.MatchingTimer.UpdateSince(matchingStart)
 	}
+	allowedPatterns := storage.limiter.filterPatterns(parsedMetric.Metric, matchedPatterns, matchingStart.Unix())
+	if len(allowedPatterns) < len(matchedPatterns) {
+		storage.metrics.CardinalityLimitedMetricsReceived.Inc()
+		matchedPatterns = allowedPatterns
+	}
 	if len(matchedPatterns) > 0 {
 		storage.metrics.MatchingMetricsReceived.Inc()
 		return &moira.MatchedMetric{
@@ -84,7 +151,7 @@ func (storage *PatternStorage) ProcessIncomingMetric(lineBytes []byte) *moira.Ma
 }
 
 func (storage *PatternStorage) matchPatterns(metric *ParsedMetric) []string {
-	patternIndex := storage.PatternIndex.Load().(*PatternIndex)
+	patternIndex := storage.PatternIndex.Load().(*patternindex.PatternIndex)
 	seriesByTagPatternIndex := storage.SeriesByTagPatternIndex.Load().(*SeriesByTagPatternIndex)
 
 	matchedPatterns := make([]string, 0)
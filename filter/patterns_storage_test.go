@@ -3,6 +3,7 @@ package filter
 import (
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	logging "github.com/moira-alert/moira/logging/zerolog_adapter"
@@ -25,12 +26,12 @@ func TestProcessIncomingMetric(t *testing.T) {
 	Convey("Create new pattern storage, GetPatterns returns error, should error", t, func() {
 		database.EXPECT().GetPatterns().Return(nil, fmt.Errorf("some error here"))
 		metrics := metrics.ConfigureFilterMetrics(metrics.NewDummyRegistry())
-		_, err := NewPatternStorage(database, metrics, logger)
+		_, err := NewPatternStorage(&Config{}, database, metrics, logger)
 		So(err, ShouldBeError, fmt.Errorf("some error here"))
 	})
 
 	database.EXPECT().GetPatterns().Return(testPatterns, nil)
-	patternsStorage, err := NewPatternStorage(database, metrics.ConfigureFilterMetrics(metrics.NewDummyRegistry()), logger)
+	patternsStorage, err := NewPatternStorage(&Config{}, database, metrics.ConfigureFilterMetrics(metrics.NewDummyRegistry()), logger)
 
 	Convey("Create new pattern storage, should no error", t, func() {
 		So(err, ShouldBeEmpty)
@@ -70,5 +71,41 @@ func TestProcessIncomingMetric(t *testing.T) {
 		So(patternsStorage.metrics.MatchingTimer.Count(), ShouldEqual, 1)
 	})
 
+	Convey("When metric is rewritten, rewritten name should be matched", t, func() {
+		rewriter, err := NewMetricRewriter([]RewriteRule{
+			{Match: `^cpu\.old$`, Rename: "cpu.used"},
+			{Match: `^cpu\.debug$`, Drop: true},
+		})
+		So(err, ShouldBeNil)
+		patternsStorage.rewriter = rewriter
+		patternsStorage.metrics = metrics.ConfigureFilterMetrics(metrics.NewDummyRegistry())
+
+		matchedMetric := patternsStorage.ProcessIncomingMetric([]byte("cpu.old 12 1234567890"))
+		So(matchedMetric, ShouldNotBeNil)
+		So(matchedMetric.Metric, ShouldEqual, "cpu.used")
+		So(matchedMetric.Patterns, ShouldResemble, testPatterns)
+
+		matchedMetric = patternsStorage.ProcessIncomingMetric([]byte("cpu.debug 12 1234567890"))
+		So(matchedMetric, ShouldBeNil)
+		So(patternsStorage.metrics.RewrittenMetricsReceived.Count(), ShouldEqual, 1)
+		So(patternsStorage.metrics.DroppedMetricsReceived.Count(), ShouldEqual, 1)
+		So(patternsStorage.metrics.MatchingMetricsReceived.Count(), ShouldEqual, 1)
+	})
+
+	Convey("When metric timestamp is out of acceptance window, should apply timestamp policy", t, func() {
+		policy, err := newTimestampPolicy(TimestampPolicyConfig{MaxPast: time.Hour, MaxFuture: time.Minute, Action: TimestampActionDrop})
+		So(err, ShouldBeNil)
+		patternsStorage.timestampPolicy = policy
+		patternsStorage.metrics = metrics.ConfigureFilterMetrics(metrics.NewDummyRegistry())
+		now := time.Now().Unix()
+
+		So(patternsStorage.ProcessIncomingMetric([]byte(fmt.Sprintf("cpu.used 12 %d", now))), ShouldNotBeNil)
+		So(patternsStorage.ProcessIncomingMetric([]byte("cpu.used 12 1234567890")), ShouldBeNil)
+		So(patternsStorage.ProcessIncomingMetric([]byte(fmt.Sprintf("cpu.used 12 %d", now+3600))), ShouldBeNil)
+		So(patternsStorage.metrics.OutdatedMetricsReceived.Count(), ShouldEqual, 1)
+		So(patternsStorage.metrics.FutureMetricsReceived.Count(), ShouldEqual, 1)
+		So(patternsStorage.metrics.MatchingMetricsReceived.Count(), ShouldEqual, 1)
+	})
+
 	mockCtrl.Finish()
 }
@@ -0,0 +1,210 @@
+package filter
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/golang/snappy"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+const prometheusMetricNameLabel = "__name__"
+
+// Field numbers of Prometheus remote write protobuf messages, see prometheus/prompb/types.proto
+const (
+	writeRequestTimeseriesField protowire.Number = 1
+	timeSeriesLabelsField       protowire.Number = 1
+	timeSeriesSamplesField      protowire.Number = 2
+	labelNameField              protowire.Number = 1
+	labelValueField             protowire.Number = 2
+	sampleValueField            protowire.Number = 1
+	sampleTimestampField        protowire.Number = 2
+)
+
+type remoteWriteLabel struct {
+	name  string
+	value string
+}
+
+type remoteWriteSample struct {
+	value     float64
+	timestamp int64
+}
+
+// ParseRemoteWriteRequest parses snappy-compressed Prometheus remote write request body
+// Every sample of every time series becomes separate ParsedMetric, metric name is taken from __name__ label
+// and other labels are stored as metric labels. NaN samples (including Prometheus staleness markers) are skipped
+func ParseRemoteWriteRequest(compressed []byte) ([]*ParsedMetric, error) {
+	request, err := snappy.Decode(nil, compressed)
+	if err != nil {
+		return nil, fmt.Errorf("cannot decompress remote write request: %s", err)
+	}
+
+	parsedMetrics := make([]*ParsedMetric, 0)
+	err = consumeMessage(request, func(num protowire.Number, typ protowire.Type, value []byte) error {
+		if num != writeRequestTimeseriesField || typ != protowire.BytesType {
+			return nil
+		}
+		metrics, err := parseTimeSeries(value)
+		if err != nil {
+			return err
+		}
+		parsedMetrics = append(parsedMetrics, metrics...)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse remote write request: %s", err)
+	}
+	return parsedMetrics, nil
+}
+
+func parseTimeSeries(timeSeries []byte) ([]*ParsedMetric, error) {
+	labels := make([]remoteWriteLabel, 0)
+	samples := make([]remoteWriteSample, 0)
+	err := consumeMessage(timeSeries, func(num protowire.Number, typ protowire.Type, value []byte) error {
+		if typ != protowire.BytesType {
+			return nil
+		}
+		switch num {
+		case timeSeriesLabelsField:
+			label, err := parseLabel(value)
+			if err != nil {
+				return err
+			}
+			labels = append(labels, label)
+		case timeSeriesSamplesField:
+			sample, err := parseSample(value)
+			if err != nil {
+				return err
+			}
+			samples = append(samples, sample)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	name, metricLabels := "", make(map[string]string)
+	for _, label := range labels {
+		if label.name == prometheusMetricNameLabel {
+			name = sanitizeGraphiteString(label.value)
+			continue
+		}
+		metricLabels[sanitizeGraphiteString(label.name)] = sanitizeGraphiteString(label.value)
+	}
+	if name == "" {
+		return nil, fmt.Errorf("time series without %s label", prometheusMetricNameLabel)
+	}
+	metric := BuildTaggedMetric(name, metricLabels)
+
+	parsedMetrics := make([]*ParsedMetric, 0, len(samples))
+	for _, sample := range samples {
+		if math.IsNaN(sample.value) {
+			continue
+		}
+		parsedMetrics = append(parsedMetrics, &ParsedMetric{
+			Metric:    metric,
+			Name:      name,
+			Labels:    metricLabels,
+			Value:     sample.value,
+			Timestamp: sample.timestamp / 1000, //nolint
+		})
+	}
+	return parsedMetrics, nil
+}
+
+func parseLabel(labelBytes []byte) (remoteWriteLabel, error) {
+	label := remoteWriteLabel{}
+	err := consumeMessage(labelBytes, func(num protowire.Number, typ protowire.Type, value []byte) error {
+		if typ != protowire.BytesType {
+			return nil
+		}
+		switch num {
+		case labelNameField:
+			label.name = string(value)
+		case labelValueField:
+			label.value = string(value)
+		}
+		return nil
+	})
+	if err != nil {
+		return label, err
+	}
+	if label.name == "" {
+		return label, fmt.Errorf("empty label name")
+	}
+	return label, nil
+}
+
+func parseSample(sampleBytes []byte) (remoteWriteSample, error) {
+	sample := remoteWriteSample{}
+	err := consumeMessage(sampleBytes, func(num protowire.Number, typ protowire.Type, value []byte) error {
+		switch {
+		case num == sampleValueField && typ == protowire.Fixed64Type:
+			bits, _ := protowire.ConsumeFixed64(value)
+			sample.value = math.Float64frombits(bits)
+		case num == sampleTimestampField && typ == protowire.VarintType:
+			timestamp, _ := protowire.ConsumeVarint(value)
+			sample.timestamp = int64(timestamp)
+		}
+		return nil
+	})
+	return sample, err
+}
+
+// consumeMessage iterates over protobuf message fields and calls handle with raw field value
+// For length-delimited fields value contains only payload without length prefix
+func consumeMessage(message []byte, handle func(num protowire.Number, typ protowire.Type, value []byte) error) error {
+	for len(message) > 0 {
+		num, typ, tagLength := protowire.ConsumeTag(message)
+		if tagLength < 0 {
+			return protowire.ParseError(tagLength)
+		}
+		message = message[tagLength:]
+		valueLength := protowire.ConsumeFieldValue(num, typ, message)
+		if valueLength < 0 {
+			return protowire.ParseError(valueLength)
+		}
+		value := message[:valueLength]
+		if typ == protowire.BytesType {
+			value, _ = protowire.ConsumeBytes(value)
+		}
+		if err := handle(num, typ, value); err != nil {
+			return err
+		}
+		message = message[valueLength:]
+	}
+	return nil
+}
+
+// BuildTaggedMetric returns graphite tagged metric name with labels sorted by name
+func BuildTaggedMetric(name string, labels map[string]string) string {
+	labelNames := make([]string, 0, len(labels))
+	for labelName := range labels {
+		labelNames = append(labelNames, labelName)
+	}
+	sort.Strings(labelNames)
+
+	var metric strings.Builder
+	metric.WriteString(name)
+	for _, labelName := range labelNames {
+		metric.WriteString(";")
+		metric.WriteString(labelName)
+		metric.WriteString("=")
+		metric.WriteString(labels[labelName])
+	}
+	return metric.String()
+}
+
+// sanitizeGraphiteString replaces characters that are not allowed in graphite plaintext protocol with underscore
+func sanitizeGraphiteString(input string) string {
+	return strings.Map(func(r rune) rune {
+		if r <= ' ' || r > '~' || r == ';' {
+			return '_'
+		}
+		return r
+	}, input)
+}
@@ -0,0 +1,114 @@
+package filter
+
+import (
+	"math"
+	"testing"
+
+	"github.com/golang/snappy"
+	. "github.com/smartystreets/goconvey/convey"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+type testRemoteWriteSeries struct {
+	labels  [][2]string
+	samples []remoteWriteSample
+}
+
+func buildRemoteWriteRequest(series ...testRemoteWriteSeries) []byte {
+	request := make([]byte, 0)
+	for _, timeSeries := range series {
+		timeSeriesBytes := make([]byte, 0)
+		for _, label := range timeSeries.labels {
+			labelBytes := protowire.AppendTag(nil, labelNameField, protowire.BytesType)
+			labelBytes = protowire.AppendString(labelBytes, label[0])
+			labelBytes = protowire.AppendTag(labelBytes, labelValueField, protowire.BytesType)
+			labelBytes = protowire.AppendString(labelBytes, label[1])
+			timeSeriesBytes = protowire.AppendTag(timeSeriesBytes, timeSeriesLabelsField, protowire.BytesType)
+			timeSeriesBytes = protowire.AppendBytes(timeSeriesBytes, labelBytes)
+		}
+		for _, sample := range timeSeries.samples {
+			sampleBytes := protowire.AppendTag(nil, sampleValueField, protowire.Fixed64Type)
+			sampleBytes = protowire.AppendFixed64(sampleBytes, math.Float64bits(sample.value))
+			sampleBytes = protowire.AppendTag(sampleBytes, sampleTimestampField, protowire.VarintType)
+			sampleBytes = protowire.AppendVarint(sampleBytes, uint64(sample.timestamp))
+			timeSeriesBytes = protowire.AppendTag(timeSeriesBytes, timeSeriesSamplesField, protowire.BytesType)
+			timeSeriesBytes = protowire.AppendBytes(timeSeriesBytes, sampleBytes)
+		}
+		request = protowire.AppendTag(request, writeRequestTimeseriesField, protowire.BytesType)
+		request = protowire.AppendBytes(request, timeSeriesBytes)
+	}
+	return snappy.Encode(nil, request)
+}
+
+func TestParseRemoteWriteRequest(t *testing.T) {
+	Convey("Given invalid remote write requests, should return errors", t, func() {
+		Convey("Not snappy-compressed body", func() {
+			_, err := ParseRemoteWriteRequest([]byte("One.two.three 123 1234567890"))
+			So(err, ShouldBeError)
+		})
+
+		Convey("Broken protobuf message", func() {
+			_, err := ParseRemoteWriteRequest(snappy.Encode(nil, []byte{0x0a, 0xff}))
+			So(err, ShouldBeError)
+		})
+
+		Convey("Time series without name", func() {
+			request := buildRemoteWriteRequest(testRemoteWriteSeries{
+				labels:  [][2]string{{"job", "node"}},
+				samples: []remoteWriteSample{{value: 1, timestamp: 1234567890000}},
+			})
+			_, err := ParseRemoteWriteRequest(request)
+			So(err, ShouldBeError)
+		})
+	})
+
+	Convey("Given valid remote write request, should return parsed metrics", t, func() {
+		request := buildRemoteWriteRequest(
+			testRemoteWriteSeries{
+				labels: [][2]string{{"__name__", "node_load1"}, {"job", "node"}, {"instance", "host:9100"}},
+				samples: []remoteWriteSample{
+					{value: 0.5, timestamp: 1234567890000},
+					{value: math.NaN(), timestamp: 1234567950000},
+					{value: 1.5, timestamp: 1234568010123},
+				},
+			},
+			testRemoteWriteSeries{
+				labels:  [][2]string{{"__name__", "up"}, {"path", "/var/lib; data"}},
+				samples: []remoteWriteSample{{value: 1, timestamp: 1234567890000}},
+			},
+		)
+		parsedMetrics, err := ParseRemoteWriteRequest(request)
+		So(err, ShouldBeNil)
+		So(parsedMetrics, ShouldResemble, []*ParsedMetric{
+			{
+				Metric:    "node_load1;instance=host:9100;job=node",
+				Name:      "node_load1",
+				Labels:    map[string]string{"job": "node", "instance": "host:9100"},
+				Value:     0.5,
+				Timestamp: 1234567890,
+			},
+			{
+				Metric:    "node_load1;instance=host:9100;job=node",
+				Name:      "node_load1",
+				Labels:    map[string]string{"job": "node", "instance": "host:9100"},
+				Value:     1.5,
+				Timestamp: 1234568010,
+			},
+			{
+				Metric:    "up;path=/var/lib__data",
+				Name:      "up",
+				Labels:    map[string]string{"path": "/var/lib__data"},
+				Value:     1,
+				Timestamp: 1234567890,
+			},
+		})
+
+		Convey("Parsed metrics should be convertible to plaintext protocol", func() {
+			for _, parsedMetric := range parsedMetrics {
+				reparsedMetric, err := ParseMetric(parsedMetric.Bytes())
+				So(err, ShouldBeNil)
+				So(reparsedMetric, ShouldResemble, parsedMetric)
+			}
+		})
+	})
+}
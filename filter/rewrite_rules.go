@@ -0,0 +1,93 @@
+package filter
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// RewriteRule describes how to change metric before pattern matching
+// Rule is applied to metrics with name matched by Match regular expression
+type RewriteRule struct {
+	// Regular expression to match metric name
+	Match string
+	// Replacement for matched metric name, can contain $1-like references to Match submatches. Name is not changed if empty
+	Rename string
+	// Tags to add to metric, existing tags with the same names are overwritten
+	AddTags map[string]string
+	// Names of tags to remove from metric
+	DropTags []string
+	// If true, metric is dropped and is not matched at all
+	Drop bool
+}
+
+type compiledRewriteRule struct {
+	RewriteRule
+	regexp *regexp.Regexp
+}
+
+// MetricRewriter applies rewrite rules to parsed metrics
+type MetricRewriter struct {
+	rules []compiledRewriteRule
+}
+
+// NewMetricRewriter compiles rewrite rules
+func NewMetricRewriter(rules []RewriteRule) (*MetricRewriter, error) {
+	rewriter := &MetricRewriter{rules: make([]compiledRewriteRule, 0, len(rules))}
+	for _, rule := range rules {
+		ruleRegexp, err := regexp.Compile(rule.Match)
+		if err != nil {
+			return nil, fmt.Errorf("invalid rewrite rule match '%s': %s", rule.Match, err.Error())
+		}
+		rewriter.rules = append(rewriter.rules, compiledRewriteRule{RewriteRule: rule, regexp: ruleRegexp})
+	}
+	return rewriter, nil
+}
+
+// Rewrite applies all matching rules to metric in order of their declaration
+// Returns the same metric if no rule matched and nil if metric must be dropped
+func (rewriter *MetricRewriter) Rewrite(metric *ParsedMetric) *ParsedMetric {
+	var rewritten *ParsedMetric
+	for _, rule := range rewriter.rules {
+		name := metric.Name
+		if rewritten != nil {
+			name = rewritten.Name
+		}
+		if !rule.regexp.MatchString(name) {
+			continue
+		}
+		if rule.Drop {
+			return nil
+		}
+		if rewritten == nil {
+			rewritten = copyParsedMetric(metric)
+		}
+		if rule.Rename != "" {
+			rewritten.Name = rule.regexp.ReplaceAllString(name, rule.Rename)
+		}
+		for _, tag := range rule.DropTags {
+			delete(rewritten.Labels, tag)
+		}
+		for tag, value := range rule.AddTags {
+			rewritten.Labels[tag] = value
+		}
+	}
+	if rewritten == nil {
+		return metric
+	}
+	rewritten.Metric = BuildTaggedMetric(rewritten.Name, rewritten.Labels)
+	return rewritten
+}
+
+func copyParsedMetric(metric *ParsedMetric) *ParsedMetric {
+	labels := make(map[string]string, len(metric.Labels))
+	for name, value := range metric.Labels {
+		labels[name] = value
+	}
+	return &ParsedMetric{
+		Metric:    metric.Metric,
+		Name:      metric.Name,
+		Labels:    labels,
+		Value:     metric.Value,
+		Timestamp: metric.Timestamp,
+	}
+}
@@ -0,0 +1,55 @@
+package filter
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestMetricRewriter(t *testing.T) {
+	Convey("Given invalid rule, should return error", t, func() {
+		_, err := NewMetricRewriter([]RewriteRule{{Match: "(unclosed"}})
+		So(err, ShouldBeError)
+	})
+
+	rewriter, err := NewMetricRewriter([]RewriteRule{
+		{Match: `^Old\.team\.(.*)$`, Rename: "New.team.$1", AddTags: map[string]string{"team": "new"}},
+		{Match: `^New\.team\.`, DropTags: []string{"host"}},
+		{Match: `^Debug\.`, Drop: true},
+	})
+
+	Convey("Given valid rules", t, func() {
+		So(err, ShouldBeNil)
+
+		Convey("Not matched metric should be returned as is", func() {
+			metric, _ := ParseMetric([]byte("Other.team.cpu;host=a 1 1234567890"))
+			So(rewriter.Rewrite(metric), ShouldEqual, metric)
+		})
+
+		Convey("Metric matched by drop rule should be dropped", func() {
+			metric, _ := ParseMetric([]byte("Debug.cpu 1 1234567890"))
+			So(rewriter.Rewrite(metric), ShouldBeNil)
+		})
+
+		Convey("Matched metric should be renamed and retagged by all matching rules", func() {
+			metric, _ := ParseMetric([]byte("Old.team.cpu;host=a;dc=b 1 1234567890"))
+			So(rewriter.Rewrite(metric), ShouldResemble, &ParsedMetric{
+				Metric:    "New.team.cpu;dc=b;team=new",
+				Name:      "New.team.cpu",
+				Labels:    map[string]string{"dc": "b", "team": "new"},
+				Value:     1,
+				Timestamp: 1234567890,
+			})
+
+			Convey("Original metric should not be changed", func() {
+				So(metric.Metric, ShouldEqual, "Old.team.cpu;host=a;dc=b")
+				So(metric.Labels, ShouldResemble, map[string]string{"host": "a", "dc": "b"})
+			})
+		})
+
+		Convey("Untagged metric should stay untagged after rename", func() {
+			metric, _ := ParseMetric([]byte("New.team.cpu 1 1234567890"))
+			So(rewriter.Rewrite(metric).Metric, ShouldEqual, "New.team.cpu")
+		})
+	})
+}
@@ -0,0 +1,165 @@
+package statsd
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Aggregated values of every metric type are flushed with own name prefix, so metrics of different types never collide
+const (
+	countersPrefix = "counters."
+	gaugesPrefix   = "gauges."
+	timersPrefix   = "timers."
+	setsPrefix     = "sets."
+)
+
+// gaugeTTL is time after which gauge which was not updated is forgotten
+const gaugeTTL = time.Hour
+
+// aggregator accumulates StatsD samples between flushes
+type aggregator struct {
+	mutex         sync.Mutex
+	counters      map[string]float64
+	gauges        map[string]float64
+	gaugesUpdated map[string]time.Time
+	updated       map[string]struct{}
+	timers        map[string][]float64
+	timerCounts   map[string]float64
+	sets          map[string]map[string]struct{}
+	percentiles   []float64
+}
+
+func newAggregator(percentiles []float64) *aggregator {
+	aggregator := &aggregator{percentiles: percentiles}
+	aggregator.reset()
+	return aggregator
+}
+
+func (aggregator *aggregator) reset() {
+	aggregator.counters = make(map[string]float64)
+	aggregator.timers = make(map[string][]float64)
+	aggregator.timerCounts = make(map[string]float64)
+	aggregator.sets = make(map[string]map[string]struct{})
+	aggregator.updated = make(map[string]struct{})
+	if aggregator.gauges == nil {
+		aggregator.gauges = make(map[string]float64)
+		aggregator.gaugesUpdated = make(map[string]time.Time)
+	}
+}
+
+func (aggregator *aggregator) add(sample *sample) {
+	aggregator.mutex.Lock()
+	defer aggregator.mutex.Unlock()
+
+	switch sample.metricType {
+	case counterType:
+		aggregator.counters[sample.name] += sample.value / sample.sampleRate
+	case gaugeType:
+		if sample.relative {
+			aggregator.gauges[sample.name] += sample.value
+		} else {
+			aggregator.gauges[sample.name] = sample.value
+		}
+		aggregator.updated[sample.name] = struct{}{}
+	case timerType, histogramType:
+		aggregator.timers[sample.name] = append(aggregator.timers[sample.name], sample.value)
+		aggregator.timerCounts[sample.name] += 1 / sample.sampleRate
+	case setType:
+		if _, ok := aggregator.sets[sample.name]; !ok {
+			aggregator.sets[sample.name] = make(map[string]struct{})
+		}
+		aggregator.sets[sample.name][sample.setValue] = struct{}{}
+	}
+}
+
+// flush returns graphite plaintext lines with values aggregated since previous flush and resets aggregated values
+// Gauges keep their last values to apply relative changes to, but are flushed only if were updated since previous flush
+// Gauges which were not updated during gaugeTTL are forgotten
+func (aggregator *aggregator) flush(now time.Time, interval time.Duration) [][]byte {
+	aggregator.mutex.Lock()
+	counters, timers, timerCounts, sets := aggregator.counters, aggregator.timers, aggregator.timerCounts, aggregator.sets
+	flushedGauges := make(map[string]float64, len(aggregator.updated))
+	for name := range aggregator.updated {
+		flushedGauges[name] = aggregator.gauges[name]
+		aggregator.gaugesUpdated[name] = now
+	}
+	for name, updated := range aggregator.gaugesUpdated {
+		if now.Sub(updated) > gaugeTTL {
+			delete(aggregator.gauges, name)
+			delete(aggregator.gaugesUpdated, name)
+		}
+	}
+	aggregator.reset()
+	aggregator.mutex.Unlock()
+
+	timestamp := now.Unix()
+	lines := make([][]byte, 0, len(counters)*2+len(flushedGauges)+len(timers)*(5+len(aggregator.percentiles))+len(sets)) //nolint
+	for name, value := range counters {
+		lines = append(lines, formatLine(countersPrefix+withSuffix(name, "count"), value, timestamp))
+		lines = append(lines, formatLine(countersPrefix+withSuffix(name, "rate"), value/interval.Seconds(), timestamp))
+	}
+	for name, value := range flushedGauges {
+		lines = append(lines, formatLine(gaugesPrefix+name, value, timestamp))
+	}
+	for name, values := range timers {
+		lines = append(lines, aggregator.flushTimer(timersPrefix+name, values, timerCounts[name], timestamp)...)
+	}
+	for name, values := range sets {
+		lines = append(lines, formatLine(setsPrefix+withSuffix(name, "count"), float64(len(values)), timestamp))
+	}
+	return lines
+}
+
+// flushTimer returns timer aggregations, count is number of timer values adjusted with their sample rates
+func (aggregator *aggregator) flushTimer(name string, values []float64, count float64, timestamp int64) [][]byte {
+	sort.Float64s(values)
+	sum := 0.0
+	for _, value := range values {
+		sum += value
+	}
+	lines := [][]byte{
+		formatLine(withSuffix(name, "count"), count, timestamp),
+		formatLine(withSuffix(name, "sum"), sum, timestamp),
+		formatLine(withSuffix(name, "mean"), sum/float64(len(values)), timestamp),
+		formatLine(withSuffix(name, "min"), values[0], timestamp),
+		formatLine(withSuffix(name, "max"), values[len(values)-1], timestamp),
+	}
+	for _, percentile := range aggregator.percentiles {
+		lines = append(lines, formatLine(withSuffix(name, percentileSuffix(percentile)), getPercentile(values, percentile), timestamp))
+	}
+	return lines
+}
+
+// getPercentile returns nearest-rank percentile of sorted values
+func getPercentile(sortedValues []float64, percentile float64) float64 {
+	rank := int(math.Ceil(percentile / 100 * float64(len(sortedValues)))) //nolint
+	if rank < 1 {
+		rank = 1
+	}
+	if rank > len(sortedValues) {
+		rank = len(sortedValues)
+	}
+	return sortedValues[rank-1]
+}
+
+// percentileSuffix returns metric name suffix for percentile, e.g. "p90" for 90 and "p99_9" for 99.9
+func percentileSuffix(percentile float64) string {
+	return "p" + strings.Replace(strconv.FormatFloat(percentile, 'f', -1, 64), ".", "_", 1)
+}
+
+// withSuffix adds suffix node to metric name keeping graphite tags in the end
+func withSuffix(name string, suffix string) string {
+	if tagsStart := strings.IndexByte(name, ';'); tagsStart >= 0 {
+		return name[:tagsStart] + "." + suffix + name[tagsStart:]
+	}
+	return name + "." + suffix
+}
+
+func formatLine(name string, value float64, timestamp int64) []byte {
+	return []byte(fmt.Sprintf("%s %s %d", name, strconv.FormatFloat(value, 'f', -1, 64), timestamp))
+}
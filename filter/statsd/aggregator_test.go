@@ -0,0 +1,127 @@
+package statsd
+
+import (
+	"sort"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func flushLines(aggregator *aggregator, now time.Time, interval time.Duration) []string {
+	lines := make([]string, 0)
+	for _, line := range aggregator.flush(now, interval) {
+		lines = append(lines, string(line))
+	}
+	sort.Strings(lines)
+	return lines
+}
+
+func addLines(aggregator *aggregator, lines ...string) {
+	for _, line := range lines {
+		sample, err := parseLine([]byte(line))
+		So(err, ShouldBeNil)
+		aggregator.add(sample)
+	}
+}
+
+func TestAggregator(t *testing.T) {
+	now := time.Unix(1234567890, 0)
+
+	Convey("Empty aggregator should flush nothing", t, func() {
+		So(flushLines(newAggregator(nil), now, 10*time.Second), ShouldBeEmpty)
+	})
+
+	Convey("Counters should be summed with sample rate and reset on flush", t, func() {
+		aggregator := newAggregator(nil)
+		addLines(aggregator, "requests:1|c", "requests:2|c|@0.5", "requests:1|c|#dc:north", "requests:1|c|#dc:north")
+		So(flushLines(aggregator, now, 10*time.Second), ShouldResemble, []string{
+			"counters.requests.count 5 1234567890",
+			"counters.requests.count;dc=north 2 1234567890",
+			"counters.requests.rate 0.5 1234567890",
+			"counters.requests.rate;dc=north 0.2 1234567890",
+		})
+		So(flushLines(aggregator, now, 10*time.Second), ShouldBeEmpty)
+	})
+
+	Convey("Gauges should keep last value and be flushed only if updated", t, func() {
+		aggregator := newAggregator(nil)
+		addLines(aggregator, "load:3|g", "load:5|g", "load:-1|g")
+		So(flushLines(aggregator, now, 10*time.Second), ShouldResemble, []string{"gauges.load 4 1234567890"})
+		So(flushLines(aggregator, now, 10*time.Second), ShouldBeEmpty)
+		addLines(aggregator, "load:+2|g")
+		So(flushLines(aggregator, now, 10*time.Second), ShouldResemble, []string{"gauges.load 6 1234567890"})
+	})
+
+	Convey("Gauges which were not updated during ttl should be forgotten", t, func() {
+		aggregator := newAggregator(nil)
+		addLines(aggregator, "load:3|g")
+		flushLines(aggregator, now, 10*time.Second)
+		flushLines(aggregator, now.Add(gaugeTTL), 10*time.Second)
+		So(aggregator.gauges, ShouldContainKey, "load")
+		flushLines(aggregator, now.Add(gaugeTTL+time.Second), 10*time.Second)
+		So(aggregator.gauges, ShouldBeEmpty)
+		addLines(aggregator, "load:+2|g")
+		So(flushLines(aggregator, now, 10*time.Second), ShouldResemble, []string{"gauges.load 2 1234567890"})
+	})
+
+	Convey("Timers should be aggregated with percentiles", t, func() {
+		aggregator := newAggregator([]float64{50, 90, 99.9})
+		addLines(aggregator, "time:5|ms", "time:1|ms", "time:4|ms", "time:2|ms", "time:3|ms")
+		So(flushLines(aggregator, now, 10*time.Second), ShouldResemble, []string{
+			"timers.time.count 5 1234567890",
+			"timers.time.max 5 1234567890",
+			"timers.time.mean 3 1234567890",
+			"timers.time.min 1 1234567890",
+			"timers.time.p50 3 1234567890",
+			"timers.time.p90 5 1234567890",
+			"timers.time.p99_9 5 1234567890",
+			"timers.time.sum 15 1234567890",
+		})
+	})
+
+	Convey("Timers count should be adjusted with sample rate", t, func() {
+		aggregator := newAggregator(nil)
+		addLines(aggregator, "time:1|ms|@0.1", "time:3|ms|@0.5")
+		So(flushLines(aggregator, now, 10*time.Second), ShouldResemble, []string{
+			"timers.time.count 12 1234567890",
+			"timers.time.max 3 1234567890",
+			"timers.time.mean 2 1234567890",
+			"timers.time.min 1 1234567890",
+			"timers.time.sum 4 1234567890",
+		})
+	})
+
+	Convey("Metrics of different types with the same name should not collide", t, func() {
+		aggregator := newAggregator(nil)
+		addLines(aggregator, "name:1|c", "name:1|ms", "name:john|s", "name:1|g")
+		So(flushLines(aggregator, now, 10*time.Second), ShouldResemble, []string{
+			"counters.name.count 1 1234567890",
+			"counters.name.rate 0.1 1234567890",
+			"gauges.name 1 1234567890",
+			"sets.name.count 1 1234567890",
+			"timers.name.count 1 1234567890",
+			"timers.name.max 1 1234567890",
+			"timers.name.mean 1 1234567890",
+			"timers.name.min 1 1234567890",
+			"timers.name.sum 1 1234567890",
+		})
+	})
+
+	Convey("Sets should count unique values", t, func() {
+		aggregator := newAggregator(nil)
+		addLines(aggregator, "users:john|s", "users:jane|s", "users:john|s")
+		So(flushLines(aggregator, now, 10*time.Second), ShouldResemble, []string{"sets.users.count 2 1234567890"})
+	})
+}
+
+func TestGetPercentile(t *testing.T) {
+	Convey("Should return nearest-rank percentile", t, func() {
+		values := []float64{15, 20, 35, 40, 50}
+		So(getPercentile(values, 5), ShouldEqual, 15)
+		So(getPercentile(values, 30), ShouldEqual, 20)
+		So(getPercentile(values, 40), ShouldEqual, 20)
+		So(getPercentile(values, 50), ShouldEqual, 35)
+		So(getPercentile(values, 100), ShouldEqual, 50)
+	})
+}
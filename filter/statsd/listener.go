@@ -0,0 +1,97 @@
+package statsd
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+
+	"gopkg.in/tomb.v2"
+
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/filter/connection"
+	"github.com/moira-alert/moira/metrics"
+)
+
+// Listener receives StatsD metrics over UDP and periodically sends aggregated values as graphite plaintext lines
+type Listener struct {
+	udpListener   *connection.UDPListener
+	aggregator    *aggregator
+	flushInterval time.Duration
+	logger        moira.Logger
+	tomb          tomb.Tomb
+	metrics       *metrics.FilterMetrics
+}
+
+// NewListener creates new StatsD listener
+// Timers are aggregated to count, sum, mean, min, max and given percentiles
+func NewListener(port string, flushInterval time.Duration, percentiles []float64, logger moira.Logger, metrics *metrics.FilterMetrics) (*Listener, error) {
+	if flushInterval <= 0 {
+		return nil, fmt.Errorf("flush interval must be positive, got %s", flushInterval)
+	}
+	for _, percentile := range percentiles {
+		if percentile <= 0 || percentile > 100 {
+			return nil, fmt.Errorf("percentile must be in (0, 100] range, got %v", percentile)
+		}
+	}
+	udpListener, err := connection.NewUDPListener(port, logger, metrics)
+	if err != nil {
+		return nil, err
+	}
+	return &Listener{
+		udpListener:   udpListener,
+		aggregator:    newAggregator(percentiles),
+		flushInterval: flushInterval,
+		logger:        logger,
+		metrics:       metrics,
+	}, nil
+}
+
+// Listen aggregates received StatsD metrics and sends aggregated values to lineChan every flush interval
+// lineChan is not closed on Stop, so this listener must be stopped before the one which owns lineChan
+func (listener *Listener) Listen(lineChan chan<- []byte) {
+	listener.udpListener.ListenFunc(listener.handleLine)
+	listener.tomb.Go(func() error {
+		flushTicker := time.NewTicker(listener.flushInterval)
+		defer flushTicker.Stop()
+		for {
+			select {
+			case <-listener.tomb.Dying():
+				listener.flush(lineChan, time.Now())
+				return nil
+			case now := <-flushTicker.C:
+				listener.flush(lineChan, now)
+			}
+		}
+	})
+	listener.logger.Info("Moira Filter StatsD Listener Started")
+}
+
+// Stop stops receiving metrics and flushes values aggregated so far
+func (listener *Listener) Stop() error {
+	err := listener.udpListener.Stop()
+	listener.tomb.Kill(nil)
+	if flushErr := listener.tomb.Wait(); flushErr != nil {
+		return flushErr
+	}
+	return err
+}
+
+func (listener *Listener) handleLine(line []byte) {
+	line = bytes.TrimSpace(line)
+	if len(line) == 0 {
+		return
+	}
+	listener.metrics.StatsDMetricsReceived.Inc()
+	sample, err := parseLine(line)
+	if err != nil {
+		listener.logger.Infof("cannot parse StatsD input: %v", err)
+		return
+	}
+	listener.aggregator.add(sample)
+}
+
+func (listener *Listener) flush(lineChan chan<- []byte, now time.Time) {
+	for _, line := range listener.aggregator.flush(now, listener.flushInterval) {
+		lineChan <- line
+	}
+}
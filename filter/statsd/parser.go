@@ -0,0 +1,95 @@
+package statsd
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/moira-alert/moira/filter"
+)
+
+// Metric types supported by StatsD protocol
+const (
+	counterType   = "c"
+	gaugeType     = "g"
+	timerType     = "ms"
+	histogramType = "h"
+	setType       = "s"
+)
+
+// sample is one parsed StatsD line
+type sample struct {
+	name       string
+	metricType string
+	value      float64
+	setValue   string
+	sampleRate float64
+	// Gauge value with explicit sign is a delta to current gauge value
+	relative bool
+}
+
+// parseLine parses StatsD line: "<name>:<value>|<type>[|@<sample rate>][|#<tag>:<value>,...]"
+// DogStatsD tags are converted to graphite tags, so name of tagged metric is "<name>;<tag>=<value>"
+func parseLine(line []byte) (*sample, error) {
+	colon := bytes.LastIndexByte(line, ':')
+	if pipe := bytes.IndexByte(line, '|'); pipe >= 0 {
+		colon = bytes.LastIndexByte(line[:pipe], ':')
+	}
+	if colon <= 0 {
+		return nil, fmt.Errorf("no metric name: '%s'", line)
+	}
+	name := string(line[:colon])
+	if strings.ContainsAny(name, " ;") {
+		return nil, fmt.Errorf("invalid metric name: '%s'", line)
+	}
+
+	parts := strings.Split(string(line[colon+1:]), "|")
+	if len(parts) < 2 { //nolint
+		return nil, fmt.Errorf("no metric type: '%s'", line)
+	}
+	parsed := &sample{name: name, metricType: parts[1], sampleRate: 1}
+	tags := make(map[string]string)
+	for _, part := range parts[2:] {
+		switch {
+		case strings.HasPrefix(part, "@"):
+			sampleRate, err := strconv.ParseFloat(part[1:], 64)
+			if err != nil || sampleRate <= 0 || sampleRate > 1 {
+				return nil, fmt.Errorf("invalid sample rate: '%s'", line)
+			}
+			parsed.sampleRate = sampleRate
+		case strings.HasPrefix(part, "#"):
+			for _, tag := range strings.Split(part[1:], ",") {
+				tagName, tagValue := tag, ""
+				if separator := strings.IndexByte(tag, ':'); separator >= 0 {
+					tagName, tagValue = tag[:separator], tag[separator+1:]
+				}
+				if tagName == "" {
+					return nil, fmt.Errorf("empty tag name: '%s'", line)
+				}
+				tags[tagName] = tagValue
+			}
+		default:
+			return nil, fmt.Errorf("unknown line section '%s': '%s'", part, line)
+		}
+	}
+	parsed.name = filter.BuildTaggedMetric(name, tags)
+
+	value := parts[0]
+	switch parsed.metricType {
+	case setType:
+		parsed.setValue = value
+		return parsed, nil
+	case gaugeType:
+		parsed.relative = strings.HasPrefix(value, "+") || strings.HasPrefix(value, "-")
+	case counterType, timerType, histogramType:
+	default:
+		return nil, fmt.Errorf("unknown metric type '%s': '%s'", parsed.metricType, line)
+	}
+	floatValue, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse value: '%s' (%s)", line, err)
+	}
+	parsed.value = floatValue
+	return parsed, nil
+}
@@ -0,0 +1,52 @@
+package statsd
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestParseLine(t *testing.T) {
+	Convey("Given invalid StatsD lines, should return errors", t, func() {
+		invalidLines := []string{
+			"",
+			"no.value",
+			":1|c",
+			"no.type:1",
+			"unknown.type:1|x",
+			"invalid.value:abc|c",
+			"invalid.rate:1|c|@abc",
+			"zero.rate:1|c|@0",
+			"too.big.rate:1|c|@2",
+			"unknown.section:1|c|abc",
+			"empty.tag.name:1|c|#:value",
+			"space in.name:1|c",
+			"semicolon;in.name:1|c",
+		}
+		for _, line := range invalidLines {
+			_, err := parseLine([]byte(line))
+			So(err, ShouldBeError)
+		}
+	})
+
+	Convey("Given valid StatsD lines, should return parsed samples", t, func() {
+		validLines := map[string]*sample{
+			"requests:1|c":                       {name: "requests", metricType: counterType, value: 1, sampleRate: 1},
+			"requests:2|c|@0.5":                  {name: "requests", metricType: counterType, value: 2, sampleRate: 0.5},
+			"load:1.5|g":                         {name: "load", metricType: gaugeType, value: 1.5, sampleRate: 1},
+			"load:-0.5|g":                        {name: "load", metricType: gaugeType, value: -0.5, sampleRate: 1, relative: true},
+			"load:+2|g":                          {name: "load", metricType: gaugeType, value: 2, sampleRate: 1, relative: true},
+			"response.time:320|ms":               {name: "response.time", metricType: timerType, value: 320, sampleRate: 1},
+			"response.size:1024|h":               {name: "response.size", metricType: histogramType, value: 1024, sampleRate: 1},
+			"users:john|s":                       {name: "users", metricType: setType, setValue: "john", sampleRate: 1},
+			"requests:1|c|#status:200,dc:north":  {name: "requests;dc=north;status=200", metricType: counterType, value: 1, sampleRate: 1},
+			"requests:1|c|@0.1|#canary":          {name: "requests;canary=", metricType: counterType, value: 1, sampleRate: 0.1},
+			"requests:1|c|#url:http://localhost": {name: "requests;url=http://localhost", metricType: counterType, value: 1, sampleRate: 1},
+		}
+		for line, expected := range validLines {
+			parsed, err := parseLine([]byte(line))
+			So(err, ShouldBeNil)
+			So(parsed, ShouldResemble, expected)
+		}
+	})
+}
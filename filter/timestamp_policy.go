@@ -0,0 +1,131 @@
+package filter
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/moira-alert/moira"
+)
+
+// TimestampAction is action applied to points with timestamps out of acceptance window
+type TimestampAction string
+
+// Actions applied to points with timestamps out of acceptance window
+const (
+	// TimestampActionPass saves point as is
+	TimestampActionPass TimestampAction = "pass"
+	// TimestampActionDrop drops point
+	TimestampActionDrop TimestampAction = "drop"
+	// TimestampActionClamp replaces point timestamp with current time
+	TimestampActionClamp TimestampAction = "clamp"
+)
+
+// Reasons of points to be out of acceptance window
+const (
+	outdatedTimestampReason = "outdated"
+	futureTimestampReason   = "future"
+)
+
+const timestampSamplesReportPeriod = 60
+
+// TimestampPolicyConfig is acceptance window settings for point timestamps
+type TimestampPolicyConfig struct {
+	// Max age of point timestamp. Unlimited if 0
+	MaxPast time.Duration
+	// Max distance of point timestamp into the future. Unlimited if 0
+	MaxFuture time.Duration
+	// Action for points out of window, TimestampActionDrop if empty
+	Action TimestampAction
+}
+
+// timestampPolicy applies configured action to points out of acceptance window
+// and keeps last such point for every reason to report it in log and database
+type timestampPolicy struct {
+	config TimestampPolicyConfig
+
+	mutex      sync.Mutex
+	samples    map[string]string
+	counts     map[string]int
+	lastReport int64
+}
+
+func newTimestampPolicy(config TimestampPolicyConfig) (*timestampPolicy, error) {
+	switch config.Action {
+	case "":
+		config.Action = TimestampActionDrop
+	case TimestampActionPass, TimestampActionDrop, TimestampActionClamp:
+	default:
+		return nil, fmt.Errorf("unknown out of window timestamp action '%s'", config.Action)
+	}
+	if config.MaxPast < 0 || config.MaxFuture < 0 {
+		return nil, fmt.Errorf("timestamp acceptance window must not be negative")
+	}
+	return &timestampPolicy{
+		config:  config,
+		samples: make(map[string]string),
+		counts:  make(map[string]int),
+	}, nil
+}
+
+// apply returns reason if metric timestamp is out of acceptance window and nil metric if it must be dropped
+// Timestamp of clamped metric is replaced with now
+func (policy *timestampPolicy) apply(metric *ParsedMetric, now int64) (*ParsedMetric, string) {
+	reason := policy.getReason(metric.Timestamp, now)
+	if reason == "" {
+		return metric, ""
+	}
+
+	policy.mutex.Lock()
+	policy.samples[reason] = string(metric.Bytes())
+	policy.counts[reason]++
+	policy.mutex.Unlock()
+
+	switch policy.config.Action {
+	case TimestampActionDrop:
+		return nil, reason
+	case TimestampActionClamp:
+		metric.Timestamp = now
+	}
+	return metric, reason
+}
+
+func (policy *timestampPolicy) getReason(timestamp int64, now int64) string {
+	maxPast := int64(policy.config.MaxPast.Seconds())
+	if maxPast > 0 && timestamp < now-maxPast {
+		return outdatedTimestampReason
+	}
+	maxFuture := int64(policy.config.MaxFuture.Seconds())
+	if maxFuture > 0 && timestamp > now+maxFuture {
+		return futureTimestampReason
+	}
+	return ""
+}
+
+// reportSamples logs number of points out of acceptance window and last such point for every reason
+// at most once in timestampSamplesReportPeriod and returns reported samples to be saved
+func (policy *timestampPolicy) reportSamples(logger moira.Logger, now int64) []*moira.RejectedTimestampSample {
+	policy.mutex.Lock()
+	defer policy.mutex.Unlock()
+
+	if now-policy.lastReport < timestampSamplesReportPeriod {
+		return nil
+	}
+	policy.lastReport = now
+
+	samples := make([]*moira.RejectedTimestampSample, 0, len(policy.samples))
+	for reason, sample := range policy.samples {
+		logger.Warningf("Received %d points with %s timestamps (action: %s), last one: '%s'",
+			policy.counts[reason], reason, policy.config.Action, sample)
+		samples = append(samples, &moira.RejectedTimestampSample{
+			Reason:    reason,
+			Action:    string(policy.config.Action),
+			Sample:    sample,
+			Count:     policy.counts[reason],
+			Timestamp: now,
+		})
+	}
+	policy.samples = make(map[string]string)
+	policy.counts = make(map[string]int)
+	return samples
+}
@@ -0,0 +1,96 @@
+package filter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/moira-alert/moira"
+	logging "github.com/moira-alert/moira/logging/zerolog_adapter"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestTimestampPolicy(t *testing.T) {
+	const now = 1234567890
+
+	Convey("Given invalid config, should return error", t, func() {
+		_, err := newTimestampPolicy(TimestampPolicyConfig{Action: "ignore"})
+		So(err, ShouldBeError)
+		_, err = newTimestampPolicy(TimestampPolicyConfig{MaxPast: -time.Hour})
+		So(err, ShouldBeError)
+	})
+
+	Convey("Given policy without window, should pass any timestamp", t, func() {
+		policy, err := newTimestampPolicy(TimestampPolicyConfig{})
+		So(err, ShouldBeNil)
+		for _, timestamp := range []int64{0, now, now * 2} {
+			metric := &ParsedMetric{Metric: "One.two", Name: "One.two", Timestamp: timestamp}
+			result, reason := policy.apply(metric, now)
+			So(result, ShouldEqual, metric)
+			So(reason, ShouldBeEmpty)
+		}
+	})
+
+	Convey("Given acceptance window", t, func() {
+		config := TimestampPolicyConfig{MaxPast: time.Hour, MaxFuture: time.Minute}
+
+		Convey("Points within window should be passed as is", func() {
+			policy, _ := newTimestampPolicy(config)
+			for _, timestamp := range []int64{now - 3600, now, now + 60} {
+				metric := &ParsedMetric{Metric: "One.two", Name: "One.two", Timestamp: timestamp}
+				result, reason := policy.apply(metric, now)
+				So(result, ShouldEqual, metric)
+				So(result.Timestamp, ShouldEqual, timestamp)
+				So(reason, ShouldBeEmpty)
+			}
+		})
+
+		Convey("Points out of window should be passed with pass action", func() {
+			config.Action = TimestampActionPass
+			policy, _ := newTimestampPolicy(config)
+			result, reason := policy.apply(&ParsedMetric{Metric: "One.two", Name: "One.two", Timestamp: now - 3601}, now)
+			So(result.Timestamp, ShouldEqual, now-3601)
+			So(reason, ShouldEqual, outdatedTimestampReason)
+			result, reason = policy.apply(&ParsedMetric{Metric: "One.two", Name: "One.two", Timestamp: now + 61}, now)
+			So(result.Timestamp, ShouldEqual, now+61)
+			So(reason, ShouldEqual, futureTimestampReason)
+		})
+
+		Convey("Points out of window should be dropped with drop action", func() {
+			config.Action = TimestampActionDrop
+			policy, _ := newTimestampPolicy(config)
+			result, reason := policy.apply(&ParsedMetric{Metric: "One.two", Name: "One.two", Timestamp: now + 61}, now)
+			So(result, ShouldBeNil)
+			So(reason, ShouldEqual, futureTimestampReason)
+		})
+
+		Convey("Points out of window should be dropped by default", func() {
+			policy, _ := newTimestampPolicy(config)
+			result, reason := policy.apply(&ParsedMetric{Metric: "One.two", Name: "One.two", Timestamp: now - 3601}, now)
+			So(result, ShouldBeNil)
+			So(reason, ShouldEqual, outdatedTimestampReason)
+		})
+
+		Convey("Points out of window should get current timestamp with clamp action", func() {
+			config.Action = TimestampActionClamp
+			policy, _ := newTimestampPolicy(config)
+			result, reason := policy.apply(&ParsedMetric{Metric: "One.two", Name: "One.two", Timestamp: now - 7200}, now)
+			So(result.Timestamp, ShouldEqual, now)
+			So(reason, ShouldEqual, outdatedTimestampReason)
+		})
+
+		Convey("Last point out of window should be kept as sample until report", func() {
+			policy, _ := newTimestampPolicy(config)
+			policy.apply(&ParsedMetric{Metric: "One.two", Name: "One.two", Value: 1, Timestamp: now - 7200}, now)
+			policy.apply(&ParsedMetric{Metric: "One.three", Name: "One.three", Value: 2, Timestamp: now - 7300}, now)
+			So(policy.samples, ShouldResemble, map[string]string{outdatedTimestampReason: "One.three 2 1234560590"})
+			So(policy.counts, ShouldResemble, map[string]int{outdatedTimestampReason: 2})
+
+			logger, _ := logging.GetLogger("TimestampPolicy")
+			So(policy.reportSamples(logger, now), ShouldResemble, []*moira.RejectedTimestampSample{
+				{Reason: outdatedTimestampReason, Action: "drop", Sample: "One.three 2 1234560590", Count: 2, Timestamp: now},
+			})
+			So(policy.samples, ShouldBeEmpty)
+			So(policy.reportSamples(logger, now+1), ShouldBeNil)
+		})
+	})
+}
@@ -202,3 +202,10 @@ func MaxInt64(a, b int64) int64 {
 	}
 	return b
 }
+
+func MinInt64(a, b int64) int64 {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -17,6 +17,8 @@ type Database interface {
 	GetMetricsUpdatesCount() (int64, error)
 	GetChecksUpdatesCount() (int64, error)
 	GetRemoteChecksUpdatesCount() (int64, error)
+	UpdateRemoteClusterChecksHeartbeat(clusterName string) error
+	GetRemoteClusterChecksUpdatesCount(clusterName string) (int64, error)
 	GetNotifierState() (string, error)
 	SetNotifierState(string) error
 
@@ -35,12 +37,14 @@ type Database interface {
 	GetLocalTriggerIDs() ([]string, error)
 	GetAllTriggerIDs() ([]string, error)
 	GetRemoteTriggerIDs() ([]string, error)
+	GetRemoteClusterTriggerIDs(clusterName string) ([]string, error)
 	GetTrigger(triggerID string) (Trigger, error)
 	GetTriggers(triggerIDs []string) ([]*Trigger, error)
 	GetTriggerChecks(triggerIDs []string) ([]*TriggerCheck, error)
 	SaveTrigger(triggerID string, trigger *Trigger) error
 	RemoveTrigger(triggerID string) error
 	GetPatternTriggerIDs(pattern string) ([]string, error)
+	GetChildTriggerIDs(triggerID string) ([]string, error)
 	RemovePatternTriggerIDs(pattern string) error
 
 	// SearchResult AKA pager storing
@@ -95,6 +99,14 @@ type Database interface {
 	RemovePattern(pattern string) error
 	RemovePatternsMetrics(pattern []string) error
 	RemovePatternWithMetrics(pattern string) error
+	SaveIngestionLimitViolations(violations []*IngestionLimitViolation) error
+	GetIngestionLimitViolations() ([]*IngestionLimitViolation, error)
+	SaveRejectedTimestampSamples(samples []*RejectedTimestampSample) error
+	GetRejectedTimestampSamples() ([]*RejectedTimestampSample, error)
+	AddMetricTap(pattern string, expiration int64) error
+	GetMetricTaps() ([]string, error)
+	PublishMetricTapSamples(samples []*MetricTapSample) error
+	SubscribeMetricTap(tomb *tomb.Tomb, pattern string) (<-chan *MetricTapSample, error)
 
 	SubscribeMetricEvents(tomb *tomb.Tomb) (<-chan *MetricEvent, error)
 	SaveMetrics(buffer map[string]*MatchedMetric) error
@@ -112,6 +124,27 @@ type Database interface {
 	GetRemoteTriggersToCheck(count int) ([]string, error)
 	GetRemoteTriggersToCheckCount() (int64, error)
 
+	AddLocalShardTriggersToCheck(shardID string, triggerIDs []string) error
+	GetLocalShardTriggersToCheck(shardID string, count int) ([]string, error)
+	GetLocalShardTriggersToCheckCount(shardID string) (int64, error)
+
+	AddRemoteShardTriggersToCheck(shardID string, triggerIDs []string) error
+	GetRemoteShardTriggersToCheck(shardID string, count int) ([]string, error)
+	GetRemoteShardTriggersToCheckCount(shardID string) (int64, error)
+
+	GetLocalTriggersToCheckTotalCount() (int64, error)
+	GetRemoteTriggersToCheckTotalCount() (int64, error)
+
+	AddRemoteClusterTriggersToCheck(clusterName, shardID string, triggerIDs []string) error
+	GetRemoteClusterTriggersToCheck(clusterName, shardID string, count int) ([]string, error)
+	GetRemoteClusterTriggersToCheckCount(clusterName, shardID string) (int64, error)
+	GetRemoteClusterTriggersToCheckTotalCount(clusterName string) (int64, error)
+
+	// Checker instances registry used by triggers sharding
+	UpdateCheckerInstanceHeartbeat(instanceID string) error
+	GetCheckerInstances(aliveSince int64) ([]string, error)
+	RemoveCheckerInstance(instanceID string, clusterNames []string) error
+
 	// TriggerCheckLock storing
 	AcquireTriggerCheckLock(triggerID string, timeout int) error
 	DeleteTriggerCheckLock(triggerID string) error
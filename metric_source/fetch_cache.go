@@ -0,0 +1,239 @@
+package metricsource
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/metrics"
+	"github.com/patrickmn/go-cache"
+)
+
+// FetchCache is a MetricSource wrapper which coalesces fetches of target made by triggers checked within the same cycle.
+// End of fetched period is aligned up to cycle and its beginning is moved one cycle back, so result fetched for one trigger
+// covers periods of other triggers checked within the cycle, every caller gets result sliced to its own period.
+// Values are always fetched with real time alerting, last value is dropped from caller's period if it is not allowed.
+// Concurrent fetches wait for the first one and fetch results are kept for given TTL, usually check interval
+type FetchCache struct {
+	source  MetricSource
+	cache   *cache.Cache
+	cycle   int64
+	lock    sync.Mutex
+	metrics *metrics.FetchCacheMetrics
+}
+
+type fetchCacheItem struct {
+	done   chan struct{}
+	from   int64
+	result FetchResult
+	err    error
+}
+
+// NewFetchCache wraps metric source into fetch cache
+func NewFetchCache(source MetricSource, ttl time.Duration, metrics *metrics.FetchCacheMetrics) *FetchCache {
+	return &FetchCache{
+		source:  source,
+		cache:   cache.New(ttl, ttl),
+		cycle:   int64(ttl.Seconds()),
+		metrics: metrics,
+	}
+}
+
+// Fetch returns cached fetch result of target or fetches it from wrapped metric source
+func (fetchCache *FetchCache) Fetch(target string, from, until int64, allowRealTimeAlerting bool) (FetchResult, error) {
+	fetchFrom, fetchUntil := fetchCache.alignPeriod(from, until)
+	key := getFetchCacheKey(target, fetchUntil)
+	item, found := fetchCache.getOrCreateItem(key, from, fetchFrom)
+	if !found {
+		item.result, item.err = fetchCache.source.Fetch(target, fetchFrom, fetchUntil, true)
+		fetchCache.completeItem(key, item)
+	}
+	return item.get(from, until, allowRealTimeAlerting)
+}
+
+// FetchBatch returns cached fetch results of targets, targets which are not cached are fetched together
+// if wrapped metric source is BatchFetcher
+func (fetchCache *FetchCache) FetchBatch(targets []string, from, until int64, allowRealTimeAlerting bool) ([]FetchResult, error) {
+	batchFetcher, ok := fetchCache.source.(BatchFetcher)
+	if !ok {
+		return fetchTargets(fetchCache, targets, from, until, allowRealTimeAlerting)
+	}
+
+	fetchFrom, fetchUntil := fetchCache.alignPeriod(from, until)
+	items := make([]*fetchCacheItem, len(targets))
+	missedTargets := make([]string, 0)
+	missedItems := make(map[string]*fetchCacheItem)
+	for targetIndex, target := range targets {
+		key := getFetchCacheKey(target, fetchUntil)
+		if item, ok := missedItems[key]; ok {
+			items[targetIndex] = item
+			continue
+		}
+		item, found := fetchCache.getOrCreateItem(key, from, fetchFrom)
+		items[targetIndex] = item
+		if !found {
+			missedTargets = append(missedTargets, target)
+			missedItems[key] = item
+		}
+	}
+
+	if len(missedTargets) > 0 {
+		results, err := batchFetcher.FetchBatch(missedTargets, fetchFrom, fetchUntil, true)
+		if err == nil && len(results) != len(missedTargets) {
+			err = fmt.Errorf("batch fetch returned %d results for %d targets", len(results), len(missedTargets))
+		}
+		for targetIndex, target := range missedTargets {
+			key := getFetchCacheKey(target, fetchUntil)
+			item := missedItems[key]
+			if err != nil {
+				item.err = err
+			} else {
+				item.result = results[targetIndex]
+			}
+			fetchCache.completeItem(key, item)
+		}
+	}
+
+	results := make([]FetchResult, 0, len(targets))
+	for _, item := range items {
+		result, err := item.get(from, until, allowRealTimeAlerting)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// GetMetricsTTLSeconds returns metrics TTL of wrapped metric source
+func (fetchCache *FetchCache) GetMetricsTTLSeconds() int64 {
+	return fetchCache.source.GetMetricsTTLSeconds()
+}
+
+// IsConfigured returns whether wrapped metric source is configured
+func (fetchCache *FetchCache) IsConfigured() (bool, error) {
+	return fetchCache.source.IsConfigured()
+}
+
+// IsSuspended returns whether requests to wrapped metric source are suspended
+func (fetchCache *FetchCache) IsSuspended() bool {
+	source, ok := fetchCache.source.(SuspendableSource)
+	return ok && source.IsSuspended()
+}
+
+// alignPeriod returns period fetched to cache: its end is aligned up to cycle and its beginning is moved to cycle boundary
+// one cycle before given one
+func (fetchCache *FetchCache) alignPeriod(from, until int64) (int64, int64) {
+	if fetchCache.cycle <= 0 {
+		return from, until
+	}
+	fetchUntil := alignToCycle(until+fetchCache.cycle-1, fetchCache.cycle)
+	fetchFrom := moira.MinInt64(alignToCycle(from, fetchCache.cycle)-fetchCache.cycle, fetchUntil)
+	return fetchFrom, fetchUntil
+}
+
+// getOrCreateItem returns cached item by key if it covers given period beginning, otherwise new item fetching
+// period from fetchFrom is created and cached instead. Caller must complete new item
+func (fetchCache *FetchCache) getOrCreateItem(key string, from, fetchFrom int64) (*fetchCacheItem, bool) {
+	fetchCache.lock.Lock()
+	defer fetchCache.lock.Unlock()
+	if item, ok := fetchCache.cache.Get(key); ok && item.(*fetchCacheItem).from <= from {
+		fetchCache.metrics.Hits.Mark(1)
+		return item.(*fetchCacheItem), true
+	}
+	fetchCache.metrics.Misses.Mark(1)
+	item := &fetchCacheItem{done: make(chan struct{}), from: fetchFrom}
+	fetchCache.cache.SetDefault(key, item)
+	return item, false
+}
+
+// completeItem wakes up fetches waiting for item, failed fetches are not cached
+func (fetchCache *FetchCache) completeItem(key string, item *fetchCacheItem) {
+	if item.err != nil {
+		fetchCache.lock.Lock()
+		if cached, ok := fetchCache.cache.Get(key); ok && cached == item {
+			fetchCache.cache.Delete(key)
+		}
+		fetchCache.lock.Unlock()
+	}
+	close(item.done)
+}
+
+// get waits for item to be fetched and returns copy of its result sliced to given period,
+// so callers can't spoil cached values
+func (item *fetchCacheItem) get(from, until int64, allowRealTimeAlerting bool) (FetchResult, error) {
+	<-item.done
+	if item.err != nil {
+		return nil, item.err
+	}
+	return copyFetchResult(item.result, from, until, allowRealTimeAlerting), nil
+}
+
+type cachedFetchResult struct {
+	FetchResult
+	metricsData []MetricData
+}
+
+// GetMetricsData returns copied metrics data of cached fetch result
+func (result *cachedFetchResult) GetMetricsData() []MetricData {
+	return result.metricsData
+}
+
+// copyFetchResult copies metrics data of fetch result, values preceding from and following until are dropped.
+// Last value of period is dropped too if real time alerting is not allowed, as metric sources do
+func copyFetchResult(result FetchResult, from, until int64, allowRealTimeAlerting bool) FetchResult {
+	metricsData := result.GetMetricsData()
+	copied := make([]MetricData, 0, len(metricsData))
+	for _, metricData := range metricsData {
+		var skipped int64
+		count := int64(len(metricData.Values))
+		if metricData.StepTime > 0 {
+			if metricData.StartTime < from {
+				skipped = moira.MinInt64((from-metricData.StartTime+metricData.StepTime-1)/metricData.StepTime, count)
+			}
+			if until < metricData.StartTime {
+				count = 0
+			} else {
+				count = moira.MinInt64((until-metricData.StartTime)/metricData.StepTime+1, count)
+			}
+		}
+		if !allowRealTimeAlerting && count > skipped {
+			count--
+		}
+		count = moira.MaxInt64(count, skipped)
+		if count < int64(len(metricData.Values)) {
+			metricData.StopTime = metricData.StartTime + count*metricData.StepTime
+		}
+		values := make([]float64, count-skipped)
+		copy(values, metricData.Values[skipped:count])
+		metricData.Values = values
+		metricData.StartTime += skipped * metricData.StepTime
+		copied = append(copied, metricData)
+	}
+	return &cachedFetchResult{
+		FetchResult: result,
+		metricsData: copied,
+	}
+}
+
+func fetchTargets(source MetricSource, targets []string, from, until int64, allowRealTimeAlerting bool) ([]FetchResult, error) {
+	results := make([]FetchResult, 0, len(targets))
+	for _, target := range targets {
+		result, err := source.Fetch(target, from, until, allowRealTimeAlerting)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+func getFetchCacheKey(target string, until int64) string {
+	return fmt.Sprintf("%d:%s", until, target)
+}
+
+// alignToCycle returns cycle boundary preceding given timestamp or timestamp itself if it is boundary
+func alignToCycle(timestamp, cycle int64) int64 {
+	return timestamp - ((timestamp%cycle)+cycle)%cycle
+}
@@ -0,0 +1,264 @@
+package metricsource
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/moira-alert/moira/metrics"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+type testFetchResult struct {
+	metricsData []MetricData
+}
+
+func (result *testFetchResult) GetMetricsData() []MetricData {
+	return result.metricsData
+}
+
+func (*testFetchResult) GetPatterns() ([]string, error) {
+	return []string{}, nil
+}
+
+func (*testFetchResult) GetPatternMetrics() ([]string, error) {
+	return []string{}, nil
+}
+
+type testSource struct {
+	lock       sync.Mutex
+	fetches    []string
+	batches    [][]string
+	err        error
+	fetchDelay time.Duration
+}
+
+func (source *testSource) Fetch(target string, from, until int64, allowRealTimeAlerting bool) (FetchResult, error) {
+	time.Sleep(source.fetchDelay)
+	source.lock.Lock()
+	defer source.lock.Unlock()
+	source.fetches = append(source.fetches, target)
+	if source.err != nil {
+		return nil, source.err
+	}
+	return &testFetchResult{metricsData: []MetricData{*makeTestMetricData(target, from, until)}}, nil
+}
+
+func (source *testSource) GetMetricsTTLSeconds() int64 {
+	return 3600
+}
+
+func (source *testSource) IsConfigured() (bool, error) {
+	return true, nil
+}
+
+type testBatchSource struct {
+	testSource
+}
+
+func (source *testBatchSource) FetchBatch(targets []string, from, until int64, allowRealTimeAlerting bool) ([]FetchResult, error) {
+	source.lock.Lock()
+	defer source.lock.Unlock()
+	source.batches = append(source.batches, targets)
+	if source.err != nil {
+		return nil, source.err
+	}
+	results := make([]FetchResult, 0, len(targets))
+	for _, target := range targets {
+		results = append(results, &testFetchResult{metricsData: []MetricData{*makeTestMetricData(target, from, until)}})
+	}
+	return results, nil
+}
+
+type testSuspendableSource struct {
+	testSource
+	suspended bool
+}
+
+func (source *testSuspendableSource) IsSuspended() bool {
+	return source.suspended
+}
+
+// makeTestMetricData returns metric data with point every minute of given period, value of point is its timestamp
+func makeTestMetricData(target string, from, until int64) *MetricData {
+	values := make([]float64, 0)
+	for timestamp := from; timestamp <= until; timestamp += 60 {
+		values = append(values, float64(timestamp))
+	}
+	return MakeMetricData(target, values, 60, from)
+}
+
+func newTestFetchCacheMetrics() *metrics.FetchCacheMetrics {
+	registry := metrics.NewDummyRegistry()
+	return &metrics.FetchCacheMetrics{
+		Hits:   registry.NewMeter("hits"),
+		Misses: registry.NewMeter("misses"),
+	}
+}
+
+func TestFetchCache_Fetch(t *testing.T) {
+	var from int64 = 60
+	var until int64 = 240
+
+	Convey("Identical fetches are coalesced", t, func() {
+		source := &testSource{}
+		fetchCache := NewFetchCache(source, time.Minute, newTestFetchCacheMetrics())
+
+		first, err := fetchCache.Fetch("foo", from, until, true)
+		So(err, ShouldBeNil)
+		second, err := fetchCache.Fetch("foo", from, until, true)
+		So(err, ShouldBeNil)
+		So(second.GetMetricsData(), ShouldResemble, first.GetMetricsData())
+		So(source.fetches, ShouldResemble, []string{"foo"})
+
+		Convey("Different period is fetched again", func() {
+			_, err = fetchCache.Fetch("foo", from, until+60, true)
+			So(err, ShouldBeNil)
+			So(source.fetches, ShouldResemble, []string{"foo", "foo"})
+		})
+
+		Convey("Last value is dropped from cached result if real time alerting is not allowed", func() {
+			result, err := fetchCache.Fetch("foo", from, until, false)
+			So(err, ShouldBeNil)
+			So(result.GetMetricsData()[0].Values, ShouldResemble, []float64{60, 120, 180})
+			So(source.fetches, ShouldResemble, []string{"foo"})
+		})
+	})
+
+	Convey("Concurrent identical fetches wait for first one", t, func() {
+		source := &testSource{fetchDelay: time.Millisecond * 50}
+		fetchCache := NewFetchCache(source, time.Minute, newTestFetchCacheMetrics())
+
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				fetchCache.Fetch("foo", from, until, true) //nolint
+			}()
+		}
+		wg.Wait()
+		So(source.fetches, ShouldResemble, []string{"foo"})
+	})
+
+	Convey("Failed fetches are not cached", t, func() {
+		source := &testSource{err: fmt.Errorf("remote is unavailable")}
+		fetchCache := NewFetchCache(source, time.Minute, newTestFetchCacheMetrics())
+
+		_, err := fetchCache.Fetch("foo", from, until, true)
+		So(err, ShouldResemble, source.err)
+		source.err = nil
+		result, err := fetchCache.Fetch("foo", from, until, true)
+		So(err, ShouldBeNil)
+		So(result.GetMetricsData(), ShouldHaveLength, 1)
+		So(source.fetches, ShouldResemble, []string{"foo", "foo"})
+	})
+
+	Convey("Cached values can't be spoiled by caller", t, func() {
+		source := &testSource{}
+		fetchCache := NewFetchCache(source, time.Minute, newTestFetchCacheMetrics())
+
+		first, _ := fetchCache.Fetch("foo", from, until, true)
+		first.GetMetricsData()[0].Values[0] = 100
+		second, _ := fetchCache.Fetch("foo", from, until, true)
+		So(second.GetMetricsData()[0].Values, ShouldResemble, []float64{60, 120, 180, 240})
+	})
+
+	Convey("Fetches of triggers with different last checks are coalesced within cycle", t, func() {
+		source := &testSource{}
+		fetchCache := NewFetchCache(source, time.Minute, newTestFetchCacheMetrics())
+
+		// Both triggers have 600 seconds TTL, they were checked last time at 1000 and 1030
+		first, err := fetchCache.Fetch("foo", 400, 1030, true)
+		So(err, ShouldBeNil)
+		second, err := fetchCache.Fetch("foo", 430, 1050, true)
+		So(err, ShouldBeNil)
+		So(source.fetches, ShouldResemble, []string{"foo"})
+
+		So(first.GetMetricsData()[0].StartTime, ShouldEqual, 420)
+		So(first.GetMetricsData()[0].Values, ShouldResemble, []float64{420, 480, 540, 600, 660, 720, 780, 840, 900, 960, 1020})
+		So(second.GetMetricsData()[0].StartTime, ShouldEqual, 480)
+		So(second.GetMetricsData()[0].Values, ShouldResemble, []float64{480, 540, 600, 660, 720, 780, 840, 900, 960, 1020})
+
+		Convey("Trigger with longer period is fetched again", func() {
+			_, err = fetchCache.Fetch("foo", 100, 1040, true)
+			So(err, ShouldBeNil)
+			So(source.fetches, ShouldResemble, []string{"foo", "foo"})
+		})
+
+		Convey("Next cycle is fetched again", func() {
+			_, err = fetchCache.Fetch("foo", 460, 1090, true)
+			So(err, ShouldBeNil)
+			So(source.fetches, ShouldResemble, []string{"foo", "foo"})
+		})
+	})
+
+	Convey("Values following cycle boundary reach caller", t, func() {
+		source := &testSource{}
+		fetchCache := NewFetchCache(source, time.Minute*2, newTestFetchCacheMetrics())
+
+		result, err := fetchCache.Fetch("foo", 400, 1070, true)
+		So(err, ShouldBeNil)
+		So(result.GetMetricsData()[0].Values, ShouldResemble, []float64{420, 480, 540, 600, 660, 720, 780, 840, 900, 960, 1020})
+		So(result.GetMetricsData()[0].StopTime, ShouldEqual, 1080)
+
+		result, err = fetchCache.Fetch("foo", 400, 1070, false)
+		So(err, ShouldBeNil)
+		So(result.GetMetricsData()[0].Values, ShouldResemble, []float64{420, 480, 540, 600, 660, 720, 780, 840, 900, 960})
+		So(result.GetMetricsData()[0].StopTime, ShouldEqual, 1020)
+		So(source.fetches, ShouldResemble, []string{"foo"})
+	})
+}
+
+func TestFetchCache_FetchBatch(t *testing.T) {
+	var from int64 = 60
+	var until int64 = 240
+
+	Convey("Only missed targets are fetched by batch", t, func() {
+		source := &testBatchSource{}
+		fetchCache := NewFetchCache(source, time.Minute, newTestFetchCacheMetrics())
+
+		_, err := fetchCache.Fetch("foo", from, until, true)
+		So(err, ShouldBeNil)
+		results, err := fetchCache.FetchBatch([]string{"bar", "foo", "bar", "baz"}, from, until, true)
+		So(err, ShouldBeNil)
+		So(results, ShouldHaveLength, 4)
+		for i, target := range []string{"bar", "foo", "bar", "baz"} {
+			So(results[i].GetMetricsData()[0].Name, ShouldEqual, target)
+		}
+		So(source.fetches, ShouldResemble, []string{"foo"})
+		So(source.batches, ShouldResemble, [][]string{{"bar", "baz"}})
+	})
+
+	Convey("Batch error is returned and not cached", t, func() {
+		source := &testBatchSource{testSource{err: fmt.Errorf("remote is unavailable")}}
+		fetchCache := NewFetchCache(source, time.Minute, newTestFetchCacheMetrics())
+
+		results, err := fetchCache.FetchBatch([]string{"foo", "bar"}, from, until, true)
+		So(err, ShouldResemble, source.err)
+		So(results, ShouldBeNil)
+		source.err = nil
+		_, err = fetchCache.FetchBatch([]string{"foo", "bar"}, from, until, true)
+		So(err, ShouldBeNil)
+		So(source.batches, ShouldResemble, [][]string{{"foo", "bar"}, {"foo", "bar"}})
+	})
+
+	Convey("Targets are fetched one by one if source can't fetch batches", t, func() {
+		source := &testSource{}
+		fetchCache := NewFetchCache(source, time.Minute, newTestFetchCacheMetrics())
+
+		results, err := fetchCache.FetchBatch([]string{"foo", "bar", "foo"}, from, until, true)
+		So(err, ShouldBeNil)
+		So(results, ShouldHaveLength, 3)
+		So(source.fetches, ShouldResemble, []string{"foo", "bar"})
+	})
+}
+
+func TestFetchCache_IsSuspended(t *testing.T) {
+	Convey("Suspension of wrapped source is passed through", t, func() {
+		So(NewFetchCache(&testSource{}, time.Minute, newTestFetchCacheMetrics()).IsSuspended(), ShouldBeFalse)
+		So(NewFetchCache(&testSuspendableSource{}, time.Minute, newTestFetchCacheMetrics()).IsSuspended(), ShouldBeFalse)
+		So(NewFetchCache(&testSuspendableSource{suspended: true}, time.Minute, newTestFetchCacheMetrics()).IsSuspended(), ShouldBeTrue)
+	})
+}
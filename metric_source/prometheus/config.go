@@ -0,0 +1,19 @@
+package prometheus
+
+import "time"
+
+// Config represents config of Prometheus-compatible remote storage
+type Config struct {
+	URL        string
+	MetricsTTL time.Duration
+	Timeout    time.Duration
+	Step       time.Duration
+	User       string
+	Password   string
+	Enabled    bool
+}
+
+// isEnabled checks that prometheus config is enabled (url is defined and enabled flag is set)
+func (c *Config) isEnabled() bool {
+	return c.Enabled && c.URL != ""
+}
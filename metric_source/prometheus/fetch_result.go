@@ -0,0 +1,28 @@
+package prometheus
+
+import (
+	"fmt"
+
+	metricSource "github.com/moira-alert/moira/metric_source"
+)
+
+// FetchResult is implementation of metric_source.FetchResult interface,
+// which represent result of PromQL query evaluation in moira format
+type FetchResult struct {
+	MetricsData []metricSource.MetricData
+}
+
+// GetMetricsData return all metrics data from fetch result
+func (fetchResult *FetchResult) GetMetricsData() []metricSource.MetricData {
+	return fetchResult.MetricsData
+}
+
+// GetPatterns always returns error, because PromQL queries have no graphite patterns
+func (*FetchResult) GetPatterns() ([]string, error) {
+	return make([]string, 0), fmt.Errorf("prometheus fetch result never returns patterns")
+}
+
+// GetPatternMetrics always returns error, because PromQL queries have no graphite patterns
+func (*FetchResult) GetPatternMetrics() ([]string, error) {
+	return make([]string, 0), fmt.Errorf("prometheus fetch result never returns pattern metrics")
+}
@@ -0,0 +1,89 @@
+package prometheus
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/moira-alert/moira"
+	metricSource "github.com/moira-alert/moira/metric_source"
+	"github.com/moira-alert/moira/metric_source/remote"
+)
+
+// ErrPrometheusStorageDisabled is used to prevent prometheus.Fetch calls when prometheus storage is disabled
+var ErrPrometheusStorageDisabled = fmt.Errorf("prometheus storage is not enabled")
+
+// defaultStep is resolution of fetched metrics if it is not configured
+const defaultStep = time.Minute
+
+// Prometheus is implementation of MetricSource interface, which evaluates PromQL queries
+// using HTTP API of Prometheus-compatible storage such as Prometheus, VictoriaMetrics or Thanos
+type Prometheus struct {
+	config *Config
+	client *http.Client
+}
+
+// Create configures prometheus metric source
+func Create(config *Config) metricSource.MetricSource {
+	return &Prometheus{
+		config: config,
+		client: &http.Client{Timeout: config.Timeout},
+	}
+}
+
+// Fetch evaluates PromQL query over given period and converts result to expected format.
+// Fetch errors are returned as remote.ErrRemoteTriggerResponse, so they are handled like errors of remote graphite
+func (prometheus *Prometheus) Fetch(target string, from, until int64, allowRealTimeAlerting bool) (metricSource.FetchResult, error) {
+	// Don't fetch intervals larger than metrics TTL to prevent OOM errors
+	from = moira.MaxInt64(from, until-int64(prometheus.config.MetricsTTL.Seconds()))
+	step := prometheus.getStep()
+	from -= from % step
+
+	req, err := prometheus.prepareRequest(queryRangePath, target, from, until, step)
+	if err != nil {
+		return nil, remote.ErrRemoteTriggerResponse{InternalError: err, Target: target}
+	}
+	body, err := prometheus.makeRequest(req)
+	if err != nil {
+		return nil, remote.ErrRemoteTriggerResponse{InternalError: err, Target: target}
+	}
+	series, err := decodeBody(body)
+	if err != nil {
+		return nil, remote.ErrRemoteTriggerResponse{InternalError: err, Target: target}
+	}
+	fetchResult := convertResponse(series, from, until, step, allowRealTimeAlerting)
+	return &fetchResult, nil
+}
+
+// GetMetricsTTLSeconds returns maximum time interval that we are allowed to fetch from prometheus
+func (prometheus *Prometheus) GetMetricsTTLSeconds() int64 {
+	return int64(prometheus.config.MetricsTTL.Seconds())
+}
+
+// IsConfigured returns false in cases that user does not properly configure prometheus settings like URL
+func (prometheus *Prometheus) IsConfigured() (bool, error) {
+	if prometheus.config.isEnabled() {
+		return true, nil
+	}
+	return false, ErrPrometheusStorageDisabled
+}
+
+// IsAvailable checks if prometheus API is available and evaluates queries
+func (prometheus *Prometheus) IsAvailable() (bool, error) {
+	until := time.Now().Unix()
+	req, err := prometheus.prepareRequest(queryRangePath, "vector(1)", until-prometheus.getStep(), until, prometheus.getStep())
+	if err != nil {
+		return false, err
+	}
+	return remote.CheckAvailability(func() error {
+		_, err := prometheus.makeRequest(req)
+		return err
+	})
+}
+
+func (prometheus *Prometheus) getStep() int64 {
+	if prometheus.config.Step < time.Second {
+		return int64(defaultStep.Seconds())
+	}
+	return int64(prometheus.config.Step.Seconds())
+}
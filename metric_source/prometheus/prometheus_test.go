@@ -0,0 +1,187 @@
+package prometheus
+
+import (
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	metricSource "github.com/moira-alert/moira/metric_source"
+	"github.com/moira-alert/moira/metric_source/remote"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+const matrixResponse = `{
+	"status": "success",
+	"data": {
+		"resultType": "matrix",
+		"result": [
+			{"metric": {"__name__": "up", "job": "node", "instance": "host:9100"}, "values": [[300, "1"], [360, "0"], [480, "1"]]},
+			{"metric": {"job": "api"}, "values": [[420, "NaN"], [480, "2.5"]]}
+		]
+	}
+}`
+
+func TestIsConfigured(t *testing.T) {
+	Convey("Prometheus is not configured", t, func() {
+		prometheus := Create(&Config{URL: "", Enabled: true})
+		isConfigured, err := prometheus.IsConfigured()
+		So(isConfigured, ShouldBeFalse)
+		So(err, ShouldResemble, ErrPrometheusStorageDisabled)
+	})
+
+	Convey("Prometheus is configured", t, func() {
+		prometheus := Create(&Config{URL: "http://host", Enabled: true})
+		isConfigured, err := prometheus.IsConfigured()
+		So(isConfigured, ShouldBeTrue)
+		So(err, ShouldBeNil)
+	})
+}
+
+func TestFetch(t *testing.T) {
+	var from int64 = 310
+	var until int64 = 480
+	query := `up{job=~"node|api"}`
+
+	Convey("Request success", t, func() {
+		var requestURL *url.URL
+		server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			requestURL = req.URL
+			rw.Write([]byte(matrixResponse)) //nolint
+		}))
+		defer server.Close()
+		prometheus := Prometheus{client: server.Client(), config: &Config{URL: server.URL + "/", MetricsTTL: time.Hour}}
+
+		Convey("with real time alerting", func() {
+			result, err := prometheus.Fetch(query, from, until, true)
+			So(err, ShouldBeNil)
+			So(requestURL.Path, ShouldEqual, "/api/v1/query_range")
+			So(requestURL.Query().Get("query"), ShouldEqual, query)
+			So(requestURL.Query().Get("start"), ShouldEqual, "300")
+			So(requestURL.Query().Get("end"), ShouldEqual, "480")
+			So(requestURL.Query().Get("step"), ShouldEqual, "60")
+
+			metricsData := result.GetMetricsData()
+			So(metricsData, ShouldHaveLength, 2)
+			So(metricsData[0].Name, ShouldEqual, `up{instance="host:9100", job="node"}`)
+			So(metricsData[0].StartTime, ShouldEqual, 300)
+			So(metricsData[0].StepTime, ShouldEqual, 60)
+			So(metricsData[0].Values[:2], ShouldResemble, []float64{1, 0})
+			So(math.IsNaN(metricsData[0].Values[2]), ShouldBeTrue)
+			So(metricsData[0].Values[3], ShouldEqual, 1)
+			So(metricsData[1].Name, ShouldEqual, `{job="api"}`)
+			So(math.IsNaN(metricsData[1].Values[2]), ShouldBeTrue)
+			So(metricsData[1].Values[3], ShouldEqual, 2.5)
+
+			_, err = result.GetPatterns()
+			So(err, ShouldNotBeNil)
+			_, err = result.GetPatternMetrics()
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("without real time alerting", func() {
+			result, err := prometheus.Fetch(query, from, until, false)
+			So(err, ShouldBeNil)
+			metricsData := result.GetMetricsData()
+			So(metricsData, ShouldHaveLength, 2)
+			So(metricsData[0].Values, ShouldHaveLength, 3)
+			So(metricsData[0].StopTime, ShouldEqual, 480)
+		})
+
+		Convey("with period limited by metrics TTL", func() {
+			prometheus.config.MetricsTTL = time.Minute * 2
+			_, err := prometheus.Fetch(query, from, until, true)
+			So(err, ShouldBeNil)
+			So(requestURL.Query().Get("start"), ShouldEqual, "360")
+		})
+	})
+
+	Convey("Request success with empty result", t, func() {
+		server := createServer([]byte(`{"status": "success", "data": {"resultType": "matrix", "result": []}}`), http.StatusOK)
+		defer server.Close()
+		prometheus := Prometheus{client: server.Client(), config: &Config{URL: server.URL, MetricsTTL: time.Hour}}
+		result, err := prometheus.Fetch(query, from, until, true)
+		So(err, ShouldBeNil)
+		So(result, ShouldResemble, &FetchResult{MetricsData: []metricSource.MetricData{}})
+	})
+
+	Convey("Request fails with bad query", t, func() {
+		server := createServer([]byte(`{"status": "error", "errorType": "bad_data", "error": "parse error"}`), http.StatusBadRequest)
+		defer server.Close()
+		prometheus := Prometheus{client: server.Client(), config: &Config{URL: server.URL, MetricsTTL: time.Hour}}
+		result, err := prometheus.Fetch(query, from, until, true)
+		So(result, ShouldBeNil)
+		So(err, ShouldResemble, remote.ErrRemoteTriggerResponse{
+			InternalError: remote.ErrRemoteBadTarget{StatusCode: http.StatusBadRequest, Body: "bad_data: parse error"},
+			Target:        query,
+		})
+	})
+
+	Convey("Request fails with query execution error", t, func() {
+		server := createServer([]byte(`{"status": "error", "errorType": "execution", "error": "many-to-many matching not allowed"}`), http.StatusUnprocessableEntity)
+		defer server.Close()
+		prometheus := Prometheus{client: server.Client(), config: &Config{URL: server.URL, MetricsTTL: time.Hour}}
+		result, err := prometheus.Fetch(query, from, until, true)
+		So(result, ShouldBeNil)
+		So(err, ShouldResemble, remote.ErrRemoteTriggerResponse{
+			InternalError: remote.ErrRemoteBadTarget{StatusCode: http.StatusUnprocessableEntity, Body: "execution: many-to-many matching not allowed"},
+			Target:        query,
+		})
+	})
+
+	Convey("Request fails with InternalServerError", t, func() {
+		server := createServer([]byte("Some string"), http.StatusInternalServerError)
+		defer server.Close()
+		prometheus := Prometheus{client: server.Client(), config: &Config{URL: server.URL, MetricsTTL: time.Hour}}
+		result, err := prometheus.Fetch(query, from, until, true)
+		So(result, ShouldBeNil)
+		So(err.Error(), ShouldEqual, "bad response status 500: Some string")
+	})
+
+	Convey("Request success but result is not matrix", t, func() {
+		server := createServer([]byte(`{"status": "success", "data": {"resultType": "vector", "result": []}}`), http.StatusOK)
+		defer server.Close()
+		prometheus := Prometheus{client: server.Client(), config: &Config{URL: server.URL, MetricsTTL: time.Hour}}
+		result, err := prometheus.Fetch(query, from, until, true)
+		So(result, ShouldBeNil)
+		So(err.Error(), ShouldEqual, "unexpected result type vector, expected matrix")
+	})
+
+	Convey("Request success but body is invalid", t, func() {
+		server := createServer([]byte("Some string"), http.StatusOK)
+		defer server.Close()
+		prometheus := Prometheus{client: server.Client(), config: &Config{URL: server.URL, MetricsTTL: time.Hour}}
+		result, err := prometheus.Fetch(query, from, until, true)
+		So(result, ShouldBeNil)
+		So(err.Error(), ShouldEqual, "invalid character 'S' looking for beginning of value")
+	})
+}
+
+func TestIsAvailable(t *testing.T) {
+	Convey("Is available", t, func() {
+		server := createServer([]byte(`{"status": "success", "data": {"resultType": "matrix", "result": []}}`), http.StatusOK)
+		defer server.Close()
+		prometheus := Prometheus{client: server.Client(), config: &Config{URL: server.URL}}
+		isAvailable, err := prometheus.IsAvailable()
+		So(isAvailable, ShouldBeTrue)
+		So(err, ShouldBeNil)
+	})
+
+	Convey("Not available", t, func() {
+		server := createServer([]byte("Some string"), http.StatusServiceUnavailable)
+		defer server.Close()
+		prometheus := Prometheus{client: server.Client(), config: &Config{URL: server.URL}}
+		isAvailable, err := prometheus.IsAvailable()
+		So(isAvailable, ShouldBeFalse)
+		So(err, ShouldResemble, remote.ErrRemoteServerError{StatusCode: http.StatusServiceUnavailable, Body: "Some string"})
+	})
+}
+
+func createServer(body []byte, statusCode int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(statusCode)
+		rw.Write(body) //nolint
+	}))
+}
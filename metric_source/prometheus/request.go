@@ -0,0 +1,41 @@
+package prometheus
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/moira-alert/moira/metric_source/remote"
+)
+
+const queryRangePath = "/api/v1/query_range"
+
+func (prometheus *Prometheus) prepareRequest(path, query string, from, until, step int64) (*http.Request, error) {
+	req, err := http.NewRequest("GET", strings.TrimSuffix(prometheus.config.URL, "/")+path, nil)
+	if err != nil {
+		return nil, err
+	}
+	q := req.URL.Query()
+	q.Add("query", query)
+	q.Add("start", strconv.FormatInt(from, 10))
+	q.Add("end", strconv.FormatInt(until, 10))
+	q.Add("step", strconv.FormatInt(step, 10))
+	req.URL.RawQuery = q.Encode()
+	if prometheus.config.User != "" && prometheus.config.Password != "" {
+		req.SetBasicAuth(prometheus.config.User, prometheus.config.Password)
+	}
+	return req, nil
+}
+
+// makeRequest makes request to prometheus, query errors are reported with 400 and 422 statuses
+// and description taken from response body
+func (prometheus *Prometheus) makeRequest(req *http.Request) ([]byte, error) {
+	body, err := remote.MakeRequest(prometheus.client, req)
+	if badTarget, ok := err.(remote.ErrRemoteBadTarget); ok {
+		if message, ok := decodeError(body); ok {
+			badTarget.Body = message
+		}
+		return body, badTarget
+	}
+	return body, err
+}
@@ -0,0 +1,125 @@
+package prometheus
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	metricSource "github.com/moira-alert/moira/metric_source"
+)
+
+const (
+	statusSuccess    = "success"
+	resultTypeMatrix = "matrix"
+	metricNameLabel  = "__name__"
+)
+
+type queryResponse struct {
+	Status    string    `json:"status"`
+	Data      queryData `json:"data"`
+	ErrorType string    `json:"errorType"`
+	Error     string    `json:"error"`
+}
+
+type queryData struct {
+	ResultType string       `json:"resultType"`
+	Result     []timeSeries `json:"result"`
+}
+
+type timeSeries struct {
+	Metric map[string]string `json:"metric"`
+	Values [][2]interface{}  `json:"values"`
+}
+
+func decodeBody(body []byte) ([]timeSeries, error) {
+	var response queryResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, err
+	}
+	if response.Status != statusSuccess {
+		return nil, fmt.Errorf("query failed: %s: %s", response.ErrorType, response.Error)
+	}
+	if response.Data.ResultType != resultTypeMatrix {
+		return nil, fmt.Errorf("unexpected result type %s, expected %s", response.Data.ResultType, resultTypeMatrix)
+	}
+	return response.Data.Result, nil
+}
+
+// decodeError returns error description of failed query response
+func decodeError(body []byte) (string, bool) {
+	var response queryResponse
+	if err := json.Unmarshal(body, &response); err != nil || response.Error == "" {
+		return "", false
+	}
+	return fmt.Sprintf("%s: %s", response.ErrorType, response.Error), true
+}
+
+// convertResponse converts time series to metrics data with points every step seconds from from until until,
+// missing points are NaN. Last point is removed if real time alerting is not allowed
+func convertResponse(series []timeSeries, from, until, step int64, allowRealTimeAlerting bool) FetchResult {
+	pointsCount := int((until-from)/step) + 1
+	if pointsCount < 1 {
+		pointsCount = 1
+	}
+	result := make([]metricSource.MetricData, 0, len(series))
+	for _, timeSeries := range series {
+		values := make([]float64, pointsCount)
+		for i := range values {
+			values[i] = math.NaN()
+		}
+		for _, point := range timeSeries.Values {
+			timestamp, value, ok := parsePoint(point)
+			if !ok || timestamp < from || timestamp > until {
+				continue
+			}
+			values[(timestamp-from)/step] = value
+		}
+		if !allowRealTimeAlerting {
+			values = values[:len(values)-1]
+		}
+		result = append(result, *metricSource.MakeMetricData(getMetricName(timeSeries.Metric), values, step, from))
+	}
+	return FetchResult{MetricsData: result}
+}
+
+// parsePoint parses point of time series which is pair of unix timestamp and string value
+func parsePoint(point [2]interface{}) (int64, float64, bool) {
+	timestamp, ok := point[0].(float64)
+	if !ok {
+		return 0, 0, false
+	}
+	rawValue, ok := point[1].(string)
+	if !ok {
+		return 0, 0, false
+	}
+	value, err := strconv.ParseFloat(rawValue, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	return int64(timestamp), value, true
+}
+
+// getMetricName formats time series labels like Prometheus does: name{label="value", ...} with labels sorted by name
+func getMetricName(labels map[string]string) string {
+	names := make([]string, 0, len(labels))
+	for name := range labels {
+		if name != metricNameLabel {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	pairs := make([]string, 0, len(names))
+	for _, name := range names {
+		pairs = append(pairs, fmt.Sprintf("%s=%q", name, labels[name]))
+	}
+	if len(pairs) == 0 {
+		if metricName := labels[metricNameLabel]; metricName != "" {
+			return metricName
+		}
+		return "{}"
+	}
+	return labels[metricNameLabel] + "{" + strings.Join(pairs, ", ") + "}"
+}
@@ -2,8 +2,11 @@ package metricsource
 
 import (
 	"fmt"
+	"sort"
+	"time"
 
 	"github.com/moira-alert/moira"
+	"github.com/moira-alert/moira/metrics"
 )
 
 // ErrMetricSourceIsNotConfigured is used then metric source return false on IsConfigured method call with nil error
@@ -11,18 +14,50 @@ var ErrMetricSourceIsNotConfigured = fmt.Errorf("metric source is not configured
 
 // SourceProvider is a provider for all known metrics sources
 type SourceProvider struct {
-	local  MetricSource
-	remote MetricSource
+	local      MetricSource
+	remote     MetricSource
+	prometheus MetricSource
+	clusters   map[string]MetricSource
+	// fetchCaches are fetch cache wrappers of metric sources used to check triggers
+	fetchCaches map[MetricSource]MetricSource
 }
 
-// CreateMetricSourceProvider just creates SourceProvider with all known metrics sources
-func CreateMetricSourceProvider(local MetricSource, remote MetricSource) *SourceProvider {
+// CreateMetricSourceProvider just creates SourceProvider with all known metrics sources.
+// Sources which are not used may be nil
+func CreateMetricSourceProvider(local MetricSource, remote MetricSource, prometheus MetricSource) *SourceProvider {
 	return &SourceProvider{
-		remote: remote,
-		local:  local,
+		remote:      remote,
+		local:       local,
+		prometheus:  prometheus,
+		clusters:    make(map[string]MetricSource),
+		fetchCaches: make(map[MetricSource]MetricSource),
 	}
 }
 
+// EnableFetchCache makes trigger metric source wrapped into fetch cache with given TTL.
+// Sources returned by other getters are not wrapped
+func (provider *SourceProvider) EnableFetchCache(source MetricSource, ttl time.Duration, fetchCacheMetrics *metrics.FetchCacheMetrics) {
+	if source == nil || ttl <= 0 {
+		return
+	}
+	provider.fetchCaches[source] = NewFetchCache(source, ttl, fetchCacheMetrics)
+}
+
+// RegisterRemoteCluster adds named remote graphite cluster triggers can be bound to
+func (provider *SourceProvider) RegisterRemoteCluster(clusterName string, source MetricSource) {
+	provider.clusters[clusterName] = source
+}
+
+// GetRemoteClusterNames returns sorted names of registered remote graphite clusters
+func (provider *SourceProvider) GetRemoteClusterNames() []string {
+	clusterNames := make([]string, 0, len(provider.clusters))
+	for clusterName := range provider.clusters {
+		clusterNames = append(clusterNames, clusterName)
+	}
+	sort.Strings(clusterNames)
+	return clusterNames
+}
+
 // GetLocal gets local metric source. If it not configured returns not empty error
 func (provider *SourceProvider) GetLocal() (MetricSource, error) {
 	return returnSource(provider.local)
@@ -33,9 +68,48 @@ func (provider *SourceProvider) GetRemote() (MetricSource, error) {
 	return returnSource(provider.remote)
 }
 
+// GetRemoteCluster gets remote graphite cluster by name, default remote source is used if name is empty.
+// If it not configured returns not empty error
+func (provider *SourceProvider) GetRemoteCluster(clusterName string) (MetricSource, error) {
+	if clusterName == "" {
+		return provider.GetRemote()
+	}
+	source, ok := provider.clusters[clusterName]
+	if !ok {
+		return nil, fmt.Errorf("unknown remote cluster '%s'", clusterName)
+	}
+	return returnSource(source)
+}
+
+// GetPrometheus gets prometheus metric source. If it not configured returns not empty error
+func (provider *SourceProvider) GetPrometheus() (MetricSource, error) {
+	return returnSource(provider.prometheus)
+}
+
 // GetTriggerMetricSource get metrics source by given trigger. If it not configured returns not empty error
 func (provider *SourceProvider) GetTriggerMetricSource(trigger *moira.Trigger) (MetricSource, error) {
-	return provider.GetMetricSource(trigger.IsRemote)
+	source, err := provider.GetTriggerSourceMetricSource(trigger.GetTriggerSource(), trigger.ClusterName)
+	if err != nil {
+		return source, err
+	}
+	if fetchCache, ok := provider.fetchCaches[source]; ok {
+		return fetchCache, nil
+	}
+	return source, nil
+}
+
+// GetTriggerSourceMetricSource returns metric source by declared trigger source and name of remote graphite cluster.
+// If it not configured returns not empty error
+func (provider *SourceProvider) GetTriggerSourceMetricSource(triggerSource moira.TriggerSource, clusterName string) (MetricSource, error) {
+	switch triggerSource {
+	case moira.GraphiteLocal:
+		return provider.GetLocal()
+	case moira.GraphiteRemote:
+		return provider.GetRemoteCluster(clusterName)
+	case moira.PrometheusRemote:
+		return provider.GetPrometheus()
+	}
+	return nil, fmt.Errorf("unknown trigger source '%s'", triggerSource)
 }
 
 // GetMetricSource return metric source depending on trigger flag: is remote trigger or not. GetLocal if not.
@@ -47,6 +121,9 @@ func (provider *SourceProvider) GetMetricSource(isRemote bool) (MetricSource, er
 }
 
 func returnSource(source MetricSource) (MetricSource, error) {
+	if source == nil {
+		return nil, ErrMetricSourceIsNotConfigured
+	}
 	isConfigured, err := source.IsConfigured()
 	if !isConfigured && err == nil {
 		return source, ErrMetricSourceIsNotConfigured
@@ -0,0 +1,135 @@
+package remote
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// setRequestAuth sets basic auth, bearer token and custom headers to request.
+// Secrets stored in files are cached until files are modified, so they can be rotated without restart
+func (remote *Remote) setRequestAuth(req *http.Request) error {
+	password, err := remote.secrets.get(remote.config.Password, remote.config.PasswordFile)
+	if err != nil {
+		return err
+	}
+	if remote.config.User != "" && password != "" {
+		req.SetBasicAuth(remote.config.User, password)
+	}
+
+	bearerToken, err := remote.secrets.get(remote.config.BearerToken, remote.config.BearerTokenFile)
+	if err != nil {
+		return err
+	}
+	if bearerToken != "" {
+		req.Header.Set("Authorization", "Bearer "+bearerToken)
+	}
+
+	for name, value := range remote.config.Headers {
+		req.Header.Set(name, value)
+	}
+	for name, fileName := range remote.config.HeaderFiles {
+		value, err := remote.secrets.read(fileName)
+		if err != nil {
+			return err
+		}
+		req.Header.Set(name, value)
+	}
+	return nil
+}
+
+// secretFiles caches contents of secret files, file is read again when its modification time or size changes
+type secretFiles struct {
+	lock    sync.Mutex
+	secrets map[string]secretFile
+}
+
+type secretFile struct {
+	modTime time.Time
+	size    int64
+	value   string
+}
+
+// get returns secret read from file if file is set, given value is returned otherwise
+func (files *secretFiles) get(value, fileName string) (string, error) {
+	if fileName == "" {
+		return value, nil
+	}
+	return files.read(fileName)
+}
+
+func (files *secretFiles) read(fileName string) (string, error) {
+	info, err := os.Stat(fileName)
+	if err != nil {
+		return "", ErrRemoteSecret{FileName: fileName, InternalError: err}
+	}
+	files.lock.Lock()
+	defer files.lock.Unlock()
+	if secret, ok := files.secrets[fileName]; ok && secret.modTime.Equal(info.ModTime()) && secret.size == info.Size() {
+		return secret.value, nil
+	}
+	content, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return "", ErrRemoteSecret{FileName: fileName, InternalError: err}
+	}
+	if files.secrets == nil {
+		files.secrets = make(map[string]secretFile)
+	}
+	secret := secretFile{modTime: info.ModTime(), size: info.Size(), value: strings.TrimSpace(string(content))}
+	files.secrets[fileName] = secret
+	return secret.value, nil
+}
+
+// createHTTPClient creates client of remote storage, TLS settings are applied if any of them is set
+func createHTTPClient(config *Config) (*http.Client, error) {
+	client := &http.Client{Timeout: config.Timeout}
+	if !config.TLS.isSet() {
+		return client, nil
+	}
+	tlsConfig, err := config.TLS.getTLSConfig()
+	if err != nil {
+		return client, err
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+	client.Transport = transport
+	return client, nil
+}
+
+func (config TLSConfig) isSet() bool {
+	return config.CAFile != "" || config.CertFile != "" || config.KeyFile != "" || config.InsecureSkipVerify
+}
+
+func (config TLSConfig) getTLSConfig() (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify: config.InsecureSkipVerify, //nolint
+	}
+	if config.CAFile != "" {
+		caCert, err := ioutil.ReadFile(config.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA file: %s", err.Error())
+		}
+		caCertPool := x509.NewCertPool()
+		if !caCertPool.AppendCertsFromPEM(caCert) {
+			return nil, fmt.Errorf("no certificates found in CA file %s", config.CAFile)
+		}
+		tlsConfig.RootCAs = caCertPool
+	}
+	if config.CertFile != "" || config.KeyFile != "" {
+		if config.CertFile == "" || config.KeyFile == "" {
+			return nil, fmt.Errorf("both client certificate and key files must be set")
+		}
+		clientCert, err := tls.LoadX509KeyPair(config.CertFile, config.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %s", err.Error())
+		}
+		tlsConfig.Certificates = []tls.Certificate{clientCert}
+	}
+	return tlsConfig, nil
+}
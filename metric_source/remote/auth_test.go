@@ -0,0 +1,182 @@
+package remote
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestSetRequestAuth(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "moira-remote-auth")
+	defer os.RemoveAll(dir)
+
+	Convey("Bearer token and headers are set", t, func() {
+		remote := Remote{config: &Config{
+			URL:         "http://test/",
+			BearerToken: "token",
+			Headers:     map[string]string{"X-Scope": "moira"},
+		}}
+		req, err := remote.prepareRequest(300, 500, "foo.bar")
+		So(err, ShouldBeNil)
+		So(req.Header.Get("Authorization"), ShouldEqual, "Bearer token")
+		So(req.Header.Get("X-Scope"), ShouldEqual, "moira")
+	})
+
+	Convey("Secrets are read from files", t, func() {
+		passwordFile := writeTestFile(dir, "password", "secret\n")
+		apiKeyFile := writeTestFile(dir, "api-key", "key")
+		remote := Remote{config: &Config{
+			URL:          "http://test/",
+			User:         "moira",
+			Password:     "ignored",
+			PasswordFile: passwordFile,
+			HeaderFiles:  map[string]string{"X-Api-Key": apiKeyFile},
+		}}
+		req, err := remote.prepareRequest(300, 500, "foo.bar")
+		So(err, ShouldBeNil)
+		user, password, ok := req.BasicAuth()
+		So(ok, ShouldBeTrue)
+		So(user, ShouldEqual, "moira")
+		So(password, ShouldEqual, "secret")
+		So(req.Header.Get("X-Api-Key"), ShouldEqual, "key")
+
+		Convey("Rotated secret is used by next request", func() {
+			writeTestFile(dir, "api-key", "new key")
+			modTime := time.Now().Add(time.Minute)
+			os.Chtimes(apiKeyFile, modTime, modTime) //nolint
+			req, err := remote.prepareRequest(300, 500, "foo.bar")
+			So(err, ShouldBeNil)
+			So(req.Header.Get("X-Api-Key"), ShouldEqual, "new key")
+		})
+
+		Convey("Unmodified secret file is not read again", func() {
+			info, _ := os.Stat(apiKeyFile)
+			writeTestFile(dir, "api-key", "KEY")
+			os.Chtimes(apiKeyFile, info.ModTime(), info.ModTime()) //nolint
+			req, err := remote.prepareRequest(300, 500, "foo.bar")
+			So(err, ShouldBeNil)
+			So(req.Header.Get("X-Api-Key"), ShouldEqual, "key")
+		})
+	})
+
+	Convey("Bearer token file takes precedence over basic auth", t, func() {
+		remote := Remote{config: &Config{
+			URL:             "http://test/",
+			User:            "moira",
+			Password:        "secret",
+			BearerTokenFile: writeTestFile(dir, "token", "file token"),
+		}}
+		req, err := remote.prepareRequest(300, 500, "foo.bar")
+		So(err, ShouldBeNil)
+		So(req.Header.Get("Authorization"), ShouldEqual, "Bearer file token")
+	})
+
+	Convey("Missing secret file fails request", t, func() {
+		remote := Remote{config: &Config{
+			URL:             "http://test/",
+			BearerTokenFile: filepath.Join(dir, "missing"),
+		}}
+		req, err := remote.prepareRequest(300, 500, "foo.bar")
+		So(req, ShouldBeNil)
+		So(err, ShouldHaveSameTypeAs, ErrRemoteSecret{})
+		So(err.(ErrRemoteSecret).FileName, ShouldEqual, filepath.Join(dir, "missing"))
+
+		_, err = remote.Fetch("foo.bar", 300, 500, true)
+		So(err, ShouldHaveSameTypeAs, ErrRemoteTriggerResponse{})
+		So(err.(ErrRemoteTriggerResponse).Reason(), ShouldEqual, "Remote server credentials can't be read from "+filepath.Join(dir, "missing"))
+	})
+}
+
+func TestCreateHTTPClient(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "moira-remote-tls")
+	defer os.RemoveAll(dir)
+
+	Convey("Client without TLS settings uses default transport", t, func() {
+		client, err := createHTTPClient(&Config{Timeout: time.Minute})
+		So(err, ShouldBeNil)
+		So(client.Transport, ShouldBeNil)
+		So(client.Timeout, ShouldEqual, time.Minute)
+	})
+
+	Convey("Client with mutual TLS", t, func() {
+		clientCert, clientKey := generateTestCertificate()
+		clientCertPool := x509.NewCertPool()
+		clientCertPool.AppendCertsFromPEM(clientCert)
+
+		server := httptest.NewUnstartedServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			rw.Write([]byte("[]")) //nolint
+		}))
+		server.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: clientCertPool}
+		server.StartTLS()
+		defer server.Close()
+		caFile := writeTestFile(dir, "ca.pem", string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})))
+
+		Convey("Request with client certificate succeeds", func() {
+			config := &Config{URL: server.URL, Enabled: true, TLS: TLSConfig{
+				CAFile:   caFile,
+				CertFile: writeTestFile(dir, "client.pem", string(clientCert)),
+				KeyFile:  writeTestFile(dir, "client-key.pem", string(clientKey)),
+			}}
+			remote := Create(config).(*Remote)
+			isConfigured, err := remote.IsConfigured()
+			So(err, ShouldBeNil)
+			So(isConfigured, ShouldBeTrue)
+			isAvailable, err := remote.IsRemoteAvailable()
+			So(err, ShouldBeNil)
+			So(isAvailable, ShouldBeTrue)
+		})
+
+		Convey("Request without client certificate fails", func() {
+			remote := Create(&Config{URL: server.URL, Enabled: true, TLS: TLSConfig{CAFile: caFile}}).(*Remote)
+			isAvailable, err := remote.IsRemoteAvailable()
+			So(err, ShouldNotBeNil)
+			So(isAvailable, ShouldBeFalse)
+		})
+	})
+
+	Convey("Remote with invalid TLS settings is not configured", t, func() {
+		remote := Create(&Config{URL: "https://test/", Enabled: true, TLS: TLSConfig{CertFile: filepath.Join(dir, "missing.pem")}})
+		isConfigured, err := remote.IsConfigured()
+		So(isConfigured, ShouldBeFalse)
+		So(err, ShouldNotBeNil)
+	})
+}
+
+func writeTestFile(dir, name, content string) string {
+	fileName := filepath.Join(dir, name)
+	ioutil.WriteFile(fileName, []byte(content), 0600) //nolint
+	return fileName
+}
+
+// generateTestCertificate returns PEM encoded self-signed client certificate and its key
+func generateTestCertificate() ([]byte, []byte) {
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "moira"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	cert, _ := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	keyBytes, _ := x509.MarshalECPrivateKey(key)
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyBytes})
+}
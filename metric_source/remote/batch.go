@@ -0,0 +1,137 @@
+package remote
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/moira-alert/moira"
+	metricSource "github.com/moira-alert/moira/metric_source"
+)
+
+const batchTargetPrefix = "__moira_target_"
+
+// FetchBatch fetches several remote targets by render requests containing up to MaxBatchTargets targets each.
+// Every target is wrapped into aliasSub to find out which target returned series belong to.
+// If remote server responds to batch with error status targets are fetched one by one, so error is bound to exact target
+func (remote *Remote) FetchBatch(targets []string, from, until int64, allowRealTimeAlerting bool) ([]metricSource.FetchResult, error) {
+	batchSize := remote.config.MaxBatchTargets
+	if batchSize <= 1 {
+		batchSize = 1
+	}
+	results := make([]metricSource.FetchResult, 0, len(targets))
+	for start := 0; start < len(targets); start += batchSize {
+		end := start + batchSize
+		if end > len(targets) {
+			end = len(targets)
+		}
+		batchResults, err := remote.fetchBatch(targets[start:end], from, until, allowRealTimeAlerting)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, batchResults...)
+	}
+	return results, nil
+}
+
+func (remote *Remote) fetchBatch(targets []string, from, until int64, allowRealTimeAlerting bool) ([]metricSource.FetchResult, error) {
+	if len(targets) == 1 {
+		fetchResult, err := remote.Fetch(targets[0], from, until, allowRealTimeAlerting)
+		if err != nil {
+			return nil, err
+		}
+		return []metricSource.FetchResult{fetchResult}, nil
+	}
+	results, err := remote.fetchMultipleTargets(targets, from, until, allowRealTimeAlerting)
+	if err == nil {
+		return results, nil
+	}
+	// Error statuses can be caused by any of targets, otherwise remote server can't serve targets one by one as well
+	switch err.(type) {
+	case ErrRemoteTimeout, ErrRemoteUnavailable, ErrRemoteAuth, ErrRemoteCircuitBreakerOpen:
+		return nil, ErrRemoteTriggerResponse{
+			InternalError: err,
+			Target:        strings.Join(targets, ", "),
+		}
+	}
+	results = make([]metricSource.FetchResult, 0, len(targets))
+	for _, target := range targets {
+		fetchResult, err := remote.Fetch(target, from, until, allowRealTimeAlerting)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, fetchResult)
+	}
+	return results, nil
+}
+
+func (remote *Remote) fetchMultipleTargets(targets []string, from, until int64, allowRealTimeAlerting bool) ([]metricSource.FetchResult, error) {
+	from = moira.MaxInt64(from, until-int64(remote.config.MetricsTTL.Seconds()))
+
+	aliasedTargets := make([]string, 0, len(targets))
+	for targetIndex, target := range targets {
+		aliasedTargets = append(aliasedTargets, fmt.Sprintf(`aliasSub(%s, "^", "%s")`, target, getBatchTargetAlias(targetIndex)))
+	}
+	req, err := remote.prepareRequest(from, until, aliasedTargets...)
+	if err != nil {
+		return nil, err
+	}
+	body, err := remote.request(req)
+	if err != nil {
+		return nil, err
+	}
+	metricsData, err := decodeBody(body)
+	if err != nil {
+		return nil, err
+	}
+	targetsMetricsData, err := splitBatchMetricsData(metricsData, len(targets))
+	if err != nil {
+		return nil, err
+	}
+	results := make([]metricSource.FetchResult, 0, len(targets))
+	for _, targetMetricsData := range targetsMetricsData {
+		fetchResult := convertResponse(targetMetricsData, allowRealTimeAlerting)
+		results = append(results, &fetchResult)
+	}
+	return results, nil
+}
+
+// splitBatchMetricsData groups series of batch response by targets and removes target aliases from series names
+func splitBatchMetricsData(metricsData []metricSource.MetricData, targetsCount int) ([][]metricSource.MetricData, error) {
+	result := make([][]metricSource.MetricData, targetsCount)
+	for targetIndex := range result {
+		result[targetIndex] = make([]metricSource.MetricData, 0)
+	}
+	for _, metricData := range metricsData {
+		targetIndex, name, err := parseBatchTargetAlias(metricData.Name)
+		if err != nil {
+			return nil, err
+		}
+		if targetIndex >= targetsCount {
+			return nil, fmt.Errorf("unexpected target index %d in batch response series '%s'", targetIndex, metricData.Name)
+		}
+		metricData.Name = name
+		result[targetIndex] = append(result[targetIndex], metricData)
+	}
+	return result, nil
+}
+
+func getBatchTargetAlias(targetIndex int) string {
+	return batchTargetPrefix + strconv.Itoa(targetIndex) + "__"
+}
+
+func parseBatchTargetAlias(name string) (int, string, error) {
+	if !strings.HasPrefix(name, batchTargetPrefix) {
+		return 0, "", fmt.Errorf("batch response series '%s' has no target alias", name)
+	}
+	rest := name[len(batchTargetPrefix):]
+	separatorIndex := strings.Index(rest, "__")
+	if separatorIndex < 0 {
+		return 0, "", fmt.Errorf("batch response series '%s' has no target alias", name)
+	}
+	targetIndex, err := strconv.Atoi(rest[:separatorIndex])
+	if err != nil {
+		return 0, "", fmt.Errorf("batch response series '%s' has invalid target alias: %s", name, err.Error())
+	}
+	return targetIndex, rest[separatorIndex+2:], nil
+}
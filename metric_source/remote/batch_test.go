@@ -0,0 +1,132 @@
+package remote
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"sync"
+	"testing"
+
+	metricSource "github.com/moira-alert/moira/metric_source"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+var aliasSubRegex = regexp.MustCompile(`^aliasSub\((.+), "\^", "(.+)"\)$`)
+
+// createBatchServer creates graphite render API stub which returns one series per target named by target
+func createBatchServer(failBatches bool, requests *[][]string) *httptest.Server {
+	var lock sync.Mutex
+	return httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		targets := req.URL.Query()["target"]
+		lock.Lock()
+		*requests = append(*requests, targets)
+		lock.Unlock()
+		if failBatches && len(targets) > 1 {
+			rw.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		response := make([]map[string]interface{}, 0, len(targets))
+		for _, target := range targets {
+			name := target
+			if matches := aliasSubRegex.FindStringSubmatch(target); matches != nil {
+				name = matches[2] + matches[1]
+			}
+			response = append(response, map[string]interface{}{
+				"target":     name,
+				"datapoints": [][2]float64{{1, 300}, {2, 360}},
+			})
+		}
+		body, _ := json.Marshal(response)
+		rw.Write(body) //nolint
+	}))
+}
+
+func getFetchResultsNames(results []metricSource.FetchResult) []string {
+	names := make([]string, 0, len(results))
+	for _, result := range results {
+		for _, metricData := range result.GetMetricsData() {
+			names = append(names, metricData.Name)
+		}
+	}
+	return names
+}
+
+func TestFetchBatch(t *testing.T) {
+	var from int64 = 300
+	var until int64 = 400
+	targets := []string{"foo.bar", "foo.baz", "sumSeries(foo.*)"}
+
+	Convey("Targets are fetched by batches", t, func() {
+		requests := make([][]string, 0)
+		server := createBatchServer(false, &requests)
+		remote := Remote{client: server.Client(), config: &Config{URL: server.URL, MaxBatchTargets: 2}}
+
+		results, err := remote.FetchBatch(targets, from, until, true)
+		So(err, ShouldBeNil)
+		So(getFetchResultsNames(results), ShouldResemble, targets)
+		So(requests, ShouldResemble, [][]string{
+			{`aliasSub(foo.bar, "^", "__moira_target_0__")`, `aliasSub(foo.baz, "^", "__moira_target_1__")`},
+			{"sumSeries(foo.*)"},
+		})
+	})
+
+	Convey("Targets are fetched one by one if batching is disabled", t, func() {
+		requests := make([][]string, 0)
+		server := createBatchServer(false, &requests)
+		remote := Remote{client: server.Client(), config: &Config{URL: server.URL}}
+
+		results, err := remote.FetchBatch(targets, from, until, true)
+		So(err, ShouldBeNil)
+		So(getFetchResultsNames(results), ShouldResemble, targets)
+		So(requests, ShouldHaveLength, 3)
+	})
+
+	Convey("Targets are fetched one by one if batch request fails", t, func() {
+		requests := make([][]string, 0)
+		server := createBatchServer(true, &requests)
+		remote := Remote{client: server.Client(), config: &Config{URL: server.URL, MaxBatchTargets: 3}}
+
+		results, err := remote.FetchBatch(targets, from, until, true)
+		So(err, ShouldBeNil)
+		So(getFetchResultsNames(results), ShouldResemble, targets)
+		So(requests, ShouldHaveLength, 4)
+	})
+
+	Convey("Error of target is returned if it can't be fetched", t, func() {
+		server := createServer([]byte("Bad target"), http.StatusBadRequest)
+		remote := Remote{client: server.Client(), config: &Config{URL: server.URL, MaxBatchTargets: 3}}
+
+		results, err := remote.FetchBatch(targets, from, until, true)
+		So(results, ShouldBeNil)
+		So(err, ShouldHaveSameTypeAs, ErrRemoteTriggerResponse{})
+		So(err.(ErrRemoteTriggerResponse).Target, ShouldEqual, "foo.bar")
+	})
+}
+
+func TestSplitBatchMetricsData(t *testing.T) {
+	Convey("Series are grouped by targets", t, func() {
+		metricsData := []metricSource.MetricData{
+			{Name: "__moira_target_1__foo.baz"},
+			{Name: "__moira_target_0__foo.bar"},
+			{Name: "__moira_target_1__foo.qux"},
+		}
+		result, err := splitBatchMetricsData(metricsData, 3)
+		So(err, ShouldBeNil)
+		So(result, ShouldResemble, [][]metricSource.MetricData{
+			{{Name: "foo.bar"}},
+			{{Name: "foo.baz"}, {Name: "foo.qux"}},
+			{},
+		})
+	})
+
+	Convey("Series without target alias can't be grouped", t, func() {
+		_, err := splitBatchMetricsData([]metricSource.MetricData{{Name: "foo.bar"}}, 1)
+		So(err, ShouldNotBeNil)
+	})
+
+	Convey("Series with unknown target index can't be grouped", t, func() {
+		_, err := splitBatchMetricsData([]metricSource.MetricData{{Name: "__moira_target_1__foo.bar"}}, 1)
+		So(err, ShouldNotBeNil)
+	})
+}
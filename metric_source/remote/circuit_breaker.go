@@ -0,0 +1,66 @@
+package remote
+
+import (
+	"sync"
+	"time"
+)
+
+// circuitBreaker suspends requests to remote server after threshold of consecutive transient failures.
+// When suspension timeout is over single probe request is allowed, it closes breaker on success or opens it again on failure
+type circuitBreaker struct {
+	lock        sync.Mutex
+	failures    int
+	openedUntil time.Time
+	probing     bool
+}
+
+// allow returns error if requests are suspended, threshold less than 1 disables breaker
+func (breaker *circuitBreaker) allow(threshold int) error {
+	if threshold < 1 {
+		return nil
+	}
+	breaker.lock.Lock()
+	defer breaker.lock.Unlock()
+	if breaker.openedUntil.IsZero() {
+		return nil
+	}
+	if breaker.probing || time.Now().Before(breaker.openedUntil) {
+		return ErrRemoteCircuitBreakerOpen{Until: breaker.openedUntil}
+	}
+	breaker.probing = true
+	return nil
+}
+
+// check returns error if requests are suspended, unlike allow it doesn't take probe request
+func (breaker *circuitBreaker) check(threshold int) error {
+	if threshold < 1 {
+		return nil
+	}
+	breaker.lock.Lock()
+	defer breaker.lock.Unlock()
+	if breaker.openedUntil.IsZero() || !breaker.probing && !time.Now().Before(breaker.openedUntil) {
+		return nil
+	}
+	return ErrRemoteCircuitBreakerOpen{Until: breaker.openedUntil}
+}
+
+// report counts request result, only transient errors are counted as failures
+func (breaker *circuitBreaker) report(err error, threshold int, timeout time.Duration) {
+	if threshold < 1 {
+		return
+	}
+	breaker.lock.Lock()
+	defer breaker.lock.Unlock()
+	if !isTransientError(err) {
+		breaker.failures = 0
+		breaker.openedUntil = time.Time{}
+		breaker.probing = false
+		return
+	}
+	breaker.failures++
+	if breaker.probing || breaker.failures >= threshold {
+		breaker.failures = 0
+		breaker.openedUntil = time.Now().Add(timeout)
+		breaker.probing = false
+	}
+}
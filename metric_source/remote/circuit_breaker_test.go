@@ -0,0 +1,59 @@
+package remote
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	transientErr := ErrRemoteUnavailable{InternalError: fmt.Errorf("connection refused")}
+	timeout := time.Millisecond * 50
+
+	Convey("Disabled breaker always allows requests", t, func() {
+		breaker := circuitBreaker{}
+		for i := 0; i < 10; i++ {
+			breaker.report(transientErr, 0, timeout)
+		}
+		So(breaker.allow(0), ShouldBeNil)
+	})
+
+	Convey("Breaker opens after threshold of consecutive transient failures", t, func() {
+		breaker := circuitBreaker{}
+		breaker.report(transientErr, 3, timeout)
+		breaker.report(transientErr, 3, timeout)
+		So(breaker.allow(3), ShouldBeNil)
+		breaker.report(transientErr, 3, timeout)
+		So(breaker.allow(3), ShouldHaveSameTypeAs, ErrRemoteCircuitBreakerOpen{})
+		So(breaker.check(3), ShouldHaveSameTypeAs, ErrRemoteCircuitBreakerOpen{})
+
+		Convey("Single probe is allowed after timeout", func() {
+			time.Sleep(timeout)
+			So(breaker.check(3), ShouldBeNil)
+			So(breaker.allow(3), ShouldBeNil)
+			So(breaker.allow(3), ShouldHaveSameTypeAs, ErrRemoteCircuitBreakerOpen{})
+			So(breaker.check(3), ShouldHaveSameTypeAs, ErrRemoteCircuitBreakerOpen{})
+
+			Convey("Successful probe closes breaker", func() {
+				breaker.report(nil, 3, timeout)
+				So(breaker.allow(3), ShouldBeNil)
+				So(breaker.allow(3), ShouldBeNil)
+			})
+
+			Convey("Failed probe opens breaker again", func() {
+				breaker.report(transientErr, 3, timeout)
+				So(breaker.allow(3), ShouldHaveSameTypeAs, ErrRemoteCircuitBreakerOpen{})
+			})
+		})
+	})
+
+	Convey("Not transient errors reset failures", t, func() {
+		breaker := circuitBreaker{}
+		breaker.report(transientErr, 2, timeout)
+		breaker.report(ErrRemoteBadTarget{StatusCode: 400}, 2, timeout)
+		breaker.report(transientErr, 2, timeout)
+		So(breaker.allow(2), ShouldBeNil)
+	})
+}
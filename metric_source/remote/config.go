@@ -11,6 +11,37 @@ type Config struct {
 	User          string
 	Password      string
 	Enabled       bool
+	// MaxBatchTargets is max count of targets fetched by one render request, batching is disabled if it is less than 2
+	MaxBatchTargets int
+	// MaxRetries is max count of retries of request failed with timeout, connection, 408, 429 or 5xx error,
+	// request with all retries takes no longer than CheckInterval
+	MaxRetries int
+	// RetryDelay is delay before first retry, it is doubled every next retry
+	RetryDelay time.Duration
+	// CircuitBreakerThreshold is count of consecutive failed requests after which requests are suspended, 0 disables circuit breaker
+	CircuitBreakerThreshold int
+	// CircuitBreakerTimeout is period requests are suspended for
+	CircuitBreakerTimeout time.Duration
+	// PasswordFile is file password is read from, it takes precedence over Password
+	PasswordFile string
+	// BearerToken is sent in Authorization header, it takes precedence over basic auth
+	BearerToken string
+	// BearerTokenFile is file bearer token is read from, it takes precedence over BearerToken
+	BearerTokenFile string
+	// Headers are added to every request
+	Headers map[string]string
+	// HeaderFiles are headers added to every request with values read from files
+	HeaderFiles map[string]string
+	// TLS represents TLS settings of connection to remote storage
+	TLS TLSConfig
+}
+
+// TLSConfig represents TLS settings of connection to remote storage, client certificate is used for mutual TLS
+type TLSConfig struct {
+	CAFile             string
+	CertFile           string
+	KeyFile            string
+	InsecureSkipVerify bool
 }
 
 // isEnabled checks that remote config is enabled (url is defined and enabled flag is set)
@@ -0,0 +1,113 @@
+package remote
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// ErrRemoteTimeout is used when remote server doesn't respond in configured timeout
+type ErrRemoteTimeout struct {
+	Timeout       time.Duration
+	InternalError error
+}
+
+// Error is a representation of Error interface method
+func (err ErrRemoteTimeout) Error() string {
+	return fmt.Sprintf("remote server request timed out after %s: %v", err.Timeout.String(), err.InternalError)
+}
+
+// ErrRemoteUnavailable is used when remote server can't be reached or connection was reset
+type ErrRemoteUnavailable struct {
+	InternalError error
+}
+
+// Error is a representation of Error interface method
+func (err ErrRemoteUnavailable) Error() string {
+	return fmt.Sprintf("remote server is not available: %v", err.InternalError)
+}
+
+// ErrRemoteServerError is used when remote server responds with unexpected status, e.g. 5xx
+type ErrRemoteServerError struct {
+	StatusCode int
+	Body       string
+}
+
+// Error is a representation of Error interface method
+func (err ErrRemoteServerError) Error() string {
+	return fmt.Sprintf("bad response status %d: %s", err.StatusCode, err.Body)
+}
+
+// ErrRemoteAuth is used when remote server rejects credentials with 401 or 403 status
+type ErrRemoteAuth struct {
+	StatusCode int
+	Body       string
+}
+
+// Error is a representation of Error interface method
+func (err ErrRemoteAuth) Error() string {
+	return fmt.Sprintf("bad response status %d: %s", err.StatusCode, err.Body)
+}
+
+// ErrRemoteBadTarget is used when remote server rejects requested target with 4xx status
+type ErrRemoteBadTarget struct {
+	StatusCode int
+	Body       string
+}
+
+// Error is a representation of Error interface method
+func (err ErrRemoteBadTarget) Error() string {
+	return fmt.Sprintf("bad response status %d: %s", err.StatusCode, err.Body)
+}
+
+// ErrRemoteCircuitBreakerOpen is used when requests to remote server are suspended after consecutive failures
+type ErrRemoteCircuitBreakerOpen struct {
+	Until time.Time
+}
+
+// Error is a representation of Error interface method
+func (err ErrRemoteCircuitBreakerOpen) Error() string {
+	return fmt.Sprintf("remote server requests are suspended until %s after consecutive failures", err.Until.Format(time.RFC3339))
+}
+
+// ErrRemoteSecret is used when secret of remote server credentials can't be read from file
+type ErrRemoteSecret struct {
+	FileName      string
+	InternalError error
+}
+
+// Error is a representation of Error interface method
+func (err ErrRemoteSecret) Error() string {
+	return fmt.Sprintf("failed to read secret file %s: %v", err.FileName, err.InternalError)
+}
+
+// getResponseStatusError returns error of remote response by its status, nil is returned for 200 status
+func getResponseStatusError(statusCode int, body []byte) error {
+	switch {
+	case statusCode == http.StatusOK:
+		return nil
+	case statusCode == http.StatusUnauthorized || statusCode == http.StatusForbidden:
+		return ErrRemoteAuth{StatusCode: statusCode, Body: string(body)}
+	case isThrottlingStatus(statusCode):
+		return ErrRemoteServerError{StatusCode: statusCode, Body: string(body)}
+	case statusCode >= http.StatusBadRequest && statusCode < http.StatusInternalServerError:
+		return ErrRemoteBadTarget{StatusCode: statusCode, Body: string(body)}
+	}
+	return ErrRemoteServerError{StatusCode: statusCode, Body: string(body)}
+}
+
+// isTransientError returns true if request failed with error which can disappear on retry
+func isTransientError(err error) bool {
+	switch err := err.(type) {
+	case ErrRemoteTimeout, ErrRemoteUnavailable:
+		return true
+	case ErrRemoteServerError:
+		return err.StatusCode >= http.StatusInternalServerError || isThrottlingStatus(err.StatusCode)
+	}
+	return false
+}
+
+// isThrottlingStatus returns true if remote server asks to repeat request later
+func isThrottlingStatus(statusCode int) bool {
+	return statusCode == http.StatusRequestTimeout || statusCode == http.StatusTooManyRequests
+}
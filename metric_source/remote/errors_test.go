@@ -0,0 +1,50 @@
+package remote
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestGetResponseStatusError(t *testing.T) {
+	Convey("Errors are distinguished by response status", t, func() {
+		body := []byte("Some string")
+		So(getResponseStatusError(http.StatusOK, body), ShouldBeNil)
+		So(getResponseStatusError(http.StatusUnauthorized, body), ShouldResemble, ErrRemoteAuth{StatusCode: http.StatusUnauthorized, Body: "Some string"})
+		So(getResponseStatusError(http.StatusForbidden, body), ShouldResemble, ErrRemoteAuth{StatusCode: http.StatusForbidden, Body: "Some string"})
+		So(getResponseStatusError(http.StatusBadRequest, body), ShouldResemble, ErrRemoteBadTarget{StatusCode: http.StatusBadRequest, Body: "Some string"})
+		So(getResponseStatusError(http.StatusRequestTimeout, body), ShouldResemble, ErrRemoteServerError{StatusCode: http.StatusRequestTimeout, Body: "Some string"})
+		So(getResponseStatusError(http.StatusTooManyRequests, body), ShouldResemble, ErrRemoteServerError{StatusCode: http.StatusTooManyRequests, Body: "Some string"})
+		So(getResponseStatusError(http.StatusBadGateway, body), ShouldResemble, ErrRemoteServerError{StatusCode: http.StatusBadGateway, Body: "Some string"})
+	})
+}
+
+func TestIsTransientError(t *testing.T) {
+	Convey("Only timeouts, connection, throttling and 5xx errors are transient", t, func() {
+		So(isTransientError(ErrRemoteTimeout{}), ShouldBeTrue)
+		So(isTransientError(ErrRemoteUnavailable{}), ShouldBeTrue)
+		So(isTransientError(ErrRemoteServerError{StatusCode: http.StatusServiceUnavailable}), ShouldBeTrue)
+		So(isTransientError(ErrRemoteServerError{StatusCode: http.StatusRequestTimeout}), ShouldBeTrue)
+		So(isTransientError(ErrRemoteServerError{StatusCode: http.StatusTooManyRequests}), ShouldBeTrue)
+		So(isTransientError(ErrRemoteServerError{StatusCode: http.StatusMultipleChoices}), ShouldBeFalse)
+		So(isTransientError(ErrRemoteAuth{StatusCode: http.StatusUnauthorized}), ShouldBeFalse)
+		So(isTransientError(ErrRemoteBadTarget{StatusCode: http.StatusBadRequest}), ShouldBeFalse)
+		So(isTransientError(ErrRemoteCircuitBreakerOpen{}), ShouldBeFalse)
+		So(isTransientError(fmt.Errorf("invalid json")), ShouldBeFalse)
+		So(isTransientError(nil), ShouldBeFalse)
+	})
+}
+
+func TestErrRemoteTriggerResponse_Reason(t *testing.T) {
+	Convey("Reason describes remote failure", t, func() {
+		So(ErrRemoteTriggerResponse{InternalError: ErrRemoteTimeout{Timeout: time.Minute}}.Reason(), ShouldEqual, "Remote server request timed out after 1m0s")
+		So(ErrRemoteTriggerResponse{InternalError: ErrRemoteServerError{StatusCode: http.StatusBadGateway}}.Reason(), ShouldEqual, "Remote server unavailable, response status 502")
+		So(ErrRemoteTriggerResponse{InternalError: ErrRemoteAuth{StatusCode: http.StatusUnauthorized}}.Reason(), ShouldEqual, "Remote server authorization failed, response status 401")
+		So(ErrRemoteTriggerResponse{InternalError: ErrRemoteBadTarget{Body: "unknown function"}, Target: "foo(bar)"}.Reason(), ShouldEqual, "Remote server rejected target foo(bar): unknown function")
+		So(ErrRemoteTriggerResponse{InternalError: ErrRemoteCircuitBreakerOpen{}}.Reason(), ShouldEqual, "Remote server requests are suspended after consecutive failures")
+		So(ErrRemoteTriggerResponse{InternalError: ErrRemoteUnavailable{}}.Reason(), ShouldEqual, "Remote server unavailable")
+	})
+}
@@ -12,6 +12,9 @@ import (
 // ErrRemoteStorageDisabled is used to prevent remote.Fetch calls when remote storage is disabled
 var ErrRemoteStorageDisabled = fmt.Errorf("remote graphite storage is not enabled")
 
+// availabilityCheckAttempts is number of requests made to check that remote server is available
+const availabilityCheckAttempts = 3
+
 // ErrRemoteTriggerResponse is a custom error when remote trigger check fails
 type ErrRemoteTriggerResponse struct {
 	InternalError error
@@ -23,17 +26,41 @@ func (err ErrRemoteTriggerResponse) Error() string {
 	return err.InternalError.Error()
 }
 
+// Reason returns short description of failure to be shown in trigger check message
+func (err ErrRemoteTriggerResponse) Reason() string {
+	switch internalErr := err.InternalError.(type) {
+	case ErrRemoteTimeout:
+		return fmt.Sprintf("Remote server request timed out after %s", internalErr.Timeout.String())
+	case ErrRemoteServerError:
+		return fmt.Sprintf("Remote server unavailable, response status %d", internalErr.StatusCode)
+	case ErrRemoteAuth:
+		return fmt.Sprintf("Remote server authorization failed, response status %d", internalErr.StatusCode)
+	case ErrRemoteBadTarget:
+		return fmt.Sprintf("Remote server rejected target %s: %s", err.Target, internalErr.Body)
+	case ErrRemoteCircuitBreakerOpen:
+		return "Remote server requests are suspended after consecutive failures"
+	case ErrRemoteSecret:
+		return fmt.Sprintf("Remote server credentials can't be read from %s", internalErr.FileName)
+	}
+	return "Remote server unavailable"
+}
+
 // Remote is implementation of MetricSource interface, which implements fetch metrics method from remote graphite installation
 type Remote struct {
-	config *Config
-	client *http.Client
+	config    *Config
+	client    *http.Client
+	clientErr error
+	breaker   circuitBreaker
+	secrets   secretFiles
 }
 
-// Create configures remote metric source
+// Create configures remote metric source, remote with invalid TLS settings is not configured
 func Create(config *Config) metricSource.MetricSource {
+	client, err := createHTTPClient(config)
 	return &Remote{
-		config: config,
-		client: &http.Client{Timeout: config.Timeout},
+		config:    config,
+		client:    client,
+		clientErr: err,
 	}
 }
 
@@ -50,7 +77,7 @@ func (remote *Remote) Fetch(target string, from, until int64, allowRealTimeAlert
 			Target:        target,
 		}
 	}
-	body, err := remote.makeRequest(req)
+	body, err := remote.request(req)
 	if err != nil {
 		return nil, ErrRemoteTriggerResponse{
 			InternalError: err,
@@ -76,6 +103,9 @@ func (remote *Remote) GetMetricsTTLSeconds() int64 {
 // IsConfigured returns false in cases that user does not properly configure remote settings like graphite URL
 func (remote *Remote) IsConfigured() (bool, error) {
 	if remote.config.isEnabled() {
+		if remote.clientErr != nil {
+			return false, fmt.Errorf("invalid remote TLS settings: %s", remote.clientErr.Error())
+		}
 		return true, nil
 	}
 	return false, ErrRemoteStorageDisabled
@@ -83,18 +113,37 @@ func (remote *Remote) IsConfigured() (bool, error) {
 
 // IsRemoteAvailable checks if graphite API is available and returns 200 response
 func (remote *Remote) IsRemoteAvailable() (bool, error) {
-	maxRetries := 3
+	if err := remote.breaker.check(remote.config.CircuitBreakerThreshold); err != nil {
+		return false, err
+	}
 	until := time.Now().Unix()
 	from := until - 600 //nolint
 	req, err := remote.prepareRequest(from, until, "NonExistingTarget")
 	if err != nil {
 		return false, err
 	}
-	for attempt := 0; attempt < maxRetries; attempt++ {
-		_, err = remote.makeRequest(req)
-		if err == nil {
+	return CheckAvailability(func() error {
+		_, err := remote.request(req)
+		return err
+	})
+}
+
+// IsSuspended returns true if requests to remote server are suspended by circuit breaker
+func (remote *Remote) IsSuspended() bool {
+	return remote.breaker.check(remote.config.CircuitBreakerThreshold) != nil
+}
+
+// CheckAvailability makes availability request until it succeeds or attempts are over,
+// it gives up at once if requests are suspended by circuit breaker
+func CheckAvailability(request func() error) (bool, error) {
+	var err error
+	for attempt := 0; attempt < availabilityCheckAttempts; attempt++ {
+		if err = request(); err == nil {
 			return true, nil
 		}
+		if _, ok := err.(ErrRemoteCircuitBreakerOpen); ok {
+			break
+		}
 	}
 	return false, err
 }
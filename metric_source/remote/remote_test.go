@@ -39,7 +39,7 @@ func TestIsRemoteAvailable(t *testing.T) {
 		remote := Remote{client: server.Client(), config: &Config{URL: server.URL}}
 		isAvailable, err := remote.IsRemoteAvailable()
 		So(isAvailable, ShouldBeFalse)
-		So(err, ShouldResemble, fmt.Errorf("bad response status %d: %s", http.StatusInternalServerError, "Some string"))
+		So(err, ShouldResemble, ErrRemoteServerError{StatusCode: http.StatusInternalServerError, Body: "Some string"})
 	})
 }
 
@@ -1,13 +1,16 @@
 package remote
 
 import (
-	"fmt"
+	"context"
 	"io/ioutil"
+	"math/rand"
 	"net/http"
+	"net/url"
 	"strconv"
+	"time"
 )
 
-func (remote *Remote) prepareRequest(from, until int64, target string) (*http.Request, error) {
+func (remote *Remote) prepareRequest(from, until int64, targets ...string) (*http.Request, error) {
 	req, err := http.NewRequest("GET", remote.config.URL, nil)
 	if err != nil {
 		return nil, err
@@ -15,36 +18,80 @@ func (remote *Remote) prepareRequest(from, until int64, target string) (*http.Re
 	q := req.URL.Query()
 	q.Add("format", "json")
 	q.Add("from", strconv.FormatInt(from, 10))
-	q.Add("target", target)
+	for _, target := range targets {
+		q.Add("target", target)
+	}
 	q.Add("until", strconv.FormatInt(until, 10))
 	req.URL.RawQuery = q.Encode()
-	if remote.config.User != "" && remote.config.Password != "" {
-		req.SetBasicAuth(remote.config.User, remote.config.Password)
+	if err := remote.setRequestAuth(req); err != nil {
+		return nil, err
 	}
 	return req, nil
 }
 
+// request makes request to remote server unless circuit breaker is open, transient errors are retried
+// with exponentially growing delay and jitter, request with all retries takes no longer than check interval
+func (remote *Remote) request(req *http.Request) ([]byte, error) {
+	if err := remote.breaker.allow(remote.config.CircuitBreakerThreshold); err != nil {
+		return nil, err
+	}
+	if remote.config.CheckInterval > 0 {
+		ctx, cancel := context.WithTimeout(req.Context(), remote.config.CheckInterval)
+		defer cancel()
+		req = req.WithContext(ctx)
+	}
+	deadline, hasDeadline := req.Context().Deadline()
+	var body []byte
+	var err error
+	for attempt := 0; ; attempt++ {
+		body, err = remote.makeRequest(req)
+		if err == nil || !isTransientError(err) || attempt >= remote.config.MaxRetries {
+			break
+		}
+		delay := getRetryDelay(remote.config.RetryDelay, attempt)
+		if hasDeadline && time.Now().Add(delay).After(deadline) {
+			break
+		}
+		time.Sleep(delay)
+	}
+	remote.breaker.report(err, remote.config.CircuitBreakerThreshold, remote.config.CircuitBreakerTimeout)
+	return body, err
+}
+
 func (remote *Remote) makeRequest(req *http.Request) ([]byte, error) {
+	return MakeRequest(remote.client, req)
+}
+
+// MakeRequest makes request with given client and returns response body,
+// errors are typed by their cause so they can be handled like errors of remote graphite
+func MakeRequest(client *http.Client, req *http.Request) ([]byte, error) {
 	var body []byte
 
-	resp, err := remote.client.Do(req)
+	resp, err := client.Do(req)
 	if resp != nil {
 		defer resp.Body.Close()
 	}
 
 	if err != nil {
-		return body, fmt.Errorf("The remote server is not available or the response was reset by timeout. " + //nolint
-			"TTL: %s, PATH: %s, ERROR: %v ", remote.client.Timeout.String(), req.URL.RawPath, err)
+		if urlErr, ok := err.(*url.Error); ok && urlErr.Timeout() {
+			return body, ErrRemoteTimeout{Timeout: client.Timeout, InternalError: err}
+		}
+		return body, ErrRemoteUnavailable{InternalError: err}
 	}
 
 	body, err = ioutil.ReadAll(resp.Body)
 	if err != nil {
-		return body, err
+		return body, ErrRemoteUnavailable{InternalError: err}
 	}
 
-	if resp.StatusCode != 200 { //nolint
-		return body, fmt.Errorf("bad response status %d: %s", resp.StatusCode, string(body))
-	}
+	return body, getResponseStatusError(resp.StatusCode, body)
+}
 
-	return body, nil
+// getRetryDelay returns delay before retry, delay is doubled every attempt and randomized by jitter up to its half
+func getRetryDelay(retryDelay time.Duration, attempt int) time.Duration {
+	delay := retryDelay << uint(attempt)
+	if delay <= 0 {
+		return 0
+	}
+	return delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1)) //nolint
 }
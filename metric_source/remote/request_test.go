@@ -1,10 +1,10 @@
 package remote
 
 import (
-	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	. "github.com/smartystreets/goconvey/convey"
 )
@@ -64,7 +64,7 @@ func TestMakeRequest(t *testing.T) {
 		remote := Remote{client: server.Client(), config: &Config{URL: server.URL}}
 		request, _ := remote.prepareRequest(from, until, target)
 		actual, err := remote.makeRequest(request)
-		So(err, ShouldResemble, fmt.Errorf("bad response status %d: %s", http.StatusInternalServerError, string(body)))
+		So(err, ShouldResemble, ErrRemoteServerError{StatusCode: http.StatusInternalServerError, Body: string(body)})
 		So(actual, ShouldResemble, body)
 	})
 
@@ -78,6 +78,110 @@ func TestMakeRequest(t *testing.T) {
 	})
 }
 
+func TestRequest(t *testing.T) {
+	var from int64 = 300
+	var until int64 = 500
+	target := "foo.bar"
+
+	Convey("Transient errors are retried", t, func() {
+		requestsCount := 0
+		server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			requestsCount++
+			if requestsCount < 3 { //nolint
+				rw.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			rw.Write([]byte("[]")) //nolint
+		}))
+		remote := Remote{client: server.Client(), config: &Config{URL: server.URL, MaxRetries: 2, RetryDelay: time.Millisecond}}
+		request, _ := remote.prepareRequest(from, until, target)
+		body, err := remote.request(request)
+		So(err, ShouldBeNil)
+		So(body, ShouldResemble, []byte("[]"))
+		So(requestsCount, ShouldEqual, 3)
+	})
+
+	Convey("Retries are bounded", t, func() {
+		server := createServer([]byte("Some string"), http.StatusServiceUnavailable)
+		remote := Remote{client: server.Client(), config: &Config{URL: server.URL, MaxRetries: 2, RetryDelay: time.Millisecond}}
+		request, _ := remote.prepareRequest(from, until, target)
+		_, err := remote.request(request)
+		So(err, ShouldResemble, ErrRemoteServerError{StatusCode: http.StatusServiceUnavailable, Body: "Some string"})
+	})
+
+	Convey("Retries take no longer than check interval", t, func() {
+		requestsCount := 0
+		server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			requestsCount++
+			rw.WriteHeader(http.StatusTooManyRequests)
+		}))
+		remote := Remote{client: server.Client(), config: &Config{URL: server.URL, CheckInterval: time.Millisecond * 100, MaxRetries: 10, RetryDelay: time.Millisecond * 40}}
+		request, _ := remote.prepareRequest(from, until, target)
+		startedAt := time.Now()
+		_, err := remote.request(request)
+		So(err, ShouldResemble, ErrRemoteServerError{StatusCode: http.StatusTooManyRequests, Body: ""})
+		So(time.Since(startedAt), ShouldBeLessThan, time.Millisecond*100)
+		So(requestsCount, ShouldBeLessThan, 4)
+	})
+
+	Convey("Rejected target is not retried", t, func() {
+		requestsCount := 0
+		server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			requestsCount++
+			rw.WriteHeader(http.StatusBadRequest)
+		}))
+		remote := Remote{client: server.Client(), config: &Config{URL: server.URL, MaxRetries: 2, RetryDelay: time.Millisecond}}
+		request, _ := remote.prepareRequest(from, until, target)
+		_, err := remote.request(request)
+		So(err, ShouldResemble, ErrRemoteBadTarget{StatusCode: http.StatusBadRequest, Body: ""})
+		So(requestsCount, ShouldEqual, 1)
+	})
+
+	Convey("Requests are not made while circuit breaker is open", t, func() {
+		requestsCount := 0
+		server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			requestsCount++
+			rw.WriteHeader(http.StatusInternalServerError)
+		}))
+		remote := Remote{client: server.Client(), config: &Config{URL: server.URL, CircuitBreakerThreshold: 2, CircuitBreakerTimeout: time.Minute}}
+		request, _ := remote.prepareRequest(from, until, target)
+		remote.request(request) //nolint
+		remote.request(request) //nolint
+		_, err := remote.request(request)
+		So(err, ShouldHaveSameTypeAs, ErrRemoteCircuitBreakerOpen{})
+		So(requestsCount, ShouldEqual, 2)
+
+		So(remote.IsSuspended(), ShouldBeTrue)
+		isAvailable, err := remote.IsRemoteAvailable()
+		So(isAvailable, ShouldBeFalse)
+		So(err, ShouldHaveSameTypeAs, ErrRemoteCircuitBreakerOpen{})
+		So(requestsCount, ShouldEqual, 2)
+	})
+
+	Convey("Client timeout is distinguished", t, func() {
+		server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			time.Sleep(time.Millisecond * 100)
+		}))
+		client := server.Client()
+		client.Timeout = time.Millisecond * 10
+		remote := Remote{client: client, config: &Config{URL: server.URL}}
+		request, _ := remote.prepareRequest(from, until, target)
+		_, err := remote.request(request)
+		So(err, ShouldHaveSameTypeAs, ErrRemoteTimeout{})
+	})
+}
+
+func TestGetRetryDelay(t *testing.T) {
+	Convey("Retry delay grows exponentially with jitter", t, func() {
+		for attempt := 0; attempt < 3; attempt++ {
+			delay := getRetryDelay(time.Second, attempt)
+			maxDelay := time.Second << uint(attempt)
+			So(delay, ShouldBeBetweenOrEqual, maxDelay/2, maxDelay)
+		}
+		So(getRetryDelay(0, 1), ShouldEqual, 0)
+	})
+}
+
 func createServer(body []byte, statusCode int) *httptest.Server {
 	return httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
 		rw.WriteHeader(statusCode)
@@ -7,6 +7,17 @@ type MetricSource interface {
 	IsConfigured() (bool, error)
 }
 
+// BatchFetcher is implemented by metric sources which can fetch several targets over the same period by one request.
+// Results are returned in order of targets
+type BatchFetcher interface {
+	FetchBatch(targets []string, from int64, until int64, allowRealTimeAlerting bool) ([]FetchResult, error)
+}
+
+// SuspendableSource is implemented by metric sources which can suspend requests, e.g. after consecutive failures
+type SuspendableSource interface {
+	IsSuspended() bool
+}
+
 // FetchResult implements moira metric sources fetching result format
 type FetchResult interface {
 	GetMetricsData() []MetricData
@@ -6,19 +6,33 @@ import "github.com/moira-alert/moira"
 type CheckerMetrics struct {
 	LocalMetrics           *CheckMetrics
 	RemoteMetrics          *CheckMetrics
+	RemoteClustersMetrics  map[string]*CheckMetrics
 	MetricEventsChannelLen Histogram
 	UnusedTriggersCount    Histogram
 	MetricEventsHandleTime Timer
+	ShardCheckersCount     Histogram
+	ShardTriggersCount     Histogram
+	ShardRebalances        Meter
+	FetchCache             *FetchCacheMetrics
 }
 
 // GetCheckMetrics return check metrics dependent on given trigger type
 func (metrics *CheckerMetrics) GetCheckMetrics(trigger *moira.Trigger) *CheckMetrics {
 	if trigger.IsRemote {
+		if clusterMetrics, ok := metrics.RemoteClustersMetrics[trigger.ClusterName]; ok {
+			return clusterMetrics
+		}
 		return metrics.RemoteMetrics
 	}
 	return metrics.LocalMetrics
 }
 
+// FetchCacheMetrics is a collection of metrics of metric sources fetch cache, hit rate is Hits / (Hits + Misses)
+type FetchCacheMetrics struct {
+	Hits   Meter
+	Misses Meter
+}
+
 // CheckMetrics is a collection of metrics for trigger checks
 type CheckMetrics struct {
 	CheckError           Meter
@@ -34,6 +48,14 @@ func ConfigureCheckerMetrics(registry Registry, remoteEnabled bool) *CheckerMetr
 		MetricEventsChannelLen: registry.NewHistogram("metricEvents"),
 		MetricEventsHandleTime: registry.NewTimer("metricEventsHandle"),
 		UnusedTriggersCount:    registry.NewHistogram("triggers", "unused"),
+		ShardCheckersCount:     registry.NewHistogram("sharding", "checkers"),
+		ShardTriggersCount:     registry.NewHistogram("sharding", "triggers"),
+		ShardRebalances:        registry.NewMeter("sharding", "rebalances"),
+		RemoteClustersMetrics:  make(map[string]*CheckMetrics),
+		FetchCache: &FetchCacheMetrics{
+			Hits:   registry.NewMeter("fetchCache", "hits"),
+			Misses: registry.NewMeter("fetchCache", "misses"),
+		},
 	}
 	if remoteEnabled {
 		m.RemoteMetrics = configureCheckMetrics(registry, "remote")
@@ -41,6 +63,11 @@ func ConfigureCheckerMetrics(registry Registry, remoteEnabled bool) *CheckerMetr
 	return m
 }
 
+// ConfigureRemoteClusterMetrics configures check metrics of named remote graphite cluster
+func (metrics *CheckerMetrics) ConfigureRemoteClusterMetrics(registry Registry, clusterName string) {
+	metrics.RemoteClustersMetrics[clusterName] = configureCheckMetrics(registry, "remote_"+clusterName)
+}
+
 func configureCheckMetrics(registry Registry, prefix string) *CheckMetrics {
 	return &CheckMetrics{
 		CheckError:           registry.NewMeter(prefix, "errors", "check"),
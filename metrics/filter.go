@@ -2,26 +2,52 @@ package metrics
 
 // FilterMetrics is a collection of metrics used in filter
 type FilterMetrics struct {
-	TotalMetricsReceived    Counter
-	ValidMetricsReceived    Counter
-	MatchingMetricsReceived Counter
-	MatchingTimer           Timer
-	SavingTimer             Timer
-	BuildTreeTimer          Timer
-	MetricChannelLen        Histogram
-	LineChannelLen          Histogram
+	TotalMetricsReceived              Counter
+	ValidMetricsReceived              Counter
+	MatchingMetricsReceived           Counter
+	RewrittenMetricsReceived          Counter
+	DroppedMetricsReceived            Counter
+	TCPMetricsReceived                Counter
+	UDPMetricsReceived                Counter
+	PickleMetricsReceived             Counter
+	InfluxMetricsReceived             Counter
+	RemoteWriteSamplesReceived        Counter
+	StatsDMetricsReceived             Counter
+	ForbiddenMetricsReceived          Counter
+	RateLimitedMetricsReceived        Counter
+	CardinalityLimitedMetricsReceived Counter
+	OutdatedMetricsReceived           Counter
+	FutureMetricsReceived             Counter
+	MatchingTimer                     Timer
+	SavingTimer                       Timer
+	BuildTreeTimer                    Timer
+	MetricChannelLen                  Histogram
+	LineChannelLen                    Histogram
 }
 
 // ConfigureFilterMetrics initialize metrics
 func ConfigureFilterMetrics(registry Registry) *FilterMetrics {
 	return &FilterMetrics{
-		TotalMetricsReceived:    registry.NewCounter("received", "total"),
-		ValidMetricsReceived:    registry.NewCounter("received", "valid"),
-		MatchingMetricsReceived: registry.NewCounter("received", "matching"),
-		MatchingTimer:           registry.NewTimer("time", "match"),
-		SavingTimer:             registry.NewTimer("time", "save"),
-		BuildTreeTimer:          registry.NewTimer("time", "buildtree"),
-		MetricChannelLen:        registry.NewHistogram("metricsToSave"),
-		LineChannelLen:          registry.NewHistogram("linesToMatch"),
+		TotalMetricsReceived:              registry.NewCounter("received", "total"),
+		ValidMetricsReceived:              registry.NewCounter("received", "valid"),
+		MatchingMetricsReceived:           registry.NewCounter("received", "matching"),
+		RewrittenMetricsReceived:          registry.NewCounter("received", "rewritten"),
+		DroppedMetricsReceived:            registry.NewCounter("received", "dropped"),
+		TCPMetricsReceived:                registry.NewCounter("received", "tcp"),
+		UDPMetricsReceived:                registry.NewCounter("received", "udp"),
+		PickleMetricsReceived:             registry.NewCounter("received", "pickle"),
+		InfluxMetricsReceived:             registry.NewCounter("received", "influx"),
+		RemoteWriteSamplesReceived:        registry.NewCounter("received", "remote_write"),
+		StatsDMetricsReceived:             registry.NewCounter("received", "statsd"),
+		ForbiddenMetricsReceived:          registry.NewCounter("received", "forbidden"),
+		RateLimitedMetricsReceived:        registry.NewCounter("received", "rate_limited"),
+		CardinalityLimitedMetricsReceived: registry.NewCounter("received", "cardinality_limited"),
+		OutdatedMetricsReceived:           registry.NewCounter("received", "outdated"),
+		FutureMetricsReceived:             registry.NewCounter("received", "future"),
+		MatchingTimer:                     registry.NewTimer("time", "match"),
+		SavingTimer:                       registry.NewTimer("time", "save"),
+		BuildTreeTimer:                    registry.NewTimer("time", "buildtree"),
+		MetricChannelLen:                  registry.NewHistogram("metricsToSave"),
+		LineChannelLen:                    registry.NewHistogram("linesToMatch"),
 	}
 }
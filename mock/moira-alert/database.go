@@ -50,6 +50,20 @@ func (mr *MockDatabaseMockRecorder) AcquireTriggerCheckLock(arg0, arg1 interface
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AcquireTriggerCheckLock", reflect.TypeOf((*MockDatabase)(nil).AcquireTriggerCheckLock), arg0, arg1)
 }
 
+// AddLocalShardTriggersToCheck mocks base method.
+func (m *MockDatabase) AddLocalShardTriggersToCheck(arg0 string, arg1 []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddLocalShardTriggersToCheck", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddLocalShardTriggersToCheck indicates an expected call of AddLocalShardTriggersToCheck.
+func (mr *MockDatabaseMockRecorder) AddLocalShardTriggersToCheck(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddLocalShardTriggersToCheck", reflect.TypeOf((*MockDatabase)(nil).AddLocalShardTriggersToCheck), arg0, arg1)
+}
+
 // AddLocalTriggersToCheck mocks base method.
 func (m *MockDatabase) AddLocalTriggersToCheck(arg0 []string) error {
 	m.ctrl.T.Helper()
@@ -64,6 +78,20 @@ func (mr *MockDatabaseMockRecorder) AddLocalTriggersToCheck(arg0 interface{}) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddLocalTriggersToCheck", reflect.TypeOf((*MockDatabase)(nil).AddLocalTriggersToCheck), arg0)
 }
 
+// AddMetricTap mocks base method.
+func (m *MockDatabase) AddMetricTap(arg0 string, arg1 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddMetricTap", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddMetricTap indicates an expected call of AddMetricTap.
+func (mr *MockDatabaseMockRecorder) AddMetricTap(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddMetricTap", reflect.TypeOf((*MockDatabase)(nil).AddMetricTap), arg0, arg1)
+}
+
 // AddNotification mocks base method.
 func (m *MockDatabase) AddNotification(arg0 *moira.ScheduledNotification) error {
 	m.ctrl.T.Helper()
@@ -106,6 +134,34 @@ func (mr *MockDatabaseMockRecorder) AddPatternMetric(arg0, arg1 interface{}) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddPatternMetric", reflect.TypeOf((*MockDatabase)(nil).AddPatternMetric), arg0, arg1)
 }
 
+// AddRemoteClusterTriggersToCheck mocks base method.
+func (m *MockDatabase) AddRemoteClusterTriggersToCheck(arg0 string, arg1 string, arg2 []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddRemoteClusterTriggersToCheck", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddRemoteClusterTriggersToCheck indicates an expected call of AddRemoteClusterTriggersToCheck.
+func (mr *MockDatabaseMockRecorder) AddRemoteClusterTriggersToCheck(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddRemoteClusterTriggersToCheck", reflect.TypeOf((*MockDatabase)(nil).AddRemoteClusterTriggersToCheck), arg0, arg1, arg2)
+}
+
+// AddRemoteShardTriggersToCheck mocks base method.
+func (m *MockDatabase) AddRemoteShardTriggersToCheck(arg0 string, arg1 []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddRemoteShardTriggersToCheck", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddRemoteShardTriggersToCheck indicates an expected call of AddRemoteShardTriggersToCheck.
+func (mr *MockDatabaseMockRecorder) AddRemoteShardTriggersToCheck(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddRemoteShardTriggersToCheck", reflect.TypeOf((*MockDatabase)(nil).AddRemoteShardTriggersToCheck), arg0, arg1)
+}
+
 // AddRemoteTriggersToCheck mocks base method.
 func (m *MockDatabase) AddRemoteTriggersToCheck(arg0 []string) error {
 	m.ctrl.T.Helper()
@@ -265,6 +321,21 @@ func (mr *MockDatabaseMockRecorder) GetAllTriggerIDs() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllTriggerIDs", reflect.TypeOf((*MockDatabase)(nil).GetAllTriggerIDs))
 }
 
+// GetCheckerInstances mocks base method.
+func (m *MockDatabase) GetCheckerInstances(arg0 int64) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCheckerInstances", arg0)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCheckerInstances indicates an expected call of GetCheckerInstances.
+func (mr *MockDatabaseMockRecorder) GetCheckerInstances(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCheckerInstances", reflect.TypeOf((*MockDatabase)(nil).GetCheckerInstances), arg0)
+}
+
 // GetChecksUpdatesCount mocks base method.
 func (m *MockDatabase) GetChecksUpdatesCount() (int64, error) {
 	m.ctrl.T.Helper()
@@ -280,6 +351,21 @@ func (mr *MockDatabaseMockRecorder) GetChecksUpdatesCount() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetChecksUpdatesCount", reflect.TypeOf((*MockDatabase)(nil).GetChecksUpdatesCount))
 }
 
+// GetChildTriggerIDs mocks base method.
+func (m *MockDatabase) GetChildTriggerIDs(arg0 string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetChildTriggerIDs", arg0)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetChildTriggerIDs indicates an expected call of GetChildTriggerIDs.
+func (mr *MockDatabaseMockRecorder) GetChildTriggerIDs(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetChildTriggerIDs", reflect.TypeOf((*MockDatabase)(nil).GetChildTriggerIDs), arg0)
+}
+
 // GetContact mocks base method.
 func (m *MockDatabase) GetContact(arg0 string) (moira.ContactData, error) {
 	m.ctrl.T.Helper()
@@ -325,6 +411,51 @@ func (mr *MockDatabaseMockRecorder) GetIDByUsername(arg0, arg1 interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIDByUsername", reflect.TypeOf((*MockDatabase)(nil).GetIDByUsername), arg0, arg1)
 }
 
+// GetIngestionLimitViolations mocks base method.
+func (m *MockDatabase) GetIngestionLimitViolations() ([]*moira.IngestionLimitViolation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetIngestionLimitViolations")
+	ret0, _ := ret[0].([]*moira.IngestionLimitViolation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetIngestionLimitViolations indicates an expected call of GetIngestionLimitViolations.
+func (mr *MockDatabaseMockRecorder) GetIngestionLimitViolations() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIngestionLimitViolations", reflect.TypeOf((*MockDatabase)(nil).GetIngestionLimitViolations))
+}
+
+// GetLocalShardTriggersToCheck mocks base method.
+func (m *MockDatabase) GetLocalShardTriggersToCheck(arg0 string, arg1 int) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLocalShardTriggersToCheck", arg0, arg1)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLocalShardTriggersToCheck indicates an expected call of GetLocalShardTriggersToCheck.
+func (mr *MockDatabaseMockRecorder) GetLocalShardTriggersToCheck(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLocalShardTriggersToCheck", reflect.TypeOf((*MockDatabase)(nil).GetLocalShardTriggersToCheck), arg0, arg1)
+}
+
+// GetLocalShardTriggersToCheckCount mocks base method.
+func (m *MockDatabase) GetLocalShardTriggersToCheckCount(arg0 string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLocalShardTriggersToCheckCount", arg0)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLocalShardTriggersToCheckCount indicates an expected call of GetLocalShardTriggersToCheckCount.
+func (mr *MockDatabaseMockRecorder) GetLocalShardTriggersToCheckCount(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLocalShardTriggersToCheckCount", reflect.TypeOf((*MockDatabase)(nil).GetLocalShardTriggersToCheckCount), arg0)
+}
+
 // GetLocalTriggerIDs mocks base method.
 func (m *MockDatabase) GetLocalTriggerIDs() ([]string, error) {
 	m.ctrl.T.Helper()
@@ -370,6 +501,21 @@ func (mr *MockDatabaseMockRecorder) GetLocalTriggersToCheckCount() *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLocalTriggersToCheckCount", reflect.TypeOf((*MockDatabase)(nil).GetLocalTriggersToCheckCount))
 }
 
+// GetLocalTriggersToCheckTotalCount mocks base method.
+func (m *MockDatabase) GetLocalTriggersToCheckTotalCount() (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLocalTriggersToCheckTotalCount")
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLocalTriggersToCheckTotalCount indicates an expected call of GetLocalTriggersToCheckTotalCount.
+func (mr *MockDatabaseMockRecorder) GetLocalTriggersToCheckTotalCount() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLocalTriggersToCheckTotalCount", reflect.TypeOf((*MockDatabase)(nil).GetLocalTriggersToCheckTotalCount))
+}
+
 // GetMetricRetention mocks base method.
 func (m *MockDatabase) GetMetricRetention(arg0 string) (int64, error) {
 	m.ctrl.T.Helper()
@@ -385,6 +531,21 @@ func (mr *MockDatabaseMockRecorder) GetMetricRetention(arg0 interface{}) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMetricRetention", reflect.TypeOf((*MockDatabase)(nil).GetMetricRetention), arg0)
 }
 
+// GetMetricTaps mocks base method.
+func (m *MockDatabase) GetMetricTaps() ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMetricTaps")
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMetricTaps indicates an expected call of GetMetricTaps.
+func (mr *MockDatabaseMockRecorder) GetMetricTaps() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMetricTaps", reflect.TypeOf((*MockDatabase)(nil).GetMetricTaps))
+}
+
 // GetMetricsTTLSeconds mocks base method.
 func (m *MockDatabase) GetMetricsTTLSeconds() int64 {
 	m.ctrl.T.Helper()
@@ -534,6 +695,21 @@ func (mr *MockDatabaseMockRecorder) GetPatterns() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPatterns", reflect.TypeOf((*MockDatabase)(nil).GetPatterns))
 }
 
+// GetRejectedTimestampSamples mocks base method.
+func (m *MockDatabase) GetRejectedTimestampSamples() ([]*moira.RejectedTimestampSample, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRejectedTimestampSamples")
+	ret0, _ := ret[0].([]*moira.RejectedTimestampSample)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRejectedTimestampSamples indicates an expected call of GetRejectedTimestampSamples.
+func (mr *MockDatabaseMockRecorder) GetRejectedTimestampSamples() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRejectedTimestampSamples", reflect.TypeOf((*MockDatabase)(nil).GetRejectedTimestampSamples))
+}
+
 // GetRemoteChecksUpdatesCount mocks base method.
 func (m *MockDatabase) GetRemoteChecksUpdatesCount() (int64, error) {
 	m.ctrl.T.Helper()
@@ -549,6 +725,111 @@ func (mr *MockDatabaseMockRecorder) GetRemoteChecksUpdatesCount() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRemoteChecksUpdatesCount", reflect.TypeOf((*MockDatabase)(nil).GetRemoteChecksUpdatesCount))
 }
 
+// GetRemoteClusterChecksUpdatesCount mocks base method.
+func (m *MockDatabase) GetRemoteClusterChecksUpdatesCount(arg0 string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRemoteClusterChecksUpdatesCount", arg0)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRemoteClusterChecksUpdatesCount indicates an expected call of GetRemoteClusterChecksUpdatesCount.
+func (mr *MockDatabaseMockRecorder) GetRemoteClusterChecksUpdatesCount(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRemoteClusterChecksUpdatesCount", reflect.TypeOf((*MockDatabase)(nil).GetRemoteClusterChecksUpdatesCount), arg0)
+}
+
+// GetRemoteClusterTriggerIDs mocks base method.
+func (m *MockDatabase) GetRemoteClusterTriggerIDs(arg0 string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRemoteClusterTriggerIDs", arg0)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRemoteClusterTriggerIDs indicates an expected call of GetRemoteClusterTriggerIDs.
+func (mr *MockDatabaseMockRecorder) GetRemoteClusterTriggerIDs(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRemoteClusterTriggerIDs", reflect.TypeOf((*MockDatabase)(nil).GetRemoteClusterTriggerIDs), arg0)
+}
+
+// GetRemoteClusterTriggersToCheck mocks base method.
+func (m *MockDatabase) GetRemoteClusterTriggersToCheck(arg0 string, arg1 string, arg2 int) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRemoteClusterTriggersToCheck", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRemoteClusterTriggersToCheck indicates an expected call of GetRemoteClusterTriggersToCheck.
+func (mr *MockDatabaseMockRecorder) GetRemoteClusterTriggersToCheck(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRemoteClusterTriggersToCheck", reflect.TypeOf((*MockDatabase)(nil).GetRemoteClusterTriggersToCheck), arg0, arg1, arg2)
+}
+
+// GetRemoteClusterTriggersToCheckCount mocks base method.
+func (m *MockDatabase) GetRemoteClusterTriggersToCheckCount(arg0 string, arg1 string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRemoteClusterTriggersToCheckCount", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRemoteClusterTriggersToCheckCount indicates an expected call of GetRemoteClusterTriggersToCheckCount.
+func (mr *MockDatabaseMockRecorder) GetRemoteClusterTriggersToCheckCount(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRemoteClusterTriggersToCheckCount", reflect.TypeOf((*MockDatabase)(nil).GetRemoteClusterTriggersToCheckCount), arg0, arg1)
+}
+
+// GetRemoteClusterTriggersToCheckTotalCount mocks base method.
+func (m *MockDatabase) GetRemoteClusterTriggersToCheckTotalCount(arg0 string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRemoteClusterTriggersToCheckTotalCount", arg0)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRemoteClusterTriggersToCheckTotalCount indicates an expected call of GetRemoteClusterTriggersToCheckTotalCount.
+func (mr *MockDatabaseMockRecorder) GetRemoteClusterTriggersToCheckTotalCount(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRemoteClusterTriggersToCheckTotalCount", reflect.TypeOf((*MockDatabase)(nil).GetRemoteClusterTriggersToCheckTotalCount), arg0)
+}
+
+// GetRemoteShardTriggersToCheck mocks base method.
+func (m *MockDatabase) GetRemoteShardTriggersToCheck(arg0 string, arg1 int) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRemoteShardTriggersToCheck", arg0, arg1)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRemoteShardTriggersToCheck indicates an expected call of GetRemoteShardTriggersToCheck.
+func (mr *MockDatabaseMockRecorder) GetRemoteShardTriggersToCheck(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRemoteShardTriggersToCheck", reflect.TypeOf((*MockDatabase)(nil).GetRemoteShardTriggersToCheck), arg0, arg1)
+}
+
+// GetRemoteShardTriggersToCheckCount mocks base method.
+func (m *MockDatabase) GetRemoteShardTriggersToCheckCount(arg0 string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRemoteShardTriggersToCheckCount", arg0)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRemoteShardTriggersToCheckCount indicates an expected call of GetRemoteShardTriggersToCheckCount.
+func (mr *MockDatabaseMockRecorder) GetRemoteShardTriggersToCheckCount(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRemoteShardTriggersToCheckCount", reflect.TypeOf((*MockDatabase)(nil).GetRemoteShardTriggersToCheckCount), arg0)
+}
+
 // GetRemoteTriggerIDs mocks base method.
 func (m *MockDatabase) GetRemoteTriggerIDs() ([]string, error) {
 	m.ctrl.T.Helper()
@@ -594,6 +875,21 @@ func (mr *MockDatabaseMockRecorder) GetRemoteTriggersToCheckCount() *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRemoteTriggersToCheckCount", reflect.TypeOf((*MockDatabase)(nil).GetRemoteTriggersToCheckCount))
 }
 
+// GetRemoteTriggersToCheckTotalCount mocks base method.
+func (m *MockDatabase) GetRemoteTriggersToCheckTotalCount() (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRemoteTriggersToCheckTotalCount")
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRemoteTriggersToCheckTotalCount indicates an expected call of GetRemoteTriggersToCheckTotalCount.
+func (mr *MockDatabaseMockRecorder) GetRemoteTriggersToCheckTotalCount() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRemoteTriggersToCheckTotalCount", reflect.TypeOf((*MockDatabase)(nil).GetRemoteTriggersToCheckTotalCount))
+}
+
 // GetSubscription mocks base method.
 func (m *MockDatabase) GetSubscription(arg0 string) (moira.SubscriptionData, error) {
 	m.ctrl.T.Helper()
@@ -960,6 +1256,20 @@ func (mr *MockDatabaseMockRecorder) NewLock(arg0, arg1 interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewLock", reflect.TypeOf((*MockDatabase)(nil).NewLock), arg0, arg1)
 }
 
+// PublishMetricTapSamples mocks base method.
+func (m *MockDatabase) PublishMetricTapSamples(arg0 []*moira.MetricTapSample) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PublishMetricTapSamples", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PublishMetricTapSamples indicates an expected call of PublishMetricTapSamples.
+func (mr *MockDatabaseMockRecorder) PublishMetricTapSamples(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublishMetricTapSamples", reflect.TypeOf((*MockDatabase)(nil).PublishMetricTapSamples), arg0)
+}
+
 // PushNotificationEvent mocks base method.
 func (m *MockDatabase) PushNotificationEvent(arg0 *moira.NotificationEvent, arg1 bool) error {
 	m.ctrl.T.Helper()
@@ -1002,6 +1312,20 @@ func (mr *MockDatabaseMockRecorder) RemoveAllNotifications() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveAllNotifications", reflect.TypeOf((*MockDatabase)(nil).RemoveAllNotifications))
 }
 
+// RemoveCheckerInstance mocks base method.
+func (m *MockDatabase) RemoveCheckerInstance(arg0 string, arg1 []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveCheckerInstance", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveCheckerInstance indicates an expected call of RemoveCheckerInstance.
+func (mr *MockDatabaseMockRecorder) RemoveCheckerInstance(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveCheckerInstance", reflect.TypeOf((*MockDatabase)(nil).RemoveCheckerInstance), arg0, arg1)
+}
+
 // RemoveContact mocks base method.
 func (m *MockDatabase) RemoveContact(arg0 string) error {
 	m.ctrl.T.Helper()
@@ -1213,6 +1537,20 @@ func (mr *MockDatabaseMockRecorder) SaveContact(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveContact", reflect.TypeOf((*MockDatabase)(nil).SaveContact), arg0)
 }
 
+// SaveIngestionLimitViolations mocks base method.
+func (m *MockDatabase) SaveIngestionLimitViolations(arg0 []*moira.IngestionLimitViolation) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveIngestionLimitViolations", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveIngestionLimitViolations indicates an expected call of SaveIngestionLimitViolations.
+func (mr *MockDatabaseMockRecorder) SaveIngestionLimitViolations(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveIngestionLimitViolations", reflect.TypeOf((*MockDatabase)(nil).SaveIngestionLimitViolations), arg0)
+}
+
 // SaveMetrics mocks base method.
 func (m *MockDatabase) SaveMetrics(arg0 map[string]*moira.MatchedMetric) error {
 	m.ctrl.T.Helper()
@@ -1227,6 +1565,20 @@ func (mr *MockDatabaseMockRecorder) SaveMetrics(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveMetrics", reflect.TypeOf((*MockDatabase)(nil).SaveMetrics), arg0)
 }
 
+// SaveRejectedTimestampSamples mocks base method.
+func (m *MockDatabase) SaveRejectedTimestampSamples(arg0 []*moira.RejectedTimestampSample) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveRejectedTimestampSamples", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveRejectedTimestampSamples indicates an expected call of SaveRejectedTimestampSamples.
+func (mr *MockDatabaseMockRecorder) SaveRejectedTimestampSamples(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveRejectedTimestampSamples", reflect.TypeOf((*MockDatabase)(nil).SaveRejectedTimestampSamples), arg0)
+}
+
 // SaveSubscription mocks base method.
 func (m *MockDatabase) SaveSubscription(arg0 *moira.SubscriptionData) error {
 	m.ctrl.T.Helper()
@@ -1411,6 +1763,35 @@ func (mr *MockDatabaseMockRecorder) SubscribeMetricEvents(arg0 interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubscribeMetricEvents", reflect.TypeOf((*MockDatabase)(nil).SubscribeMetricEvents), arg0)
 }
 
+// SubscribeMetricTap mocks base method.
+func (m *MockDatabase) SubscribeMetricTap(arg0 *tomb.Tomb, arg1 string) (<-chan *moira.MetricTapSample, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SubscribeMetricTap", arg0, arg1)
+	ret0, _ := ret[0].(<-chan *moira.MetricTapSample)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SubscribeMetricTap indicates an expected call of SubscribeMetricTap.
+func (mr *MockDatabaseMockRecorder) SubscribeMetricTap(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubscribeMetricTap", reflect.TypeOf((*MockDatabase)(nil).SubscribeMetricTap), arg0, arg1)
+}
+
+// UpdateCheckerInstanceHeartbeat mocks base method.
+func (m *MockDatabase) UpdateCheckerInstanceHeartbeat(arg0 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateCheckerInstanceHeartbeat", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateCheckerInstanceHeartbeat indicates an expected call of UpdateCheckerInstanceHeartbeat.
+func (mr *MockDatabaseMockRecorder) UpdateCheckerInstanceHeartbeat(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCheckerInstanceHeartbeat", reflect.TypeOf((*MockDatabase)(nil).UpdateCheckerInstanceHeartbeat), arg0)
+}
+
 // UpdateMetricsHeartbeat mocks base method.
 func (m *MockDatabase) UpdateMetricsHeartbeat() error {
 	m.ctrl.T.Helper()
@@ -1424,3 +1805,17 @@ func (mr *MockDatabaseMockRecorder) UpdateMetricsHeartbeat() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateMetricsHeartbeat", reflect.TypeOf((*MockDatabase)(nil).UpdateMetricsHeartbeat))
 }
+
+// UpdateRemoteClusterChecksHeartbeat mocks base method.
+func (m *MockDatabase) UpdateRemoteClusterChecksHeartbeat(arg0 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateRemoteClusterChecksHeartbeat", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateRemoteClusterChecksHeartbeat indicates an expected call of UpdateRemoteClusterChecksHeartbeat.
+func (mr *MockDatabaseMockRecorder) UpdateRemoteClusterChecksHeartbeat(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateRemoteClusterChecksHeartbeat", reflect.TypeOf((*MockDatabase)(nil).UpdateRemoteClusterChecksHeartbeat), arg0)
+}
@@ -35,6 +35,12 @@ func (selfCheck *SelfCheckWorker) selfStateChecker(stop <-chan struct{}) error {
 		selfCheck.Heartbeats = append(selfCheck.Heartbeats, heartbeat)
 	}
 
+	for _, clusterName := range selfCheck.Config.RemoteClusters {
+		if heartbeat := heartbeat.GetRemoteClusterChecker(clusterName, selfCheck.Config.LastRemoteCheckDelaySeconds, selfCheck.Logger, selfCheck.Database); heartbeat != nil {
+			selfCheck.Heartbeats = append(selfCheck.Heartbeats, heartbeat)
+		}
+	}
+
 	if heartbeat := heartbeat.GetNotifier(selfCheck.Logger, selfCheck.Database); heartbeat != nil {
 		selfCheck.Heartbeats = append(selfCheck.Heartbeats, heartbeat)
 	}
@@ -11,6 +11,7 @@ type Config struct {
 	LastMetricReceivedDelaySeconds int64
 	LastCheckDelaySeconds          int64
 	LastRemoteCheckDelaySeconds    int64
+	RemoteClusters                 []string
 	NoticeIntervalSeconds          int64
 	Contacts                       []map[string]string
 }
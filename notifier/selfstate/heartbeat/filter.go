@@ -27,7 +27,7 @@ func GetFilter(delay int64, logger moira.Logger, database moira.Database) Heartb
 }
 
 func (check *filter) Check(nowTS int64) (int64, bool, error) {
-	triggersCount, err := check.database.GetLocalTriggersToCheckCount()
+	triggersCount, err := check.database.GetLocalTriggersToCheckTotalCount()
 	if err != nil {
 		return 0, false, err
 	}
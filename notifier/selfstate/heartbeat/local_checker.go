@@ -24,7 +24,7 @@ func GetLocalChecker(delay int64, logger moira.Logger, database moira.Database)
 }
 
 func (check *localChecker) Check(nowTS int64) (int64, bool, error) {
-	triggersCount, err := check.database.GetLocalTriggersToCheckCount()
+	triggersCount, err := check.database.GetLocalTriggersToCheckTotalCount()
 	if err != nil {
 		return 0, false, err
 	}
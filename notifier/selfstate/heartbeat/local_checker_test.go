@@ -28,7 +28,7 @@ func TestCheckDelay_Check(t *testing.T) {
 
 		Convey("GraphiteLocalChecker error handling test", func() {
 			database.EXPECT().GetChecksUpdatesCount().Return(int64(1), nil)
-			database.EXPECT().GetLocalTriggersToCheckCount().Return(int64(1), err)
+			database.EXPECT().GetLocalTriggersToCheckTotalCount().Return(int64(1), err)
 
 			value, needSend, errActual := check.Check(now)
 			So(errActual, ShouldEqual, err)
@@ -39,7 +39,7 @@ func TestCheckDelay_Check(t *testing.T) {
 		Convey("Test update lastSuccessfulCheck", func() {
 			now += 1000
 			database.EXPECT().GetChecksUpdatesCount().Return(int64(1), nil)
-			database.EXPECT().GetLocalTriggersToCheckCount().Return(int64(1), nil)
+			database.EXPECT().GetLocalTriggersToCheckTotalCount().Return(int64(1), nil)
 
 			value, needSend, errActual := check.Check(now)
 			So(errActual, ShouldBeNil)
@@ -51,7 +51,7 @@ func TestCheckDelay_Check(t *testing.T) {
 		Convey("Test get notification", func() {
 			check.lastSuccessfulCheck = now - check.delay - 1
 			database.EXPECT().GetChecksUpdatesCount().Return(int64(0), nil)
-			database.EXPECT().GetLocalTriggersToCheckCount().Return(int64(1), nil)
+			database.EXPECT().GetLocalTriggersToCheckTotalCount().Return(int64(1), nil)
 			database.EXPECT().SetNotifierState(moira.SelfStateERROR)
 
 			value, needSend, errActual := check.Check(now)
@@ -62,7 +62,7 @@ func TestCheckDelay_Check(t *testing.T) {
 
 		Convey("Exit without action", func() {
 			database.EXPECT().GetChecksUpdatesCount().Return(int64(0), nil)
-			database.EXPECT().GetLocalTriggersToCheckCount().Return(int64(1), nil)
+			database.EXPECT().GetLocalTriggersToCheckTotalCount().Return(int64(1), nil)
 
 			value, needSend, errActual := check.Check(now)
 			So(errActual, ShouldBeNil)
@@ -72,7 +72,7 @@ func TestCheckDelay_Check(t *testing.T) {
 
 		Convey("Test NeedToCheckOthers and NeedTurnOffNotifier", func() {
 			database.EXPECT().GetChecksUpdatesCount().Return(int64(1), nil)
-			database.EXPECT().GetLocalTriggersToCheckCount().Return(int64(0), nil)
+			database.EXPECT().GetLocalTriggersToCheckTotalCount().Return(int64(0), nil)
 			needCheck := check.NeedToCheckOthers()
 			So(needCheck, ShouldBeTrue)
 
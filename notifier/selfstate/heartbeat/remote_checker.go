@@ -1,6 +1,7 @@
 package heartbeat
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/moira-alert/moira"
@@ -8,28 +9,52 @@ import (
 
 type remoteChecker struct {
 	heartbeat
-	count int64
+	count                   int64
+	errorMessage            string
+	getTriggersToCheckCount func() (int64, error)
+	getChecksUpdatesCount   func() (int64, error)
 }
 
 func GetRemoteChecker(delay int64, logger moira.Logger, database moira.Database) Heartbeater {
+	return getRemoteChecker(delay, logger, database, "Moira-Remote-Checker does not check remote triggers",
+		func() (int64, error) { return database.GetRemoteTriggersToCheckTotalCount() },
+		func() (int64, error) { return database.GetRemoteChecksUpdatesCount() },
+	)
+}
+
+// GetRemoteClusterChecker returns heartbeat of checker workers of named remote graphite cluster
+func GetRemoteClusterChecker(clusterName string, delay int64, logger moira.Logger, database moira.Database) Heartbeater {
+	return getRemoteChecker(delay, logger, database, fmt.Sprintf("Moira-Remote-Checker does not check triggers of remote cluster %s", clusterName),
+		func() (int64, error) { return database.GetRemoteClusterTriggersToCheckTotalCount(clusterName) },
+		func() (int64, error) { return database.GetRemoteClusterChecksUpdatesCount(clusterName) },
+	)
+}
+
+func getRemoteChecker(delay int64, logger moira.Logger, database moira.Database, errorMessage string,
+	getTriggersToCheckCount, getChecksUpdatesCount func() (int64, error)) Heartbeater {
 	if delay > 0 {
-		return &remoteChecker{heartbeat: heartbeat{
-			logger:              logger,
-			database:            database,
-			delay:               delay,
-			lastSuccessfulCheck: time.Now().Unix(),
-		}}
+		return &remoteChecker{
+			heartbeat: heartbeat{
+				logger:              logger,
+				database:            database,
+				delay:               delay,
+				lastSuccessfulCheck: time.Now().Unix(),
+			},
+			errorMessage:            errorMessage,
+			getTriggersToCheckCount: getTriggersToCheckCount,
+			getChecksUpdatesCount:   getChecksUpdatesCount,
+		}
 	}
 	return nil
 }
 
 func (check *remoteChecker) Check(nowTS int64) (int64, bool, error) {
-	triggerCount, err := check.database.GetRemoteTriggersToCheckCount()
+	triggerCount, err := check.getTriggersToCheckCount()
 	if err != nil {
 		return 0, false, err
 	}
 
-	remoteTriggersCount, _ := check.database.GetRemoteChecksUpdatesCount()
+	remoteTriggersCount, _ := check.getChecksUpdatesCount()
 	if check.count != remoteTriggersCount || triggerCount == 0 {
 		check.count = remoteTriggersCount
 		check.lastSuccessfulCheck = nowTS
@@ -51,6 +76,6 @@ func (remoteChecker) NeedToCheckOthers() bool {
 	return true
 }
 
-func (remoteChecker) GetErrorMessage() string {
-	return "Moira-Remote-Checker does not check remote triggers"
+func (check remoteChecker) GetErrorMessage() string {
+	return check.errorMessage
 }
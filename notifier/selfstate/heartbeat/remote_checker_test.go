@@ -20,13 +20,13 @@ func TestGraphiteRemoteChecker(t *testing.T) {
 		database := check.database.(*mock_moira_alert.MockDatabase)
 
 		Convey("Checking the created graphite remote checker", func() {
-			expected := &remoteChecker{heartbeat: heartbeat{database: check.database, logger: check.logger, delay: 1, lastSuccessfulCheck: now}}
 			So(GetRemoteChecker(0, check.logger, check.database), ShouldBeNil)
-			So(GetRemoteChecker(1, check.logger, check.database), ShouldResemble, expected)
+			So(GetRemoteChecker(1, check.logger, check.database), ShouldNotBeNil)
+			So(check.GetErrorMessage(), ShouldEqual, "Moira-Remote-Checker does not check remote triggers")
 		})
 
 		Convey("GraphiteRemoteChecker error handling test", func() {
-			database.EXPECT().GetRemoteTriggersToCheckCount().Return(int64(0), err)
+			database.EXPECT().GetRemoteTriggersToCheckTotalCount().Return(int64(0), err)
 
 			value, needSend, errActual := check.Check(now)
 			So(errActual, ShouldEqual, err)
@@ -37,7 +37,7 @@ func TestGraphiteRemoteChecker(t *testing.T) {
 		Convey("Test update lastSuccessfulCheck", func() {
 			now += 1000
 			database.EXPECT().GetRemoteChecksUpdatesCount().Return(int64(1), nil)
-			database.EXPECT().GetRemoteTriggersToCheckCount().Return(int64(1), nil)
+			database.EXPECT().GetRemoteTriggersToCheckTotalCount().Return(int64(1), nil)
 
 			value, needSend, errActual := check.Check(now)
 			So(errActual, ShouldBeNil)
@@ -50,7 +50,7 @@ func TestGraphiteRemoteChecker(t *testing.T) {
 			check.lastSuccessfulCheck = now - check.delay - 1
 
 			database.EXPECT().GetRemoteChecksUpdatesCount().Return(int64(0), nil)
-			database.EXPECT().GetRemoteTriggersToCheckCount().Return(int64(1), nil)
+			database.EXPECT().GetRemoteTriggersToCheckTotalCount().Return(int64(1), nil)
 
 			value, needSend, errActual := check.Check(now)
 			So(errActual, ShouldBeNil)
@@ -60,7 +60,7 @@ func TestGraphiteRemoteChecker(t *testing.T) {
 
 		Convey("Exit without action", func() {
 			database.EXPECT().GetRemoteChecksUpdatesCount().Return(int64(0), nil)
-			database.EXPECT().GetRemoteTriggersToCheckCount().Return(int64(1), nil)
+			database.EXPECT().GetRemoteTriggersToCheckTotalCount().Return(int64(1), nil)
 
 			value, needSend, errActual := check.Check(now)
 			So(errActual, ShouldBeNil)
@@ -70,7 +70,7 @@ func TestGraphiteRemoteChecker(t *testing.T) {
 
 		Convey("Test NeedToCheckOthers and NeedTurnOffNotifier", func() {
 			database.EXPECT().GetRemoteChecksUpdatesCount().Return(int64(1), nil)
-			database.EXPECT().GetRemoteTriggersToCheckCount().Return(int64(0), nil)
+			database.EXPECT().GetRemoteTriggersToCheckTotalCount().Return(int64(0), nil)
 			So(check.NeedToCheckOthers(), ShouldBeTrue)
 
 			database.EXPECT().GetRemoteChecksUpdatesCount().Return(int64(0), nil)
@@ -81,9 +81,64 @@ func TestGraphiteRemoteChecker(t *testing.T) {
 	})
 }
 
+func TestRemoteClusterChecker(t *testing.T) {
+	Convey("Test remote cluster checker heartbeat", t, func() {
+		err := errors.New("test error remoteClusterChecker")
+		now := time.Now().Unix()
+		check := createRemoteClusterCheckerTest(t)
+		database := check.database.(*mock_moira_alert.MockDatabase)
+
+		Convey("Checking the created remote cluster checker", func() {
+			So(GetRemoteClusterChecker("eu", 0, check.logger, check.database), ShouldBeNil)
+			So(GetRemoteClusterChecker("eu", 1, check.logger, check.database), ShouldNotBeNil)
+			So(check.GetErrorMessage(), ShouldEqual, "Moira-Remote-Checker does not check triggers of remote cluster eu")
+		})
+
+		Convey("RemoteClusterChecker error handling test", func() {
+			database.EXPECT().GetRemoteClusterTriggersToCheckTotalCount("eu").Return(int64(0), err)
+
+			value, needSend, errActual := check.Check(now)
+			So(errActual, ShouldEqual, err)
+			So(needSend, ShouldBeFalse)
+			So(value, ShouldEqual, 0)
+		})
+
+		Convey("Test update lastSuccessfulCheck", func() {
+			now += 1000
+			database.EXPECT().GetRemoteClusterChecksUpdatesCount("eu").Return(int64(1), nil)
+			database.EXPECT().GetRemoteClusterTriggersToCheckTotalCount("eu").Return(int64(1), nil)
+
+			value, needSend, errActual := check.Check(now)
+			So(errActual, ShouldBeNil)
+			So(needSend, ShouldBeFalse)
+			So(value, ShouldEqual, 0)
+			So(check.lastSuccessfulCheck, ShouldResemble, now)
+		})
+
+		Convey("Check for notification", func() {
+			check.lastSuccessfulCheck = now - check.delay - 1
+
+			database.EXPECT().GetRemoteClusterChecksUpdatesCount("eu").Return(int64(0), nil)
+			database.EXPECT().GetRemoteClusterTriggersToCheckTotalCount("eu").Return(int64(1), nil)
+
+			value, needSend, errActual := check.Check(now)
+			So(errActual, ShouldBeNil)
+			So(needSend, ShouldBeTrue)
+			So(value, ShouldEqual, now-check.lastSuccessfulCheck)
+		})
+	})
+}
+
 func createGraphiteRemoteCheckerTest(t *testing.T) *remoteChecker {
 	mockCtrl := gomock.NewController(t)
 	logger, _ := logging.GetLogger("MetricDelay")
 
 	return GetRemoteChecker(120, logger, mock_moira_alert.NewMockDatabase(mockCtrl)).(*remoteChecker)
 }
+
+func createRemoteClusterCheckerTest(t *testing.T) *remoteChecker {
+	mockCtrl := gomock.NewController(t)
+	logger, _ := logging.GetLogger("MetricDelay")
+
+	return GetRemoteClusterChecker("eu", 120, logger, mock_moira_alert.NewMockDatabase(mockCtrl)).(*remoteChecker)
+}
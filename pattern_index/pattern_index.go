@@ -0,0 +1,270 @@
+package patternindex
+
+import (
+	"path"
+	"strings"
+
+	"github.com/moira-alert/moira"
+)
+
+const (
+	asteriskPart   = "*"
+	globCharacters = "*?["
+)
+
+// PatternNode contains pattern node
+// Children are indexed by kind: exact (including braces expanded to exact values), single asterisk and other globs.
+// Globs are indexed by their literal prefix in character trie, so matching of metric part takes one map lookup
+// and check of globs which literal prefix is a prefix of the metric part only.
+// Square brackets are character classes as in graphite, e.g. "host[12]" matches "host1" and "host2" but not "host[12]"
+type PatternNode struct {
+	Children   []*PatternNode
+	Part       string
+	Prefix     string
+	InnerParts []string
+	Terminal   bool
+
+	childrenByPart map[string]*PatternNode
+	exactChildren  map[string][]*PatternNode
+	asteriskChild  *PatternNode
+	globChildren   *globTrieNode
+}
+
+// globTrieNode is node of character trie of glob literal prefixes
+type globTrieNode struct {
+	children map[byte]*globTrieNode
+	globs    []globTrieEntry
+}
+
+// globTrieEntry is glob inner part of pattern node which literal prefix ends in trie node
+type globTrieEntry struct {
+	glob string
+	node *PatternNode
+}
+
+// PatternIndex helps to index patterns and allows to match them by metric
+type PatternIndex struct {
+	Root   *PatternNode
+	Logger moira.Logger
+}
+
+// NewPatternIndex creates new PatternIndex using patterns
+func NewPatternIndex(logger moira.Logger, patterns []string) *PatternIndex {
+	root := newPatternNode("", "")
+
+	for _, pattern := range patterns {
+		currentNode := root
+		parts := strings.Split(pattern, ".")
+		if hasEmptyParts(parts) {
+			logger.Warningf("Pattern %s is ignored because it contains an empty part", pattern)
+			continue
+		}
+		for _, part := range parts {
+			currentNode = currentNode.getOrAddChild(part)
+		}
+		currentNode.Terminal = true
+	}
+
+	return &PatternIndex{Logger: logger, Root: root}
+}
+
+func newPatternNode(part string, prefix string) *PatternNode {
+	return &PatternNode{
+		Part:           part,
+		Prefix:         prefix,
+		childrenByPart: make(map[string]*PatternNode),
+		exactChildren:  make(map[string][]*PatternNode),
+	}
+}
+
+func (node *PatternNode) getOrAddChild(part string) *PatternNode {
+	if child, ok := node.childrenByPart[part]; ok {
+		return child
+	}
+
+	prefix := part
+	if node.Prefix != "" {
+		prefix = node.Prefix + "." + part
+	}
+	child := newPatternNode(part, prefix)
+	node.childrenByPart[part] = child
+	node.Children = append(node.Children, child)
+
+	switch {
+	case part == asteriskPart:
+		node.asteriskChild = child
+	case !strings.ContainsAny(part, "{"+globCharacters):
+		node.exactChildren[part] = append(node.exactChildren[part], child)
+	default:
+		child.InnerParts = expandBraces(part)
+		if !containsGlob(child.InnerParts) {
+			for _, innerPart := range child.InnerParts {
+				node.exactChildren[innerPart] = append(node.exactChildren[innerPart], child)
+			}
+			break
+		}
+		if node.globChildren == nil {
+			node.globChildren = &globTrieNode{}
+		}
+		for i, innerPart := range child.InnerParts {
+			// Graphite negates character class with exclamation mark, path.Match does it with caret
+			child.InnerParts[i] = strings.Replace(innerPart, "[!", "[^", -1)
+			node.globChildren.add(child.InnerParts[i], child)
+		}
+	}
+	return child
+}
+
+// add adds glob to trie node of glob literal prefix
+func (trie *globTrieNode) add(glob string, node *PatternNode) {
+	literalPrefixLength := strings.IndexAny(glob, globCharacters)
+	if literalPrefixLength < 0 {
+		literalPrefixLength = len(glob)
+	}
+	current := trie
+	for i := 0; i < literalPrefixLength; i++ {
+		if current.children == nil {
+			current.children = make(map[byte]*globTrieNode)
+		}
+		child, ok := current.children[glob[i]]
+		if !ok {
+			child = &globTrieNode{}
+			current.children[glob[i]] = child
+		}
+		current = child
+	}
+	current.globs = append(current.globs, globTrieEntry{glob: glob, node: node})
+}
+
+// match appends to nodes pattern nodes which globs match metric part
+// Only globs with literal prefix which is a prefix of metric part are checked
+func (trie *globTrieNode) match(part string, nodes []*PatternNode) []*PatternNode {
+	from := len(nodes)
+	current := trie
+	for i := 0; current != nil; i++ {
+		for _, entry := range current.globs {
+			// Pattern node with braces has several globs, but is matched once
+			if containsNode(nodes[from:], entry.node) {
+				continue
+			}
+			if match, _ := path.Match(entry.glob, part); match {
+				nodes = append(nodes, entry.node)
+			}
+		}
+		if i == len(part) {
+			break
+		}
+		current = current.children[part[i]]
+	}
+	return nodes
+}
+
+// MatchPatterns allows to match pattern by metric
+func (source *PatternIndex) MatchPatterns(metric string) []string {
+	currentLevel := []*PatternNode{source.Root}
+	var index int
+	for i, c := range metric {
+		if c == '.' {
+			part := metric[index:i]
+
+			if len(part) == 0 {
+				source.Logger.Warningf("Metric %s is ignored, because it contains empty parts", metric)
+				return []string{}
+			}
+
+			index = i + 1
+
+			currentLevel = findPart(part, currentLevel)
+			if len(currentLevel) == 0 {
+				return []string{}
+			}
+		}
+	}
+
+	part := metric[index:]
+	currentLevel = findPart(part, currentLevel)
+	if len(currentLevel) == 0 {
+		return []string{}
+	}
+
+	matched := make([]string, 0, len(currentLevel))
+	for _, node := range currentLevel {
+		if node.Terminal {
+			matched = append(matched, node.Prefix)
+		}
+	}
+
+	return matched
+}
+
+func findPart(part string, currentLevel []*PatternNode) []*PatternNode {
+	nextLevel := make([]*PatternNode, 0, 5)
+
+	for _, node := range currentLevel {
+		nextLevel = append(nextLevel, node.exactChildren[part]...)
+		if node.asteriskChild != nil {
+			nextLevel = append(nextLevel, node.asteriskChild)
+		}
+		if node.globChildren != nil {
+			nextLevel = node.globChildren.match(part, nextLevel)
+		}
+	}
+	return nextLevel
+}
+
+// expandBraces returns all distinct variants of pattern part with expanded braces, e.g. "a{b,c}d{e,f}" gives "abde", "abdf", "acde" and "acdf"
+// Unpaired brace is kept as is
+func expandBraces(part string) []string {
+	open := strings.IndexByte(part, '{')
+	if open < 0 {
+		return []string{part}
+	}
+	closing := strings.IndexByte(part[open:], '}')
+	if closing < 0 {
+		return []string{part}
+	}
+	closing += open
+
+	prefix := part[:open]
+	suffixes := expandBraces(part[closing+1:])
+	variants := strings.Split(part[open+1:closing], ",")
+	expanded := make([]string, 0, len(variants)*len(suffixes))
+	seen := make(map[string]bool, len(variants)*len(suffixes))
+	for _, variant := range variants {
+		for _, suffix := range suffixes {
+			innerPart := prefix + variant + suffix
+			if !seen[innerPart] {
+				seen[innerPart] = true
+				expanded = append(expanded, innerPart)
+			}
+		}
+	}
+	return expanded
+}
+
+func containsGlob(parts []string) bool {
+	for _, part := range parts {
+		if strings.ContainsAny(part, globCharacters) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsNode(nodes []*PatternNode, node *PatternNode) bool {
+	for _, existingNode := range nodes {
+		if existingNode == node {
+			return true
+		}
+	}
+	return false
+}
+
+func hasEmptyParts(parts []string) bool {
+	for _, part := range parts {
+		if part == "" {
+			return true
+		}
+	}
+	return false
+}
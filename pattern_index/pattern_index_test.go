@@ -1,4 +1,4 @@
-package filter
+package patternindex
 
 import (
 	"testing"
@@ -24,6 +24,15 @@ func TestPatternIndex(t *testing.T) {
 			"Complex.*{one,two,three}suf*.pattern",
 			"Question.?at_begin",
 			"Question.at_the_end?",
+			"Class.host[12]",
+			"Class.[!a-y]*",
+			"Brackets.{a,b}-{c,d}",
+			"Brackets.{a*,b}.pattern",
+			"Brackets.{a,a}.pattern",
+			"Brackets.b.pattern",
+			"Prefix.ab*",
+			"Prefix.abc?",
+			"Prefix.{abd,x}*",
 		}
 
 		index := NewPatternIndex(logger, patterns)
@@ -47,12 +56,30 @@ func TestPatternIndex(t *testing.T) {
 			{"Complex.anything.pattern", []string{"Complex.*.*"}},
 			{"Question.1at_begin", []string{"Question.?at_begin"}},
 			{"Question.at_the_end2", []string{"Question.at_the_end?"}},
+			{"Class.host1", []string{"Class.host[12]"}},
+			{"Class.host2", []string{"Class.host[12]"}},
+			{"Class.zebra", []string{"Class.[!a-y]*"}},
+			{"Brackets.a-c", []string{"Brackets.{a,b}-{c,d}"}},
+			{"Brackets.b-d", []string{"Brackets.{a,b}-{c,d}"}},
+			{"Brackets.anything.pattern", []string{"Brackets.{a*,b}.pattern"}},
+			{"Brackets.a.pattern", []string{"Brackets.{a,a}.pattern", "Brackets.{a*,b}.pattern"}},
+			{"Brackets.b.pattern", []string{"Brackets.b.pattern", "Brackets.{a*,b}.pattern"}},
 			{"Two.dots..together", []string{}},
+			{"Class.host3", []string{}},
+			{"Class.host", []string{}},
+			{"Class.zz", []string{"Class.[!a-y]*"}},
+			{"Class.apple", []string{}},
+			{"Brackets.a-b", []string{}},
 			{"Simple.notmatching.pattern", []string{}},
 			{"Star.nothing", []string{}},
 			{"Bracket.one.nothing", []string{}},
 			{"Bracket.nothing.pattern", []string{}},
 			{"Complex.prefixonesuffix", []string{}},
+			{"Class.host[12]", []string{}},
+			{"Prefix.abcd", []string{"Prefix.ab*", "Prefix.abc?"}},
+			{"Prefix.abde", []string{"Prefix.ab*", "Prefix.{abd,x}*"}},
+			{"Prefix.xyz", []string{"Prefix.{abd,x}*"}},
+			{"Prefix.a", []string{}},
 		}
 
 		for _, testCase := range testCases {
@@ -61,3 +88,13 @@ func TestPatternIndex(t *testing.T) {
 		}
 	})
 }
+
+func TestExpandBraces(t *testing.T) {
+	Convey("Should expand all braces of pattern part", t, func() {
+		So(expandBraces("simple"), ShouldResemble, []string{"simple"})
+		So(expandBraces("pr{one,two}suf"), ShouldResemble, []string{"pronesuf", "prtwosuf"})
+		So(expandBraces("{a,b}-{c,d}"), ShouldResemble, []string{"a-c", "a-d", "b-c", "b-d"})
+		So(expandBraces("{a,a,}"), ShouldResemble, []string{"a", ""})
+		So(expandBraces("unpaired{brace"), ShouldResemble, []string{"unpaired{brace"})
+	})
+}
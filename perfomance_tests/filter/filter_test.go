@@ -15,6 +15,7 @@ import (
 	logging "github.com/moira-alert/moira/logging/zerolog_adapter"
 	"github.com/moira-alert/moira/metrics"
 	mock_moira_alert "github.com/moira-alert/moira/mock/moira-alert"
+	patternindex "github.com/moira-alert/moira/pattern_index"
 )
 
 func BenchmarkProcessIncomingMetric(b *testing.B) {
@@ -43,7 +44,7 @@ func BenchmarkProcessIncomingMetric(b *testing.B) {
 	logger, _ := logging.GetLogger("Benchmark")
 
 	database.EXPECT().GetPatterns().Return(patterns, nil)
-	patternsStorage, err := filter.NewPatternStorage(database, filterMetrics, logger)
+	patternsStorage, err := filter.NewPatternStorage(&filter.Config{}, database, filterMetrics, logger)
 	if err != nil {
 		b.Errorf("Can not create new cache storage %s", err)
 	}
@@ -62,7 +63,7 @@ func generateMetrics(patterns *filter.PatternStorage, count int) []string {
 	for i < count {
 		parts := make([]string, 0, 16)
 
-		patternTree := patterns.PatternIndex.Load().(*filter.PatternIndex).Root
+		patternTree := patterns.PatternIndex.Load().(*patternindex.PatternIndex).Root
 		node := patternTree.Children[rand.Intn(len(patternTree.Children))]
 		matched := rand.Float64() < 0.02
 		level := float64(0)
@@ -0,0 +1,97 @@
+package filter
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/moira-alert/moira/filter"
+	logging "github.com/moira-alert/moira/logging/zerolog_adapter"
+	"github.com/moira-alert/moira/metrics"
+	mock_moira_alert "github.com/moira-alert/moira/mock/moira-alert"
+	patternindex "github.com/moira-alert/moira/pattern_index"
+)
+
+const largePatternsCount = 100000
+
+func BenchmarkNewPatternIndex100k(b *testing.B) {
+	logger, _ := logging.GetLogger("Benchmark")
+	patterns := generatePatterns(largePatternsCount)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		patternindex.NewPatternIndex(logger, patterns)
+	}
+}
+
+func BenchmarkMatchPatterns100k(b *testing.B) {
+	logger, _ := logging.GetLogger("Benchmark")
+	index := patternindex.NewPatternIndex(logger, generatePatterns(largePatternsCount))
+	testMetrics := generateMetricNames(largePatternsCount, b.N)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		index.MatchPatterns(testMetrics[i])
+	}
+}
+
+func BenchmarkProcessIncomingMetric100k(b *testing.B) {
+	filterMetrics := metrics.ConfigureFilterMetrics(metrics.NewDummyRegistry())
+
+	mockCtrl := gomock.NewController(b)
+	database := mock_moira_alert.NewMockDatabase(mockCtrl)
+	database.EXPECT().AllowStale().AnyTimes().Return(database)
+	logger, _ := logging.GetLogger("Benchmark")
+
+	database.EXPECT().GetPatterns().Return(generatePatterns(largePatternsCount), nil)
+	patternsStorage, err := filter.NewPatternStorage(&filter.Config{}, database, filterMetrics, logger)
+	if err != nil {
+		b.Errorf("Can not create new cache storage %s", err)
+	}
+	testMetricsLines := generateMetrics(patternsStorage, b.N)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		patternsStorage.ProcessIncomingMetric([]byte(testMetricsLines[i]))
+	}
+}
+
+// generatePatterns returns patterns like "service1.host[0-4]*.{metric1,metric2}.count" with exact, asterisk, brace and character class nodes
+func generatePatterns(count int) []string {
+	random := rand.New(rand.NewSource(1))
+	patterns := make([]string, 0, count)
+	for i := 0; i < count; i++ {
+		var hosts, metric string
+		switch random.Intn(4) { //nolint
+		case 0:
+			hosts = fmt.Sprintf("host%d", random.Intn(100))
+		case 1:
+			hosts = "*"
+		case 2:
+			hosts = "host[0-4]*"
+		default:
+			hosts = fmt.Sprintf("{host%d,host%d}", random.Intn(100), random.Intn(100))
+		}
+		switch random.Intn(3) { //nolint
+		case 0:
+			metric = fmt.Sprintf("metric%d", i)
+		case 1:
+			metric = fmt.Sprintf("{metric%d,metric%d}", i, random.Intn(count))
+		default:
+			metric = fmt.Sprintf("metric%d*", i)
+		}
+		patterns = append(patterns, fmt.Sprintf("service%d.%s.%s.count", i%1000, hosts, metric))
+	}
+	return patterns
+}
+
+func generateMetricNames(patternsCount int, count int) []string {
+	random := rand.New(rand.NewSource(2))
+	metricNames := make([]string, 0, count)
+	for i := 0; i < count; i++ {
+		metricNames = append(metricNames, fmt.Sprintf("service%d.host%d.metric%d.count",
+			random.Intn(1000), random.Intn(100), random.Intn(patternsCount)))
+	}
+	return metricNames
+}
@@ -20,6 +20,7 @@ type Event struct {
 	MetricElements []string
 	Timestamp      int64
 	Value          *float64
+	Values         map[string]float64
 	State          string
 }
 
@@ -39,6 +40,32 @@ func (event Event) TimestampIncrease(second int64) int64 {
 	return event.Timestamp + second
 }
 
+// TimeLeft returns approximate duration of number of seconds stored in event value with given name,
+// e.g. {{ .TimeLeft "forecast" }} gives "~3h" for forecast trigger
+func (event Event) TimeLeft(valueName string) string {
+	value, ok := event.Values[valueName]
+	if !ok {
+		return ""
+	}
+	return FormatTimeLeft(int64(value))
+}
+
+// FormatTimeLeft returns approximate human readable duration of given number of seconds, e.g. "~3h" for 10810
+func FormatTimeLeft(seconds int64) string {
+	const day = 24 * time.Hour
+	duration := time.Duration(seconds) * time.Second
+	switch {
+	case duration >= day:
+		return fmt.Sprintf("~%dd", duration.Round(day)/day)
+	case duration >= time.Hour:
+		return fmt.Sprintf("~%dh", duration.Round(time.Hour)/time.Hour)
+	case duration >= time.Minute:
+		return fmt.Sprintf("~%dm", duration.Round(time.Minute)/time.Minute)
+	default:
+		return "<1m"
+	}
+}
+
 type trigger struct {
 	Name string `json:"name"`
 }
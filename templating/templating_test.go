@@ -101,6 +101,18 @@ https://grafana.yourhost.com/some-dashboard`,
 			})
 		})
 
+		Convey("Test method time left of value", func() {
+			eventsWithValues := []Event{
+				{Metric: "1", Timestamp: testUnixTime, Values: map[string]float64{"forecast": 10810}},
+				{Metric: "2", Timestamp: testUnixTime},
+			}
+			Desc = "{{ range .Events }}{{ .TimeLeft \"forecast\" }} | {{ end }}"
+
+			expected, err := Populate(Name, Desc, eventsWithValues)
+			So(err, ShouldBeNil)
+			So("~3h |  |", ShouldEqual, expected)
+		})
+
 		Convey("Bad functions", func() {
 			var timeOffset int64 = 300
 
@@ -182,3 +194,12 @@ https://grafana.yourhost.com/some-dashboard`,
 		})
 	})
 }
+
+func TestFormatTimeLeft(t *testing.T) {
+	Convey("Should format seconds as approximate duration", t, func() {
+		So(FormatTimeLeft(30), ShouldEqual, "<1m")
+		So(FormatTimeLeft(150), ShouldEqual, "~3m")
+		So(FormatTimeLeft(10810), ShouldEqual, "~3h")
+		So(FormatTimeLeft(3*86400+7200), ShouldEqual, "~3d")
+	})
+}